@@ -0,0 +1,114 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package service
+
+import (
+	"context"
+	stderrors "errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/bborbe/errors"
+	libsentry "github.com/bborbe/sentry"
+	"github.com/getsentry/sentry-go"
+)
+
+// AdditionalSentryDSN is a secondary Sentry project receiving the exceptions matching its filter.
+type AdditionalSentryDSN struct {
+	DSN string
+	// Filter selects the exceptions of the project, nil matches all.
+	Filter func(err error) bool
+}
+
+// newAdditionalSentryClients returns a client for each additional DSN with the client options of Main.
+// Each client gets its own failure detector over the given transport, so a deleted additional project
+// only disables its own client. The detectors have no metric, the disabled gauge belongs to the primary client.
+func newAdditionalSentryClients(
+	ctx context.Context,
+	dsns []AdditionalSentryDSN,
+	transport http.RoundTripper,
+	options Options,
+) ([]filteredSentryClient, error) {
+	result := make([]filteredSentryClient, 0, len(dsns))
+	for _, dsn := range dsns {
+		detector := newSentryFailureDetector(transport, options.SentryFailureThreshold, nil, options.Logger)
+		clientOptions, err := NewSentryClientOptions(ctx, dsn.DSN, detector, options)
+		if err != nil {
+			return nil, errors.Wrapf(ctx, err, "build additional Sentry client options failed")
+		}
+		client, err := newSentryClient(ctx, *clientOptions, options.Logger, options.ExcludeErrors...)
+		if err != nil {
+			return nil, errors.Wrapf(ctx, err, "setting up additional Sentry failed")
+		}
+		result = append(result, filteredSentryClient{
+			client: newDegradingSentryClient(client, detector, options.Logger),
+			filter: dsn.Filter,
+		})
+	}
+	return result, nil
+}
+
+type filteredSentryClient struct {
+	client libsentry.Client
+	filter func(err error) bool
+}
+
+// matches reports whether the client captures err, a nil filter matches all errors.
+func (f filteredSentryClient) matches(err error) bool {
+	return f.filter == nil || f.filter(err)
+}
+
+// newFanOutSentryClient returns a client that captures each exception with the primary client
+// and additionally with every client whose filter matches. Messages only go to the primary client.
+func newFanOutSentryClient(primary libsentry.Client, clients []filteredSentryClient) libsentry.Client {
+	return &fanOutSentryClient{
+		Client:  primary,
+		clients: clients,
+	}
+}
+
+type fanOutSentryClient struct {
+	libsentry.Client
+	clients []filteredSentryClient
+}
+
+// CaptureException returns the event ID of the primary client.
+func (f *fanOutSentryClient) CaptureException(err error, hint *sentry.EventHint, scope sentry.EventModifier) *sentry.EventID {
+	eventID := f.Client.CaptureException(err, hint, scope)
+	for _, client := range f.clients {
+		if client.matches(err) {
+			client.client.CaptureException(err, hint, scope)
+		}
+	}
+	return eventID
+}
+
+// Flush flushes all clients concurrently within the timeout.
+func (f *fanOutSentryClient) Flush(timeout time.Duration) bool {
+	var wg sync.WaitGroup
+	results := make([]bool, len(f.clients))
+	for i, client := range f.clients {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = client.client.Flush(timeout)
+		}()
+	}
+	flushed := f.Client.Flush(timeout)
+	wg.Wait()
+	for _, result := range results {
+		flushed = flushed && result
+	}
+	return flushed
+}
+
+func (f *fanOutSentryClient) Close() error {
+	errs := []error{f.Client.Close()}
+	for _, client := range f.clients {
+		errs = append(errs, client.client.Close())
+	}
+	return stderrors.Join(errs...)
+}
@@ -0,0 +1,142 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package service_test
+
+import (
+	"context"
+	stderrors "errors"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	libsentry "github.com/bborbe/sentry"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/bborbe/service"
+	"github.com/bborbe/service/mocks"
+	"github.com/bborbe/service/servicetest"
+)
+
+var _ = Describe("FanOutSentryClient", func() {
+	var primary *mocks.SentryClient
+	var infra *mocks.SentryClient
+	var client libsentry.Client
+	var infraErr error
+	BeforeEach(func() {
+		primary = &mocks.SentryClient{}
+		infra = &mocks.SentryClient{}
+		infraErr = stderrors.New("database down")
+		client = service.NewFanOutSentryClient(primary, []service.FilteredSentryClient{
+			service.NewFilteredSentryClient(infra, func(err error) bool {
+				return stderrors.Is(err, infraErr)
+			}),
+		})
+	})
+	It("captures a matching error to both clients", func() {
+		client.CaptureException(infraErr, nil, nil)
+		Expect(primary.CaptureExceptionCallCount()).To(Equal(1))
+		Expect(infra.CaptureExceptionCallCount()).To(Equal(1))
+	})
+	It("captures other errors only to the primary client", func() {
+		client.CaptureException(stderrors.New("invalid input"), nil, nil)
+		Expect(primary.CaptureExceptionCallCount()).To(Equal(1))
+		Expect(infra.CaptureExceptionCallCount()).To(Equal(0))
+	})
+	It("sends messages only to the primary client", func() {
+		client.CaptureMessage("banana", nil, nil)
+		Expect(primary.CaptureMessageCallCount()).To(Equal(1))
+		Expect(infra.CaptureMessageCallCount()).To(Equal(0))
+	})
+	It("flushes all clients", func() {
+		primary.FlushReturns(true)
+		infra.FlushReturns(false)
+		Expect(client.Flush(time.Second)).To(BeFalse())
+		Expect(primary.FlushCallCount()).To(Equal(1))
+		Expect(infra.FlushCallCount()).To(Equal(1))
+	})
+	It("closes all clients", func() {
+		infra.CloseReturns(stderrors.New("close failed"))
+		Expect(client.Close()).To(MatchError("close failed"))
+		Expect(primary.CloseCallCount()).To(Equal(1))
+		Expect(infra.CloseCallCount()).To(Equal(1))
+	})
+	It("captures all errors to a client without filter", func() {
+		all := &mocks.SentryClient{}
+		client = service.NewFanOutSentryClient(primary, []service.FilteredSentryClient{
+			service.NewFilteredSentryClient(all, nil),
+		})
+		client.CaptureException(stderrors.New("invalid input"), nil, nil)
+		Expect(primary.CaptureExceptionCallCount()).To(Equal(1))
+		Expect(all.CaptureExceptionCallCount()).To(Equal(1))
+	})
+})
+
+var _ = Describe("NewAdditionalSentryClients", func() {
+	It("disables only the additional client rejected by its project", func() {
+		var mux sync.Mutex
+		var requests int
+		roundTripper := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			mux.Lock()
+			defer mux.Unlock()
+			requests++
+			return &http.Response{
+				StatusCode: http.StatusForbidden,
+				Body:       io.NopCloser(strings.NewReader("")),
+				Header:     http.Header{},
+				Request:    req,
+			}, nil
+		})
+		options := service.NewOptions(service.WithRegisterer(nil), service.WithSentryFailureThreshold(1))
+		clients, err := service.NewAdditionalSentryClients(context.Background(), []service.AdditionalSentryDSN{
+			{DSN: "https://key@sentry.example.com/2"},
+		}, roundTripper, options)
+		Expect(err).To(BeNil())
+		Expect(clients).To(HaveLen(1))
+		client := clients[0].Client()
+
+		Expect(client.CaptureException(stderrors.New("banana"), nil, nil)).NotTo(BeNil())
+		client.Flush(time.Second)
+		Expect(client.CaptureException(stderrors.New("banana"), nil, nil)).To(BeNil())
+		client.Flush(time.Second)
+		mux.Lock()
+		defer mux.Unlock()
+		Expect(requests).To(Equal(1))
+	})
+})
+
+var _ = Describe("WithAdditionalSentryDSN", func() {
+	It("lets Main capture matching errors also with the additional client", func() {
+		transport := servicetest.NewSentryTransport()
+		app := &argsApplication{}
+		Expect(service.MainWithArgs(context.Background(), app, &app.SentryDSN, nil,
+			[]string{"-name=banana", "-fail"},
+			service.WithoutTimezoneOverride(),
+			service.WithRegisterer(nil),
+			service.WithSentryTransport(transport),
+			service.WithAdditionalSentryDSN("https://key@sentry.example.com/2", func(err error) bool {
+				return strings.Contains(err.Error(), "banana")
+			}),
+			service.WithAdditionalSentryDSN("https://key@sentry.example.com/3", func(err error) bool {
+				return false
+			}),
+		)).To(Equal(service.ExitCodeFailure))
+		Expect(transport.Events()).To(HaveLen(2))
+	})
+	It("captures all errors with the additional client of a nil filter", func() {
+		transport := servicetest.NewSentryTransport()
+		app := &argsApplication{}
+		Expect(service.MainWithArgs(context.Background(), app, &app.SentryDSN, nil,
+			[]string{"-name=banana", "-fail"},
+			service.WithoutTimezoneOverride(),
+			service.WithRegisterer(nil),
+			service.WithSentryTransport(transport),
+			service.WithAdditionalSentryDSN("https://key@sentry.example.com/2", nil),
+		)).To(Equal(service.ExitCodeFailure))
+		Expect(transport.Events()).To(HaveLen(2))
+	})
+})
@@ -0,0 +1,81 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package service
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/bborbe/run"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// AdminServerOptions configures the server of NewAdminServer.
+type AdminServerOptions struct {
+	// Gatherer the metrics are served from.
+	Gatherer prometheus.Gatherer
+	// Handlers are served in addition to the default endpoints, keyed by path.
+	Handlers map[string]http.Handler
+}
+
+type AdminServerOptionsFn func(options *AdminServerOptions)
+
+// NewAdminServerOptions returns the default options with the given fns applied.
+func NewAdminServerOptions(fns ...AdminServerOptionsFn) AdminServerOptions {
+	options := AdminServerOptions{
+		Gatherer: prometheus.DefaultGatherer,
+		Handlers: map[string]http.Handler{},
+	}
+	for _, fn := range fns {
+		fn(&options)
+	}
+	return options
+}
+
+// WithAdminHandler serves the handler on the given path of the admin server.
+func WithAdminHandler(path string, handler http.Handler) AdminServerOptionsFn {
+	return func(options *AdminServerOptions) {
+		options.Handlers[path] = handler
+	}
+}
+
+// WithAdminGatherer serves the metrics of the given gatherer instead of prometheus.DefaultGatherer.
+func WithAdminGatherer(gatherer prometheus.Gatherer) AdminServerOptionsFn {
+	return func(options *AdminServerOptions) {
+		options.Gatherer = gatherer
+	}
+}
+
+// NewAdminServer returns a func serving /healthz, /readiness and /metrics on listen until ctx is canceled.
+// The probes follow the HealthState of the context, e.g. set by WithHealthState.
+// Without HealthState both probes respond 200 while the server runs.
+func NewAdminServer(listen string, fns ...AdminServerOptionsFn) run.Func {
+	options := NewAdminServerOptions(fns...)
+	return func(ctx context.Context) error {
+		mux := http.NewServeMux()
+		if healthState := HealthStateFromContext(ctx); healthState != nil {
+			mux.Handle("/healthz", healthState.LiveHandler())
+			mux.Handle("/readiness", healthState.Handler())
+		} else {
+			mux.Handle("/healthz", healthHandler(alwaysHealthy))
+			mux.Handle("/readiness", healthHandler(alwaysHealthy))
+		}
+		mux.Handle("/metrics", metricsHandler(options.Gatherer))
+		for path, handler := range options.Handlers {
+			mux.Handle(path, handler)
+		}
+		return NewHTTPServer(listen, mux)(ctx)
+	}
+}
+
+func alwaysHealthy() bool {
+	return true
+}
+
+// metricsHandler serves the metrics of the gatherer with promhttp, which negotiates the format and compression.
+func metricsHandler(gatherer prometheus.Gatherer) http.Handler {
+	return promhttp.HandlerFor(gatherer, promhttp.HandlerOpts{})
+}
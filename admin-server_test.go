@@ -0,0 +1,124 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package service_test
+
+import (
+	"compress/gzip"
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/bborbe/service"
+)
+
+var _ = Describe("NewAdminServer", func() {
+	var ctx context.Context
+	var cancel context.CancelFunc
+	var addr string
+	var fns []service.AdminServerOptionsFn
+	var errCh chan error
+	BeforeEach(func() {
+		ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		Expect(err).To(BeNil())
+		addr = listener.Addr().String()
+		Expect(listener.Close()).To(BeNil())
+
+		registry := prometheus.NewRegistry()
+		counter := prometheus.NewCounter(prometheus.CounterOpts{Name: "banana_total", Help: "Bananas."})
+		registry.MustRegister(counter)
+		counter.Inc()
+		fns = []service.AdminServerOptionsFn{
+			service.WithAdminGatherer(registry),
+			service.WithAdminHandler("/debug", http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+				_, _ = resp.Write([]byte("debug"))
+			})),
+		}
+		errCh = make(chan error, 1)
+	})
+	AfterEach(func() {
+		cancel()
+		Eventually(errCh).Should(Receive(BeNil()))
+	})
+	get := func(path string) (int, string) {
+		resp, err := http.Get("http://" + addr + path)
+		Expect(err).To(BeNil())
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		Expect(err).To(BeNil())
+		return resp.StatusCode, string(body)
+	}
+	start := func() {
+		go func() {
+			errCh <- service.NewAdminServer(addr, fns...)(ctx)
+		}()
+		Eventually(func() error {
+			conn, err := net.Dial("tcp", addr)
+			if err != nil {
+				return err
+			}
+			return conn.Close()
+		}).Should(Succeed())
+	}
+	Context("without HealthState", func() {
+		BeforeEach(func() {
+			start()
+		})
+		It("serves the probes", func() {
+			code, _ := get("/healthz")
+			Expect(code).To(Equal(http.StatusOK))
+			code, _ = get("/readiness")
+			Expect(code).To(Equal(http.StatusOK))
+		})
+		It("serves the metrics", func() {
+			code, body := get("/metrics")
+			Expect(code).To(Equal(http.StatusOK))
+			Expect(body).To(ContainSubstring("banana_total 1"))
+		})
+		It("compresses the metrics if the scraper accepts gzip", func() {
+			req, err := http.NewRequest(http.MethodGet, "http://"+addr+"/metrics", nil)
+			Expect(err).To(BeNil())
+			req.Header.Set("Accept-Encoding", "gzip")
+			resp, err := http.DefaultTransport.RoundTrip(req)
+			Expect(err).To(BeNil())
+			defer resp.Body.Close()
+			Expect(resp.Header.Get("Content-Encoding")).To(Equal("gzip"))
+			reader, err := gzip.NewReader(resp.Body)
+			Expect(err).To(BeNil())
+			body, err := io.ReadAll(reader)
+			Expect(err).To(BeNil())
+			Expect(string(body)).To(ContainSubstring("banana_total 1"))
+		})
+		It("serves additional handlers", func() {
+			code, body := get("/debug")
+			Expect(code).To(Equal(http.StatusOK))
+			Expect(body).To(Equal("debug"))
+		})
+	})
+	Context("with HealthState", func() {
+		var healthState service.HealthState
+		BeforeEach(func() {
+			healthState = service.NewHealthState()
+			healthState.SetLive(true)
+			ctx = service.ContextWithHealthState(ctx, healthState)
+			start()
+		})
+		It("follows the HealthState", func() {
+			code, _ := get("/healthz")
+			Expect(code).To(Equal(http.StatusOK))
+			code, _ = get("/readiness")
+			Expect(code).To(Equal(http.StatusServiceUnavailable))
+			healthState.SetReady(true)
+			code, _ = get("/readiness")
+			Expect(code).To(Equal(http.StatusOK))
+		})
+	})
+})
@@ -0,0 +1,83 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package service
+
+import (
+	"context"
+	"math"
+	"math/rand/v2"
+	"time"
+
+	"github.com/bborbe/run"
+)
+
+// backoff re-invokes a failed func with a delay growing by Factor from Initial up to Max.
+// It implements the loop shared by RetryWithBackoff, Restart and NewRestartingService.
+type backoff struct {
+	Initial time.Duration
+	Factor  float64
+	Max     time.Duration
+	// Jitter randomizes each delay between half and the full delay.
+	Jitter bool
+	// Stop returns the error ending the retries after the given failed attempt or nil to retry.
+	// It is asked again if ctx is canceled during the delay and has to end the retries then.
+	Stop func(ctx context.Context, err error, attempt int) error
+	// Retrying logs the retry after the given failed attempt before the delay.
+	Retrying func(ctx context.Context, err error, attempt int, delay time.Duration)
+}
+
+// Run invokes fn until it returns nil or Stop ends the retries. The delay is measured
+// with the Clock of the context and interrupted as soon as ctx is canceled.
+func (b backoff) Run(ctx context.Context, fn run.Func) error {
+	for attempt := 1; ; attempt++ {
+		err := fn(ctx)
+		if err == nil {
+			return nil
+		}
+		if stopErr := b.Stop(ctx, err, attempt); stopErr != nil {
+			return stopErr
+		}
+		delay := backoffDelay(b.Initial, b.Factor, attempt-1, b.Max)
+		if b.Jitter {
+			delay = jitter(delay)
+		}
+		b.Retrying(ctx, err, attempt, delay)
+		timer := ClockFromContext(ctx).NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			if stopErr := b.Stop(ctx, err, attempt); stopErr != nil {
+				return stopErr
+			}
+			return err
+		case <-timer.C():
+		}
+	}
+}
+
+// backoffDelay returns initial * factor^attempt capped at max.
+// A factor below 1 keeps the delay constant, a max of zero disables the cap.
+func backoffDelay(initial time.Duration, factor float64, attempt int, max time.Duration) time.Duration {
+	if factor < 1 {
+		factor = 1
+	}
+	delay := float64(initial) * math.Pow(factor, float64(attempt))
+	if max > 0 && delay > float64(max) {
+		return max
+	}
+	if delay > math.MaxInt64 {
+		return time.Duration(math.MaxInt64)
+	}
+	return time.Duration(delay)
+}
+
+// jitter returns a random delay between half and the full given delay.
+func jitter(delay time.Duration) time.Duration {
+	half := delay / 2
+	if half <= 0 {
+		return delay
+	}
+	return half + rand.N(delay-half+1)
+}
@@ -0,0 +1,73 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+// Banner is the build information Main logs with the resolved configuration after parsing the arguments.
+type Banner struct {
+	Version   string
+	Commit    string
+	BuildDate string
+}
+
+// formatBanner returns a single line with the build information and the configuration of the app as JSON.
+func formatBanner(banner Banner, app interface{}) string {
+	config := &bytes.Buffer{}
+	encoder := json.NewEncoder(config)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(bannerConfig(app)); err != nil {
+		config.Reset()
+		config.WriteString("{}")
+	}
+	return fmt.Sprintf("banner version=%q commit=%q build_date=%q config=%s", banner.Version, banner.Commit, banner.BuildDate, bytes.TrimSpace(config.Bytes()))
+}
+
+// bannerConfig returns the fields of the app by name with the redaction of argument.Print:
+// display:"hidden" fields are skipped and display:"length" fields only show their length.
+// Funcs and channels are no configuration and skipped.
+func bannerConfig(app interface{}) map[string]string {
+	result := make(map[string]string)
+	value := reflect.ValueOf(app)
+	for value.Kind() == reflect.Ptr {
+		if value.IsNil() {
+			return result
+		}
+		value = value.Elem()
+	}
+	if value.Kind() != reflect.Struct {
+		return result
+	}
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Type().Field(i)
+		fieldValue := value.Field(i)
+		if !field.IsExported() || fieldValue.Kind() == reflect.Func || fieldValue.Kind() == reflect.Chan {
+			continue
+		}
+		display := field.Tag.Get("display")
+		if display == "hidden" {
+			continue
+		}
+		if display == "length" {
+			result[field.Name] = redactValue(display, fmt.Sprintf("%v", fieldValue.Interface()))
+			continue
+		}
+		if fieldValue.Kind() == reflect.Ptr || fieldValue.Kind() == reflect.Interface {
+			if fieldValue.IsZero() {
+				result[field.Name] = "<nil>"
+			} else {
+				result[field.Name] = fmt.Sprintf("%v", fieldValue.Elem())
+			}
+			continue
+		}
+		result[field.Name] = fmt.Sprintf("%v", fieldValue.Interface())
+	}
+	return result
+}
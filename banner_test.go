@@ -0,0 +1,71 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package service_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/bborbe/service"
+	"github.com/bborbe/service/servicetest"
+)
+
+var _ = Describe("FormatBanner", func() {
+	var banner service.Banner
+	BeforeEach(func() {
+		banner = service.Banner{Version: "v1.2.3", Commit: "abc123", BuildDate: "2026-10-16"}
+	})
+	It("formats the build information and redacted config", func() {
+		name := "banana"
+		Expect(service.FormatBanner(banner, &struct {
+			SentryDSN string `display:"length"`
+			Password  string `display:"hidden"`
+			Name      *string
+			Port      int
+			Missing   *int
+		}{
+			SentryDSN: "https://key@sentry.io/1",
+			Password:  "secret",
+			Name:      &name,
+			Port:      8080,
+		})).To(Equal(`banner version="v1.2.3" commit="abc123" build_date="2026-10-16" config={"Missing":"<nil>","Name":"banana","Port":"8080","SentryDSN":"length 23"}`))
+	})
+	It("formats an empty config for a nil app", func() {
+		Expect(service.FormatBanner(banner, nil)).To(HaveSuffix("config={}"))
+	})
+})
+
+var _ = Describe("WithBanner", func() {
+	var logger *recordingLogger
+	var app *mainApplication
+	var fns []service.OptionsFn
+	BeforeEach(func() {
+		logger = &recordingLogger{}
+		app = &mainApplication{
+			RunFunc: func(ctx context.Context) error {
+				return nil
+			},
+		}
+		fns = []service.OptionsFn{
+			service.WithoutTimezoneOverride(),
+			service.WithRegisterer(nil),
+			service.WithSentryTransport(servicetest.NewSentryTransport()),
+			service.WithLogger(logger),
+		}
+	})
+	It("logs the banner before the application starts", func() {
+		Expect(service.Main(context.Background(), app, &app.SentryDSN, nil,
+			append(fns, service.WithBanner("v1.2.3", "abc123", "2026-10-16"))...,
+		)).To(Equal(service.ExitCodeSuccess))
+		lines := logger.Lines()
+		Expect(lines).To(ContainElement(`I banner version="v1.2.3" commit="abc123" build_date="2026-10-16" config={"SentryDSN":""}`))
+	})
+	It("logs no banner by default", func() {
+		Expect(service.Main(context.Background(), app, &app.SentryDSN, nil, fns...)).To(Equal(service.ExitCodeSuccess))
+		Expect(logger.Lines()).NotTo(ContainElement(HavePrefix("I banner")))
+	})
+})
@@ -0,0 +1,155 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package service
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/golang/glog"
+)
+
+// DefaultBreadcrumbLimit is the number of log lines kept as breadcrumbs.
+const DefaultBreadcrumbLimit = 100
+
+// DefaultBreadcrumbLevel records warnings and errors as breadcrumbs.
+const DefaultBreadcrumbLevel BreadcrumbLevel = sentry.LevelWarning
+
+// BreadcrumbLevel is the minimum Sentry level of log lines recorded as breadcrumbs.
+//
+// glog severities map to Sentry levels as INFO => info, WARNING => warning, ERROR => error
+// and FATAL => fatal. glog V-levels only filter which INFO lines are written, all of them
+// are recorded with level info.
+type BreadcrumbLevel = sentry.Level
+
+// BreadcrumbRecorder parses the glog lines written to it and keeps the latest ones as breadcrumbs.
+type BreadcrumbRecorder interface {
+	io.Writer
+	// Breadcrumbs returns the recorded breadcrumbs, the oldest first.
+	Breadcrumbs() []*sentry.Breadcrumb
+}
+
+// NewBreadcrumbRecorder records log lines with at least the given level and keeps the latest limit ones.
+func NewBreadcrumbRecorder(minLevel BreadcrumbLevel, limit int) BreadcrumbRecorder {
+	return &breadcrumbRecorder{
+		minLevel: breadcrumbLevelRank(minLevel),
+		limit:    limit,
+	}
+}
+
+type breadcrumbRecorder struct {
+	minLevel int
+	limit    int
+
+	mux         sync.Mutex
+	pending     []byte
+	breadcrumbs []*sentry.Breadcrumb
+}
+
+func (b *breadcrumbRecorder) Write(p []byte) (int, error) {
+	b.mux.Lock()
+	defer b.mux.Unlock()
+	b.pending = append(b.pending, p...)
+	for {
+		index := bytes.IndexByte(b.pending, '\n')
+		if index < 0 {
+			return len(p), nil
+		}
+		b.record(string(b.pending[:index]))
+		b.pending = b.pending[index+1:]
+	}
+}
+
+// record a line in glog format like "W0102 15:04:05.000000 1234 file.go:12] message".
+func (b *breadcrumbRecorder) record(line string) {
+	if len(line) == 0 {
+		return
+	}
+	level, ok := glogSeverityLevel(line[0])
+	if !ok || breadcrumbLevelRank(level) < b.minLevel {
+		return
+	}
+	index := strings.Index(line, "] ")
+	if index < 0 {
+		return
+	}
+	b.breadcrumbs = append(b.breadcrumbs, &sentry.Breadcrumb{
+		Type:      "default",
+		Category:  "log",
+		Message:   line[index+2:],
+		Level:     level,
+		Timestamp: time.Now(),
+	})
+	if b.limit > 0 && len(b.breadcrumbs) > b.limit {
+		b.breadcrumbs = b.breadcrumbs[len(b.breadcrumbs)-b.limit:]
+	}
+}
+
+func (b *breadcrumbRecorder) Breadcrumbs() []*sentry.Breadcrumb {
+	b.mux.Lock()
+	defer b.mux.Unlock()
+	result := make([]*sentry.Breadcrumb, len(b.breadcrumbs))
+	copy(result, b.breadcrumbs)
+	return result
+}
+
+func glogSeverityLevel(severity byte) (sentry.Level, bool) {
+	switch severity {
+	case 'I':
+		return sentry.LevelInfo, true
+	case 'W':
+		return sentry.LevelWarning, true
+	case 'E':
+		return sentry.LevelError, true
+	case 'F':
+		return sentry.LevelFatal, true
+	default:
+		return "", false
+	}
+}
+
+func breadcrumbLevelRank(level sentry.Level) int {
+	switch level {
+	case sentry.LevelDebug:
+		return 0
+	case sentry.LevelInfo:
+		return 1
+	case sentry.LevelWarning:
+		return 2
+	case sentry.LevelError:
+		return 3
+	case sentry.LevelFatal:
+		return 4
+	default:
+		return 2
+	}
+}
+
+// teeStderr copies everything written to os.Stderr, which glog writes to with -logtostderr,
+// also to w until the returned restore is called.
+func teeStderr(w io.Writer) (func(), error) {
+	reader, writer, err := os.Pipe()
+	if err != nil {
+		return nil, err
+	}
+	stderr := os.Stderr
+	os.Stderr = writer
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_, _ = io.Copy(io.MultiWriter(stderr, w), reader)
+	}()
+	return func() {
+		glog.Flush()
+		os.Stderr = stderr
+		_ = writer.Close()
+		<-done
+	}, nil
+}
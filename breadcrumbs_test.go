@@ -0,0 +1,77 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package service_test
+
+import (
+	"context"
+	stderrors "errors"
+
+	"github.com/getsentry/sentry-go"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/bborbe/service"
+	"github.com/bborbe/service/mocks"
+)
+
+var _ = Describe("BreadcrumbRecorder", func() {
+	var recorder service.BreadcrumbRecorder
+	var minLevel service.BreadcrumbLevel
+	messages := func() []string {
+		var result []string
+		for _, breadcrumb := range recorder.Breadcrumbs() {
+			result = append(result, breadcrumb.Message)
+		}
+		return result
+	}
+	BeforeEach(func() {
+		minLevel = service.NewOptions().BreadcrumbLevel
+	})
+	JustBeforeEach(func() {
+		recorder = service.NewBreadcrumbRecorder(minLevel, 2)
+		_, err := recorder.Write([]byte("I1016 17:19:56.494676   18635 main.go:17] info message\n" +
+			"W1016 17:19:56.494676   18635 main.go:18] warning message\n" +
+			"E1016 17:19:56.494676   18635 main.go:19] error"))
+		Expect(err).To(BeNil())
+		_, err = recorder.Write([]byte(" message\ngoroutine 1 [running]:\n"))
+		Expect(err).To(BeNil())
+	})
+	It("excludes info logs by default", func() {
+		Expect(messages()).To(Equal([]string{"warning message", "error message"}))
+	})
+	It("maps the glog severity", func() {
+		breadcrumbs := recorder.Breadcrumbs()
+		Expect(breadcrumbs[0].Level).To(Equal(sentry.LevelWarning))
+		Expect(breadcrumbs[1].Level).To(Equal(sentry.LevelError))
+	})
+	Context("WithBreadcrumbLevel error", func() {
+		BeforeEach(func() {
+			minLevel = service.NewOptions(service.WithBreadcrumbLevel(sentry.LevelError)).BreadcrumbLevel
+		})
+		It("excludes warnings", func() {
+			Expect(messages()).To(Equal([]string{"error message"}))
+		})
+	})
+	Context("WithBreadcrumbLevel info", func() {
+		BeforeEach(func() {
+			minLevel = sentry.LevelInfo
+		})
+		It("keeps only the latest breadcrumbs", func() {
+			Expect(messages()).To(Equal([]string{"warning message", "error message"}))
+		})
+	})
+	It("attaches the breadcrumbs to captured exceptions", func() {
+		sentryClient := &mocks.SentryClient{}
+		app := &mocks.ServiceApplication{}
+		app.RunReturns(stderrors.New("banana"))
+		options := service.NewOptions()
+		options.BreadcrumbRecorder = recorder
+		_ = service.NewServiceWithOptions(sentryClient, app, options).Run(context.Background())
+		_, _, scope := sentryClient.CaptureExceptionArgsForCall(0)
+		event := scope.ApplyToEvent(&sentry.Event{}, nil, nil)
+		Expect(event.Breadcrumbs).To(HaveLen(2))
+		Expect(event.Breadcrumbs[1].Message).To(Equal("error message"))
+	})
+})
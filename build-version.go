@@ -0,0 +1,36 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package service
+
+import (
+	"runtime/debug"
+)
+
+// BuildVersion can be injected by the linker:
+//
+//	go build -ldflags "-X github.com/bborbe/service.BuildVersion=v1.2.3"
+var BuildVersion string
+
+// ReadBuildVersion returns BuildVersion if injected by the linker,
+// otherwise the version or VCS revision recorded in the build info of the binary.
+// It returns an empty string if nothing is known.
+func ReadBuildVersion() string {
+	if BuildVersion != "" {
+		return BuildVersion
+	}
+	buildInfo, ok := debug.ReadBuildInfo()
+	if !ok {
+		return ""
+	}
+	if version := buildInfo.Main.Version; version != "" && version != "(devel)" {
+		return version
+	}
+	for _, setting := range buildInfo.Settings {
+		if setting.Key == "vcs.revision" {
+			return setting.Value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,87 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package service
+
+import (
+	"context"
+	"io"
+	"sync"
+
+	"github.com/bborbe/errors"
+	libsentry "github.com/bborbe/sentry"
+	"github.com/getsentry/sentry-go"
+)
+
+// CloserGroup collects resources that are closed together on shutdown.
+type CloserGroup interface {
+	// Add the closer to the group.
+	Add(closer io.Closer)
+	// Close all added closers in reverse order and return their joined errors.
+	Close(ctx context.Context) error
+}
+
+// NewCloserGroup returns an empty CloserGroup.
+func NewCloserGroup() CloserGroup {
+	return &closerGroup{}
+}
+
+type closerGroup struct {
+	mux     sync.Mutex
+	closers []io.Closer
+}
+
+func (c *closerGroup) Add(closer io.Closer) {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	c.closers = append(c.closers, closer)
+}
+
+func (c *closerGroup) Close(ctx context.Context) error {
+	c.mux.Lock()
+	closers := c.closers
+	c.closers = nil
+	c.mux.Unlock()
+
+	var errs []error
+	for i := len(closers) - 1; i >= 0; i-- {
+		if err := closers[i].Close(); err != nil {
+			errs = append(errs, errors.Wrapf(ctx, err, "close %T failed", closers[i]))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+type closerGroupContextKey struct{}
+
+// ContextWithCloserGroup returns a context that RegisterCloser adds closers to the given group with.
+func ContextWithCloserGroup(ctx context.Context, closerGroup CloserGroup) context.Context {
+	return context.WithValue(ctx, closerGroupContextKey{}, closerGroup)
+}
+
+// RegisterCloser adds the closer to the CloserGroup of the context.
+// Main closes them in reverse registration order after the application stopped.
+func RegisterCloser(ctx context.Context, closer io.Closer) error {
+	closerGroup, ok := ctx.Value(closerGroupContextKey{}).(CloserGroup)
+	if !ok {
+		return errors.Errorf(ctx, "no CloserGroup in context")
+	}
+	closerGroup.Add(closer)
+	return nil
+}
+
+// closeCloserGroup logs and captures close errors, they do not fail the service.
+func closeCloserGroup(ctx context.Context, closerGroup CloserGroup, sentryClient libsentry.Client) {
+	if err := closerGroup.Close(ctx); err != nil {
+		LoggerFromContext(ctx).Warningf("close resources failed: %v", err)
+		sentryClient.CaptureException(
+			err,
+			&sentry.EventHint{
+				Context:           ctx,
+				OriginalException: err,
+			},
+			sentry.NewScope(),
+		)
+	}
+}
@@ -0,0 +1,62 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package service_test
+
+import (
+	"context"
+	stderrors "errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/bborbe/service"
+)
+
+type closerFunc func() error
+
+func (c closerFunc) Close() error {
+	return c()
+}
+
+var _ = Describe("CloserGroup", func() {
+	var ctx context.Context
+	var closerGroup service.CloserGroup
+	var closed []string
+	BeforeEach(func() {
+		closerGroup = service.NewCloserGroup()
+		ctx = service.ContextWithCloserGroup(context.Background(), closerGroup)
+		closed = nil
+	})
+	closer := func(name string, err error) closerFunc {
+		return func() error {
+			closed = append(closed, name)
+			return err
+		}
+	}
+	It("closes in reverse registration order", func() {
+		Expect(service.RegisterCloser(ctx, closer("first", nil))).To(Succeed())
+		Expect(service.RegisterCloser(ctx, closer("second", nil))).To(Succeed())
+		Expect(service.RegisterCloser(ctx, closer("third", nil))).To(Succeed())
+		Expect(closerGroup.Close(ctx)).To(Succeed())
+		Expect(closed).To(Equal([]string{"third", "second", "first"}))
+	})
+	It("closes all and returns the errors", func() {
+		Expect(service.RegisterCloser(ctx, closer("first", stderrors.New("banana")))).To(Succeed())
+		Expect(service.RegisterCloser(ctx, closer("second", nil))).To(Succeed())
+		err := closerGroup.Close(ctx)
+		Expect(err).NotTo(BeNil())
+		Expect(err.Error()).To(ContainSubstring("banana"))
+		Expect(closed).To(Equal([]string{"second", "first"}))
+	})
+	It("closes each closer only once", func() {
+		Expect(service.RegisterCloser(ctx, closer("first", nil))).To(Succeed())
+		Expect(closerGroup.Close(ctx)).To(Succeed())
+		Expect(closerGroup.Close(ctx)).To(Succeed())
+		Expect(closed).To(Equal([]string{"first"}))
+	})
+	It("fails to register without CloserGroup in context", func() {
+		Expect(service.RegisterCloser(context.Background(), closer("first", nil))).NotTo(Succeed())
+	})
+})
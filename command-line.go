@@ -0,0 +1,104 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package service
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+
+	"github.com/getsentry/sentry-go"
+)
+
+// CommandLineContextKey is the key of the Sentry context with the command line Main was invoked with.
+const CommandLineContextKey = "command_line"
+
+// DefaultSentryContextEnv are the env vars attached to Sentry events by default, e.g. set by the Kubernetes downward API.
+var DefaultSentryContextEnv = []string{"HOSTNAME", "POD_NAME", "POD_NAMESPACE", "NODE_NAME"}
+
+// commandLineContext returns the Sentry context with the redacted args and the allowed env vars that are set.
+func commandLineContext(app interface{}, args []string, envNames []string, lookupEnv func(string) (string, bool)) sentry.Context {
+	env := make(map[string]string)
+	for _, name := range envNames {
+		if value, ok := lookupEnv(name); ok {
+			env[name] = value
+		}
+	}
+	return sentry.Context{
+		"args": redactArgs(app, args),
+		"env":  env,
+	}
+}
+
+// redactArgs replaces the values of flags for fields tagged display:"hidden" with RedactedValue
+// and of fields tagged display:"length" with their length, like argument.Print.
+func redactArgs(app interface{}, args []string) []string {
+	secrets := secretArgs(app)
+	result := make([]string, len(args))
+	copy(result, args)
+	for i := 0; i < len(result); i++ {
+		arg := result[i]
+		if !strings.HasPrefix(arg, "-") || arg == "--" {
+			continue
+		}
+		name, value, hasValue := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+		display, ok := secrets[name]
+		if !ok {
+			continue
+		}
+		prefix := arg[:len(arg)-len(strings.TrimLeft(arg, "-"))]
+		if hasValue {
+			result[i] = prefix + name + "=" + redactValue(display, value)
+			continue
+		}
+		if i+1 < len(result) {
+			// value as separate arg
+			result[i+1] = redactValue(display, result[i+1])
+			i++
+		}
+	}
+	return result
+}
+
+func redactValue(display string, value string) string {
+	if display == "length" {
+		return fmt.Sprintf("length %d", len(value))
+	}
+	return RedactedValue
+}
+
+// secretArgs returns the display tag of all arg tagged secret fields of the app by arg name.
+// Bool flags are skipped, because they carry no value worth hiding.
+func secretArgs(app interface{}) map[string]string {
+	result := make(map[string]string)
+	value := reflect.ValueOf(app)
+	for value.Kind() == reflect.Ptr {
+		if value.IsNil() {
+			return result
+		}
+		value = value.Elem()
+	}
+	if value.Kind() != reflect.Struct {
+		return result
+	}
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Type().Field(i)
+		name, ok := field.Tag.Lookup("arg")
+		if !ok || !isSecret(field) || field.Type.Kind() == reflect.Bool {
+			continue
+		}
+		result[name] = field.Tag.Get("display")
+	}
+	return result
+}
+
+// osArgs returns the args of the process without the program name.
+func osArgs() []string {
+	if len(os.Args) < 2 {
+		return nil
+	}
+	return os.Args[1:]
+}
@@ -0,0 +1,100 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package service_test
+
+import (
+	"context"
+	stderrors "errors"
+
+	libsentry "github.com/bborbe/sentry"
+	"github.com/getsentry/sentry-go"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/bborbe/service"
+	"github.com/bborbe/service/servicetest"
+)
+
+type secretApplication struct {
+	SentryDSN string `required:"false" arg:"sentry-dsn" usage:"SentryDSN" display:"length"`
+	Password  string `required:"false" arg:"password" usage:"password" display:"hidden"`
+	Name      string `required:"false" arg:"name" usage:"name"`
+	Debug     bool   `required:"false" arg:"debug" usage:"debug" display:"hidden"`
+}
+
+func (s *secretApplication) Run(ctx context.Context, sentryClient libsentry.Client) error {
+	return stderrors.New("banana")
+}
+
+var _ = Describe("RedactArgs", func() {
+	var app *secretApplication
+	BeforeEach(func() {
+		app = &secretApplication{}
+	})
+	It("redacts hidden flag values", func() {
+		Expect(service.RedactArgs(app, []string{"-password=secret", "--password", "secret"})).To(Equal([]string{
+			"-password=" + service.RedactedValue,
+			"--password",
+			service.RedactedValue,
+		}))
+	})
+	It("replaces length flag values with their length", func() {
+		Expect(service.RedactArgs(app, []string{"-sentry-dsn=https://key@sentry.io/1"})).To(Equal([]string{
+			"-sentry-dsn=length 23",
+		}))
+	})
+	It("keeps other flags", func() {
+		Expect(service.RedactArgs(app, []string{"-name=banana", "-debug", "apple"})).To(Equal([]string{
+			"-name=banana",
+			"-debug",
+			"apple",
+		}))
+	})
+	It("does not change the given args", func() {
+		args := []string{"-password=secret"}
+		service.RedactArgs(app, args)
+		Expect(args).To(Equal([]string{"-password=secret"}))
+	})
+})
+
+var _ = Describe("CommandLineContext", func() {
+	It("adds only allowed env vars that are set", func() {
+		sentryContext := service.CommandLineContext(&secretApplication{}, nil, []string{"HOSTNAME", "POD_NAME"}, func(name string) (string, bool) {
+			switch name {
+			case "HOSTNAME":
+				return "pod-1", true
+			case "PASSWORD":
+				return "secret", true
+			}
+			return "", false
+		})
+		Expect(sentryContext["env"]).To(Equal(map[string]string{"HOSTNAME": "pod-1"}))
+	})
+})
+
+var _ = Describe("Main command line context", func() {
+	It("attaches the redacted command line to captured events", func() {
+		transport := servicetest.NewSentryTransport()
+		app := &secretApplication{}
+		Expect(service.MainWithArgs(context.Background(), app, &app.SentryDSN, nil,
+			[]string{"-password=secret", "-name=banana"},
+			service.WithoutTimezoneOverride(),
+			service.WithRegisterer(nil),
+			service.WithSentryTransport(transport),
+			service.WithSentryContextEnv(),
+		)).To(Equal(service.ExitCodeFailure))
+		var events []*sentry.Event
+		for _, event := range transport.Events() {
+			if event.Contexts[service.CommandLineContextKey] != nil {
+				events = append(events, event)
+			}
+		}
+		Expect(events).NotTo(BeEmpty())
+		Expect(events[0].Contexts[service.CommandLineContextKey]["args"]).To(Equal([]string{
+			"-password=" + service.RedactedValue,
+			"-name=banana",
+		}))
+	})
+})
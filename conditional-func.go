@@ -0,0 +1,27 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package service
+
+import (
+	"context"
+
+	"github.com/bborbe/run"
+)
+
+// ConditionalFunc runs fn only if enabled returns true at the time the func starts,
+// e.g. for a feature flag carried in the context. A disabled func returns nil immediately.
+// Run cancels its group once any func returns, so combine dormant funcs with RunUntilError or RunAll.
+func ConditionalFunc(enabled func(ctx context.Context) bool, fn run.Func) run.Func {
+	return func(ctx context.Context) error {
+		if !enabled(ctx) {
+			logger := LoggerFromContext(ctx)
+			if logger.V(2) {
+				logger.Infof("func disabled => skip")
+			}
+			return nil
+		}
+		return fn(ctx)
+	}
+}
@@ -0,0 +1,62 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package service_test
+
+import (
+	"context"
+	stderrors "errors"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/bborbe/service"
+)
+
+type featureFlagKey struct{}
+
+var _ = Describe("ConditionalFunc", func() {
+	var ctx context.Context
+	var called bool
+	var fn func(ctx context.Context) error
+	var enabled func(ctx context.Context) bool
+	BeforeEach(func() {
+		ctx = context.Background()
+		called = false
+		fn = func(ctx context.Context) error {
+			called = true
+			return stderrors.New("banana")
+		}
+		enabled = func(ctx context.Context) bool {
+			flag, _ := ctx.Value(featureFlagKey{}).(bool)
+			return flag
+		}
+	})
+	It("runs the func if enabled", func() {
+		err := service.ConditionalFunc(enabled, fn)(context.WithValue(ctx, featureFlagKey{}, true))
+		Expect(err).To(MatchError("banana"))
+		Expect(called).To(BeTrue())
+	})
+	It("returns nil without running the func if disabled", func() {
+		err := service.ConditionalFunc(enabled, fn)(ctx)
+		Expect(err).To(BeNil())
+		Expect(called).To(BeFalse())
+	})
+	It("keeps the other funcs of RunUntilError running if disabled", func() {
+		err := service.RunUntilError(ctx,
+			service.ConditionalFunc(enabled, fn),
+			func(ctx context.Context) error {
+				select {
+				case <-ctx.Done():
+					return ctx.Err()
+				case <-time.After(10 * time.Millisecond):
+					return nil
+				}
+			},
+		)
+		Expect(err).To(BeNil())
+		Expect(called).To(BeFalse())
+	})
+})
@@ -0,0 +1,76 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package service
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+// RedactedValue replaces the value of secrets in ConfigAsEnv.
+const RedactedValue = "REDACTED"
+
+// ConfigAsEnv returns all env tagged fields of the parsed app as KEY=VALUE pairs, e.g. for exec.Cmd.Env
+// of a child process. Fields without env tag and nil pointers are skipped.
+// Fields tagged display:"hidden" or display:"length" are secrets and replaced by RedactedValue
+// unless includeSecrets is set.
+func ConfigAsEnv(app interface{}, includeSecrets bool) []string {
+	value := reflect.ValueOf(app)
+	for value.Kind() == reflect.Ptr {
+		if value.IsNil() {
+			return nil
+		}
+		value = value.Elem()
+	}
+	if value.Kind() != reflect.Struct {
+		return nil
+	}
+	var result []string
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Type().Field(i)
+		name, ok := field.Tag.Lookup("env")
+		if !ok || name == "" || !field.IsExported() {
+			continue
+		}
+		formatted, ok := formatEnvValue(value.Field(i))
+		if !ok {
+			continue
+		}
+		if !includeSecrets && isSecret(field) {
+			formatted = RedactedValue
+		}
+		result = append(result, name+"="+formatted)
+	}
+	return result
+}
+
+func isSecret(field reflect.StructField) bool {
+	display := field.Tag.Get("display")
+	return display == "hidden" || display == "length"
+}
+
+func formatEnvValue(value reflect.Value) (string, bool) {
+	switch v := value.Interface().(type) {
+	case time.Duration:
+		return v.String(), true
+	case *float64:
+		if v == nil {
+			return "", false
+		}
+		return strconv.FormatFloat(*v, 'f', -1, 64), true
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), true
+	default:
+		if value.Kind() == reflect.Ptr {
+			if value.IsNil() {
+				return "", false
+			}
+			return formatEnvValue(value.Elem())
+		}
+		return fmt.Sprint(v), true
+	}
+}
@@ -0,0 +1,71 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package service_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/bborbe/service"
+)
+
+var _ = Describe("ConfigAsEnv", func() {
+	type application struct {
+		SentryDSN string        `required:"true" arg:"sentry-dsn" env:"SENTRY_DSN" display:"length"`
+		Password  string        `arg:"password" env:"PASSWORD" display:"hidden"`
+		Listen    string        `arg:"listen" env:"LISTEN"`
+		Debug     bool          `arg:"debug" env:"DEBUG"`
+		Workers   int           `arg:"workers" env:"WORKERS"`
+		Ratio     *float64      `arg:"ratio" env:"RATIO"`
+		Interval  time.Duration `arg:"interval" env:"INTERVAL"`
+		ArgOnly   string        `arg:"arg-only"`
+		Internal  string
+	}
+	var app *application
+	BeforeEach(func() {
+		app = &application{
+			SentryDSN: "https://key@sentry.example.com/1",
+			Password:  "secret",
+			Listen:    ":8080",
+			Debug:     true,
+			Workers:   4,
+			Interval:  90 * time.Second,
+			ArgOnly:   "skipped",
+			Internal:  "skipped",
+		}
+	})
+	It("includes secrets if requested", func() {
+		Expect(service.ConfigAsEnv(app, true)).To(Equal([]string{
+			"SENTRY_DSN=https://key@sentry.example.com/1",
+			"PASSWORD=secret",
+			"LISTEN=:8080",
+			"DEBUG=true",
+			"WORKERS=4",
+			"INTERVAL=1m30s",
+		}))
+	})
+	It("redacts secrets", func() {
+		env := service.ConfigAsEnv(app, false)
+		Expect(env).To(ContainElement("SENTRY_DSN=" + service.RedactedValue))
+		Expect(env).To(ContainElement("PASSWORD=" + service.RedactedValue))
+		Expect(env).To(ContainElement("LISTEN=:8080"))
+	})
+	It("skips fields without env tag", func() {
+		for _, entry := range service.ConfigAsEnv(app, true) {
+			Expect(entry).NotTo(ContainSubstring("skipped"))
+		}
+	})
+	It("formats set pointers", func() {
+		ratio := 0.25
+		app.Ratio = &ratio
+		Expect(service.ConfigAsEnv(app, true)).To(ContainElement("RATIO=0.25"))
+	})
+	It("formats durations parsable as duration", func() {
+		app.Interval = 1500 * time.Millisecond
+		Expect(service.ConfigAsEnv(app, true)).To(ContainElement("INTERVAL=1.5s"))
+	})
+})
@@ -0,0 +1,152 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/bborbe/errors"
+	"gopkg.in/yaml.v3"
+)
+
+// ConfigFlag is the flag that sets the path of the config file, e.g. -config=/etc/app.yaml.
+const ConfigFlag = "config"
+
+// configFileArgs returns the args with the values of the config file prepended as flags, so args, env vars and env files still override them.
+// The keys of the file are the arg tags of the app. The path of the -config flag wins over defaultPath.
+// A missing file is only an error if the path was set by flag. The -config flag is removed from the args
+// unless the app declares it itself. Without defaultPath the args are returned unchanged.
+func configFileArgs(ctx context.Context, app interface{}, args []string, defaultPath string) ([]string, error) {
+	if defaultPath == "" {
+		return args, nil
+	}
+	names := argNames(app)
+	path, explicit, rest := extractConfigFlag(args)
+	if names[ConfigFlag] {
+		rest = args
+	}
+	if !explicit {
+		path = defaultPath
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		if !explicit && stderrors.Is(err, fs.ErrNotExist) {
+			LoggerFromContext(ctx).Infof("config file %s not found => skip", path)
+			return rest, nil
+		}
+		return nil, errors.Wrapf(ctx, err, "read config file %s failed", path)
+	}
+	values, err := decodeConfigFile(ctx, path, content)
+	if err != nil {
+		return nil, err
+	}
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	result := make([]string, 0, len(keys)+len(rest))
+	for _, key := range keys {
+		if !names[key] {
+			return nil, errors.Errorf(ctx, "config file %s contains unknown key %s", path, key)
+		}
+		value, err := formatConfigValue(ctx, key, values[key])
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, "-"+key+"="+value)
+	}
+	return append(result, rest...), nil
+}
+
+func decodeConfigFile(ctx context.Context, path string, content []byte) (map[string]interface{}, error) {
+	values := make(map[string]interface{})
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		decoder := json.NewDecoder(bytes.NewReader(content))
+		decoder.UseNumber()
+		if err := decoder.Decode(&values); err != nil {
+			return nil, errors.Wrapf(ctx, err, "decode json config file %s failed", path)
+		}
+	case ".yaml", ".yml":
+		if err := yaml.Unmarshal(content, &values); err != nil {
+			return nil, errors.Wrapf(ctx, err, "decode yaml config file %s failed", path)
+		}
+	default:
+		return nil, errors.Errorf(ctx, "config file %s has unsupported format, use .json, .yaml or .yml", path)
+	}
+	return values, nil
+}
+
+func formatConfigValue(ctx context.Context, key string, value interface{}) (string, error) {
+	switch v := value.(type) {
+	case nil:
+		return "", nil
+	case map[string]interface{}, []interface{}:
+		return "", errors.Errorf(ctx, "config key %s has unsupported nested value", key)
+	default:
+		return fmt.Sprint(v), nil
+	}
+}
+
+// extractConfigFlag returns the value of the -config flag, whether it was given and the args without it.
+func extractConfigFlag(args []string) (string, bool, []string) {
+	var path string
+	var found bool
+	rest := make([]string, 0, len(args))
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" {
+			rest = append(rest, args[i:]...)
+			break
+		}
+		name, value, hasValue := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+		if !strings.HasPrefix(arg, "-") || name != ConfigFlag {
+			rest = append(rest, arg)
+			continue
+		}
+		found = true
+		if hasValue {
+			path = value
+			continue
+		}
+		if i+1 < len(args) {
+			path = args[i+1]
+			i++
+		}
+	}
+	return path, found, rest
+}
+
+// argNames returns the arg tags of the app.
+func argNames(app interface{}) map[string]bool {
+	result := make(map[string]bool)
+	value := reflect.ValueOf(app)
+	for value.Kind() == reflect.Ptr {
+		if value.IsNil() {
+			return result
+		}
+		value = value.Elem()
+	}
+	if value.Kind() != reflect.Struct {
+		return result
+	}
+	for i := 0; i < value.NumField(); i++ {
+		if name, ok := value.Type().Field(i).Tag.Lookup("arg"); ok {
+			result[name] = true
+		}
+	}
+	return result
+}
@@ -0,0 +1,133 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package service_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"time"
+
+	libsentry "github.com/bborbe/sentry"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/bborbe/service"
+	"github.com/bborbe/service/servicetest"
+)
+
+type configApplication struct {
+	SentryDSN string        `required:"false" arg:"sentry-dsn" usage:"SentryDSN"`
+	Name      string        `required:"true" arg:"name" usage:"name"`
+	Port      int           `required:"false" arg:"port" usage:"port" default:"8080"`
+	Timeout   time.Duration `required:"false" arg:"timeout" usage:"timeout"`
+}
+
+func (c *configApplication) Run(ctx context.Context, sentryClient libsentry.Client) error {
+	return nil
+}
+
+var _ = Describe("ConfigFileArgs", func() {
+	var ctx context.Context
+	var dir string
+	var app *configApplication
+	BeforeEach(func() {
+		ctx = context.Background()
+		dir = GinkgoT().TempDir()
+		app = &configApplication{}
+	})
+	write := func(name string, content string) string {
+		path := filepath.Join(dir, name)
+		Expect(os.WriteFile(path, []byte(content), 0600)).To(Succeed())
+		return path
+	}
+	It("prepends the values of a yaml file", func() {
+		path := write("config.yaml", "name: banana\nport: 9090\ntimeout: 30s\n")
+		Expect(service.ConfigFileArgs(ctx, app, []string{"-name=apple"}, path)).To(Equal([]string{
+			"-name=banana",
+			"-port=9090",
+			"-timeout=30s",
+			"-name=apple",
+		}))
+	})
+	It("prepends the values of a json file", func() {
+		path := write("config.json", `{"name":"banana","port":9090}`)
+		Expect(service.ConfigFileArgs(ctx, app, nil, path)).To(Equal([]string{
+			"-name=banana",
+			"-port=9090",
+		}))
+	})
+	It("reads the path of the config flag and removes it", func() {
+		path := write("other.yaml", "name: banana\n")
+		Expect(service.ConfigFileArgs(ctx, app, []string{"--config", path, "-port=1"}, filepath.Join(dir, "missing.yaml"))).To(Equal([]string{
+			"-name=banana",
+			"-port=1",
+		}))
+	})
+	It("skips a missing default file", func() {
+		Expect(service.ConfigFileArgs(ctx, app, []string{"-name=apple"}, filepath.Join(dir, "missing.yaml"))).To(Equal([]string{"-name=apple"}))
+	})
+	It("fails for a missing file set by flag", func() {
+		_, err := service.ConfigFileArgs(ctx, app, []string{"-config=" + filepath.Join(dir, "missing.yaml")}, filepath.Join(dir, "default.yaml"))
+		Expect(err).NotTo(BeNil())
+	})
+	It("fails for an unknown key", func() {
+		path := write("config.yaml", "banana: true\n")
+		_, err := service.ConfigFileArgs(ctx, app, nil, path)
+		Expect(err).To(MatchError(ContainSubstring("unknown key banana")))
+	})
+	It("fails for nested values", func() {
+		path := write("config.yaml", "name:\n  first: banana\n")
+		_, err := service.ConfigFileArgs(ctx, app, nil, path)
+		Expect(err).NotTo(BeNil())
+	})
+	It("returns the args unchanged without config file", func() {
+		Expect(service.ConfigFileArgs(ctx, app, []string{"-config=x"}, "")).To(Equal([]string{"-config=x"}))
+	})
+})
+
+var _ = Describe("Main WithConfigFile", func() {
+	var dir string
+	BeforeEach(func() {
+		dir = GinkgoT().TempDir()
+		Expect(os.WriteFile(filepath.Join(dir, "config.yaml"), []byte("name: banana\nport: 9090\n"), 0600)).To(Succeed())
+	})
+	mainWithArgs := func(app *configApplication, args ...string) int {
+		return service.MainWithArgs(context.Background(), app, &app.SentryDSN, nil, args,
+			service.WithoutTimezoneOverride(),
+			service.WithRegisterer(nil),
+			service.WithSentryTransport(servicetest.NewSentryTransport()),
+			service.WithConfigFile(filepath.Join(dir, "config.yaml")),
+		)
+	}
+	It("fills the application from the file", func() {
+		app := &configApplication{}
+		Expect(mainWithArgs(app)).To(Equal(service.ExitCodeSuccess))
+		Expect(app.Name).To(Equal("banana"))
+		Expect(app.Port).To(Equal(9090))
+	})
+	It("lets args override the file", func() {
+		app := &configApplication{}
+		Expect(mainWithArgs(app, "-port=1")).To(Equal(service.ExitCodeSuccess))
+		Expect(app.Name).To(Equal("banana"))
+		Expect(app.Port).To(Equal(1))
+	})
+	It("exits with the parse arguments code for a missing file set by flag", func() {
+		app := &configApplication{}
+		Expect(mainWithArgs(app, "-config="+filepath.Join(dir, "missing.yaml"))).To(Equal(service.ExitCodeParseArguments))
+	})
+	It("logs with the Logger of the options", func() {
+		app := &configApplication{}
+		logger := &recordingLogger{}
+		Expect(service.MainWithArgs(context.Background(), app, &app.SentryDSN, nil, []string{"-name=banana"},
+			service.WithoutTimezoneOverride(),
+			service.WithRegisterer(nil),
+			service.WithSentryTransport(servicetest.NewSentryTransport()),
+			service.WithConfigFile(filepath.Join(dir, "missing.yaml")),
+			service.WithLogger(logger),
+		)).To(Equal(service.ExitCodeSuccess))
+		Expect(logger.Lines()).To(ContainElement(ContainSubstring("missing.yaml not found => skip")))
+	})
+})
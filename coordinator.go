@@ -0,0 +1,126 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package service
+
+import (
+	"context"
+	"sync"
+
+	"github.com/bborbe/run"
+)
+
+//counterfeiter:generate -o mocks/service-coordinator.go --fake-name ServiceCoordinator . Coordinator
+
+// Coordinator shuts down multiple independent Run groups together.
+// As soon as one group finishes, all other groups are canceled.
+type Coordinator interface {
+	// Run the given funcs as one group like Run.
+	// If the coordinator is already shut down the funcs are not started.
+	Run(ctx context.Context, funcs ...run.Func) error
+	// Shutdown cancels all groups.
+	Shutdown()
+	// Active returns the number of groups that are still running.
+	Active() int
+	// Wait until all groups have returned or ctx is done.
+	Wait(ctx context.Context) error
+}
+
+func NewCoordinator() Coordinator {
+	ctx, cancel := context.WithCancel(context.Background())
+	idle := make(chan struct{})
+	close(idle)
+	return &coordinator{
+		ctx:    ctx,
+		cancel: cancel,
+		idle:   idle,
+	}
+}
+
+type coordinator struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	mux    sync.Mutex
+	active int
+	// idle is closed while no group is running
+	idle chan struct{}
+}
+
+func (c *coordinator) Run(ctx context.Context, funcs ...run.Func) error {
+	if !c.register() {
+		return nil
+	}
+	defer c.unregister()
+	defer c.cancel()
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	stop := context.AfterFunc(c.ctx, cancel)
+	defer stop()
+
+	return Run(ctx, funcs...)
+}
+
+func (c *coordinator) Shutdown() {
+	c.cancel()
+}
+
+func (c *coordinator) Active() int {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	return c.active
+}
+
+func (c *coordinator) Wait(ctx context.Context) error {
+	c.mux.Lock()
+	idle := c.idle
+	c.mux.Unlock()
+
+	select {
+	case <-idle:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (c *coordinator) register() bool {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	if c.ctx.Err() != nil {
+		return false
+	}
+	if c.active == 0 {
+		c.idle = make(chan struct{})
+	}
+	c.active++
+	return true
+}
+
+func (c *coordinator) unregister() {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	c.active--
+	if c.active == 0 {
+		close(c.idle)
+	}
+}
+
+// drainCoordinator runs fn and afterwards shuts down the coordinator and waits until all its groups have returned.
+func drainCoordinator(coordinator Coordinator, fn run.Func) run.Func {
+	return func(ctx context.Context) error {
+		err := fn(ctx)
+		coordinator.Shutdown()
+		logger := LoggerFromContext(ctx)
+		if logger.V(2) {
+			logger.Infof("wait for %d coordinated groups", coordinator.Active())
+		}
+		_ = coordinator.Wait(context.Background())
+		if logger.V(2) {
+			logger.Infof("all coordinated groups finished")
+		}
+		return err
+	}
+}
@@ -0,0 +1,52 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package service_test
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/bborbe/service"
+)
+
+func ExampleCoordinator() {
+	ctx := context.Background()
+	coordinator := service.NewCoordinator()
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	serverStarted := make(chan struct{})
+
+	// the server group runs until it gets canceled
+	go func() {
+		defer wg.Done()
+		_ = coordinator.Run(ctx, func(ctx context.Context) error {
+			close(serverStarted)
+			<-ctx.Done()
+			fmt.Println("server stopped")
+			return ctx.Err()
+		})
+	}()
+
+	// the worker group finishes its work and thereby shuts down the server group as well
+	go func() {
+		defer wg.Done()
+		<-serverStarted
+		_ = coordinator.Run(ctx, func(ctx context.Context) error {
+			fmt.Println("work done")
+			return nil
+		})
+	}()
+
+	wg.Wait()
+	_ = coordinator.Wait(ctx)
+	fmt.Println("active groups:", coordinator.Active())
+
+	// Output:
+	// work done
+	// server stopped
+	// active groups: 0
+}
@@ -0,0 +1,84 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package service_test
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/bborbe/service"
+)
+
+var _ = Describe("Coordinator", func() {
+	var ctx context.Context
+	var cancel context.CancelFunc
+	var coordinator service.Coordinator
+	BeforeEach(func() {
+		ctx, cancel = context.WithTimeout(context.Background(), time.Second)
+		coordinator = service.NewCoordinator()
+	})
+	AfterEach(func() {
+		cancel()
+	})
+	waitForDone := func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+	It("is idle without groups", func() {
+		Expect(coordinator.Active()).To(Equal(0))
+		Expect(coordinator.Wait(ctx)).To(BeNil())
+	})
+	It("cancels all groups if one group finishes", func() {
+		secondDone := make(chan error, 1)
+		go func() {
+			secondDone <- coordinator.Run(ctx, waitForDone)
+		}()
+		Eventually(coordinator.Active).Should(Equal(1))
+
+		Expect(coordinator.Run(ctx, func(ctx context.Context) error {
+			return nil
+		})).To(BeNil())
+
+		Eventually(secondDone).Should(Receive(BeNil()))
+		Expect(coordinator.Wait(ctx)).To(BeNil())
+		Expect(coordinator.Active()).To(Equal(0))
+	})
+	It("cancels all groups on shutdown", func() {
+		done := make(chan error, 2)
+		for i := 0; i < 2; i++ {
+			go func() {
+				done <- coordinator.Run(ctx, waitForDone)
+			}()
+		}
+		Eventually(coordinator.Active).Should(Equal(2))
+		coordinator.Shutdown()
+		Expect(coordinator.Wait(ctx)).To(BeNil())
+		Expect(done).To(HaveLen(2))
+	})
+	It("does not start groups after shutdown", func() {
+		coordinator.Shutdown()
+		var started bool
+		Expect(coordinator.Run(ctx, func(ctx context.Context) error {
+			started = true
+			return nil
+		})).To(BeNil())
+		Expect(started).To(BeFalse())
+	})
+	It("stops waiting if the context is canceled", func() {
+		go func() {
+			_ = coordinator.Run(context.Background(), func(ctx context.Context) error {
+				time.Sleep(time.Second)
+				return nil
+			})
+		}()
+		Eventually(coordinator.Active).Should(Equal(1))
+		waitCtx, waitCancel := context.WithCancel(ctx)
+		waitCancel()
+		Expect(coordinator.Wait(waitCtx)).To(Equal(context.Canceled))
+	})
+})
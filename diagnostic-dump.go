@@ -0,0 +1,65 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package service
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"runtime"
+	"runtime/pprof"
+	"time"
+
+	"github.com/bborbe/errors"
+)
+
+// WriteDiagnosticDump writes GOMAXPROCS, the memory stats and the stacks of all goroutines to w.
+func WriteDiagnosticDump(ctx context.Context, w io.Writer) error {
+	var memStats runtime.MemStats
+	runtime.ReadMemStats(&memStats)
+	_, _ = fmt.Fprintf(w, "=== diagnostic dump %s ===\n", time.Now().Format(time.RFC3339))
+	_, _ = fmt.Fprintf(w, "GOMAXPROCS: %d\n", runtime.GOMAXPROCS(0))
+	_, _ = fmt.Fprintf(w, "NumCPU: %d\n", runtime.NumCPU())
+	_, _ = fmt.Fprintf(w, "NumGoroutine: %d\n", runtime.NumGoroutine())
+	_, _ = fmt.Fprintf(w, "HeapAlloc: %d\n", memStats.HeapAlloc)
+	_, _ = fmt.Fprintf(w, "HeapInuse: %d\n", memStats.HeapInuse)
+	_, _ = fmt.Fprintf(w, "HeapObjects: %d\n", memStats.HeapObjects)
+	_, _ = fmt.Fprintf(w, "Sys: %d\n", memStats.Sys)
+	_, _ = fmt.Fprintf(w, "NumGC: %d\n", memStats.NumGC)
+	_, _ = fmt.Fprintf(w, "PauseTotalNs: %d\n", memStats.PauseTotalNs)
+	if err := pprof.Lookup("goroutine").WriteTo(w, 2); err != nil {
+		return errors.Wrapf(ctx, err, "write goroutine stacks failed")
+	}
+	return nil
+}
+
+// diagnosticDumpOnSignal registers the given signal and writes a dump on each received signal until ctx is done.
+// Registering the signal replaces the default handling of Go, for SIGQUIT the process keeps running.
+func diagnosticDumpOnSignal(ctx context.Context, sig os.Signal, w io.Writer) {
+	signalCh := make(chan os.Signal, 1)
+	signal.Notify(signalCh, sig)
+	go func() {
+		defer signal.Stop(signalCh)
+		diagnosticDumpOnSignalCh(ctx, signalCh, w)
+	}()
+}
+
+func diagnosticDumpOnSignalCh(ctx context.Context, signalCh <-chan os.Signal, w io.Writer) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case sig := <-signalCh:
+			if LoggerFromContext(ctx).V(1) {
+				LoggerFromContext(ctx).Infof("got signal %s => write diagnostic dump", sig)
+			}
+			if err := WriteDiagnosticDump(ctx, w); err != nil {
+				LoggerFromContext(ctx).Warningf("write diagnostic dump failed: %v", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,46 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package service_test
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"syscall"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/bborbe/service"
+)
+
+var _ = Describe("DiagnosticDump", func() {
+	var ctx context.Context
+	BeforeEach(func() {
+		ctx = context.Background()
+	})
+	It("writes GOMAXPROCS, mem stats and goroutine stacks", func() {
+		buf := &bytes.Buffer{}
+		Expect(service.WriteDiagnosticDump(ctx, buf)).To(BeNil())
+		Expect(buf.String()).To(ContainSubstring("GOMAXPROCS: "))
+		Expect(buf.String()).To(ContainSubstring("HeapAlloc: "))
+		Expect(buf.String()).To(ContainSubstring("goroutine "))
+	})
+	It("writes a dump for every signal until the context is done", func() {
+		ctx, cancel := context.WithCancel(ctx)
+		buf := &syncBuffer{}
+		signalCh := make(chan os.Signal)
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			service.DiagnosticDumpOnSignalCh(ctx, signalCh, buf)
+		}()
+		signalCh <- syscall.SIGQUIT
+		signalCh <- syscall.SIGQUIT
+		cancel()
+		Eventually(done).Should(BeClosed())
+		Expect(bytes.Count([]byte(buf.String()), []byte("=== diagnostic dump"))).To(Equal(2))
+	})
+})
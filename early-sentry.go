@@ -0,0 +1,67 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package service
+
+import (
+	"context"
+	"os"
+	"runtime/debug"
+
+	libsentry "github.com/bborbe/sentry"
+	"github.com/getsentry/sentry-go"
+)
+
+// newEarlySentryClient creates a minimal Sentry client from the DSN in the given env var,
+// because the arguments are not parsed yet. It returns nil if the env var is empty.
+func newEarlySentryClient(ctx context.Context, dsnEnv string) libsentry.Client {
+	dsn := os.Getenv(dsnEnv)
+	if dsn == "" {
+		if LoggerFromContext(ctx).V(2) {
+			LoggerFromContext(ctx).Infof("env %s is empty => skip early sentry", dsnEnv)
+		}
+		return nil
+	}
+	sentryClient, err := newSentryClient(ctx, sentry.ClientOptions{
+		Dsn: dsn,
+	}, LoggerFromContext(ctx))
+	if err != nil {
+		LoggerFromContext(ctx).Warningf("setting up early Sentry failed: %v", err)
+		return nil
+	}
+	return sentryClient
+}
+
+// parseArguments calls parse and captures a panic with the given Sentry client before it is re-raised.
+// A nil sentryClient only re-raises.
+func parseArguments(
+	ctx context.Context,
+	app Application,
+	sentryClient libsentry.Client,
+	parse func(ctx context.Context, data interface{}) error,
+) error {
+	defer func() {
+		value := recover()
+		if value == nil {
+			return
+		}
+		if sentryClient != nil {
+			err := &PanicError{
+				Value: value,
+				Stack: debug.Stack(),
+			}
+			sentryClient.CaptureException(
+				err,
+				&sentry.EventHint{
+					Context:           ctx,
+					OriginalException: err,
+				},
+				sentry.NewScope(),
+			)
+			sentryClient.Flush(sentryFlushTimeout)
+		}
+		panic(value)
+	}()
+	return parse(ctx, app)
+}
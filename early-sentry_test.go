@@ -0,0 +1,54 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package service_test
+
+import (
+	"context"
+	stderrors "errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/bborbe/service"
+	"github.com/bborbe/service/mocks"
+)
+
+var _ = Describe("ParseArguments", func() {
+	var ctx context.Context
+	var app *mocks.ServiceApplication
+	var sentryClient *mocks.SentryClient
+	BeforeEach(func() {
+		ctx = context.Background()
+		app = &mocks.ServiceApplication{}
+		sentryClient = &mocks.SentryClient{}
+	})
+	It("returns the parse error", func() {
+		err := service.ParseArguments(ctx, app, sentryClient, func(ctx context.Context, data interface{}) error {
+			return stderrors.New("banana")
+		})
+		Expect(err).NotTo(BeNil())
+		Expect(sentryClient.CaptureExceptionCallCount()).To(Equal(0))
+	})
+	It("captures a panic during argument parsing", func() {
+		Expect(func() {
+			_ = service.ParseArguments(ctx, app, sentryClient, func(ctx context.Context, data interface{}) error {
+				panic("banana")
+			})
+		}).To(PanicWith("banana"))
+		Expect(sentryClient.CaptureExceptionCallCount()).To(Equal(1))
+		err, _, _ := sentryClient.CaptureExceptionArgsForCall(0)
+		var panicErr *service.PanicError
+		Expect(stderrors.As(err, &panicErr)).To(BeTrue())
+		Expect(panicErr.Value).To(Equal("banana"))
+		Expect(sentryClient.FlushCallCount()).To(Equal(1))
+	})
+	It("re-raises a panic without early sentry", func() {
+		Expect(func() {
+			_ = service.ParseArguments(ctx, app, nil, func(ctx context.Context, data interface{}) error {
+				panic("banana")
+			})
+		}).To(PanicWith("banana"))
+	})
+})
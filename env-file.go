@@ -0,0 +1,96 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package service
+
+import (
+	"context"
+	"os"
+	"reflect"
+	"strings"
+
+	"github.com/bborbe/errors"
+)
+
+// EnvFileSuffix is appended to the env tag of a field to get the env var with the path of a file containing its value,
+// e.g. SENTRY_DSN_FILE=/run/secrets/sentry-dsn for a field tagged env:"SENTRY_DSN".
+const EnvFileSuffix = "_FILE"
+
+// envFileEnviron returns environ with the contents of the files referenced by the X_FILE env vars
+// of all fields tagged env:"X" for which X is not set, so a set env var overrides the file.
+func envFileEnviron(ctx context.Context, data interface{}, environ []string) ([]string, error) {
+	envs := environMap(environ)
+	result := environ
+	value := reflect.ValueOf(data).Elem()
+	for i := 0; i < value.NumField(); i++ {
+		env, ok := value.Type().Field(i).Tag.Lookup("env")
+		if !ok {
+			continue
+		}
+		path, ok := envs[env+EnvFileSuffix]
+		if !ok {
+			continue
+		}
+		if _, ok := envs[env]; ok {
+			continue
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil, errors.Wrapf(ctx, err, "read file of %s%s failed", env, EnvFileSuffix)
+		}
+		result = append(result, env+"="+strings.TrimRight(string(content), "\r\n"))
+	}
+	return result, nil
+}
+
+// environWithoutExplicitArgs returns environ without the env vars of fields whose arg was given explicitly,
+// so an explicit arg overrides the env var and the file.
+func environWithoutExplicitArgs(data interface{}, explicitArgs map[string]bool, environ []string) []string {
+	skip := make(map[string]bool)
+	value := reflect.ValueOf(data).Elem()
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Type().Field(i)
+		env, ok := field.Tag.Lookup("env")
+		if !ok {
+			continue
+		}
+		if arg, ok := field.Tag.Lookup("arg"); ok && explicitArgs[arg] {
+			skip[env] = true
+		}
+	}
+	result := make([]string, 0, len(environ))
+	for _, entry := range environ {
+		name, _, _ := strings.Cut(entry, "=")
+		if skip[name] {
+			continue
+		}
+		result = append(result, entry)
+	}
+	return result
+}
+
+func environMap(environ []string) map[string]string {
+	result := make(map[string]string, len(environ))
+	for _, entry := range environ {
+		name, value, _ := strings.Cut(entry, "=")
+		result[name] = value
+	}
+	return result
+}
+
+// flagNames returns the names of the flags in args.
+func flagNames(args []string) map[string]bool {
+	result := make(map[string]bool)
+	for _, arg := range args {
+		if arg == "--" {
+			break
+		}
+		if !strings.HasPrefix(arg, "-") {
+			continue
+		}
+		name, _, _ := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+		result[name] = true
+	}
+	return result
+}
@@ -0,0 +1,122 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package service_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/bborbe/service"
+)
+
+type envFileApplication struct {
+	SentryDSN string `required:"false" arg:"sentry-dsn" env:"SENTRY_DSN" usage:"SentryDSN" display:"length" default:"default"`
+	Token     string `required:"false" env:"TOKEN" usage:"token" display:"hidden"`
+	Name      string `required:"false" arg:"name" usage:"name"`
+}
+
+var _ = Describe("EnvFileEnviron", func() {
+	var ctx context.Context
+	var path string
+	var app *envFileApplication
+	BeforeEach(func() {
+		ctx = context.Background()
+		path = filepath.Join(GinkgoT().TempDir(), "secret")
+		Expect(os.WriteFile(path, []byte("banana\n"), 0600)).To(Succeed())
+		app = &envFileApplication{}
+	})
+	It("appends the trimmed file content", func() {
+		environ, err := service.EnvFileEnviron(ctx, app, []string{"SENTRY_DSN_FILE=" + path, "TOKEN_FILE=" + path})
+		Expect(err).To(BeNil())
+		Expect(environ).To(Equal([]string{
+			"SENTRY_DSN_FILE=" + path,
+			"TOKEN_FILE=" + path,
+			"SENTRY_DSN=banana",
+			"TOKEN=banana",
+		}))
+	})
+	It("prefers the env var", func() {
+		environ, err := service.EnvFileEnviron(ctx, app, []string{"SENTRY_DSN=apple", "SENTRY_DSN_FILE=" + path})
+		Expect(err).To(BeNil())
+		Expect(environ).To(Equal([]string{"SENTRY_DSN=apple", "SENTRY_DSN_FILE=" + path}))
+	})
+	It("ignores fields without env tag", func() {
+		environ, err := service.EnvFileEnviron(ctx, app, []string{"NAME_FILE=" + path})
+		Expect(err).To(BeNil())
+		Expect(environ).To(Equal([]string{"NAME_FILE=" + path}))
+	})
+	It("fails for a missing file", func() {
+		_, err := service.EnvFileEnviron(ctx, app, []string{"TOKEN_FILE=" + filepath.Join(filepath.Dir(path), "missing")})
+		Expect(err).To(MatchError(ContainSubstring("TOKEN_FILE")))
+	})
+})
+
+var _ = Describe("ParseArgsAndEnv precedence", func() {
+	var ctx context.Context
+	var path string
+	var app *envFileApplication
+	var args []string
+	var explicitArgs []string
+	var environ []string
+	BeforeEach(func() {
+		ctx = context.Background()
+		path = filepath.Join(GinkgoT().TempDir(), "secret")
+		Expect(os.WriteFile(path, []byte("file\n"), 0600)).To(Succeed())
+		app = &envFileApplication{}
+		args = nil
+		explicitArgs = nil
+		environ = nil
+	})
+	parse := func() string {
+		// config file args are prepended to the explicit args
+		Expect(service.ParseArgsAndEnv(ctx, app, append(args, explicitArgs...), service.FlagNames(explicitArgs), environ)).To(Succeed())
+		return app.SentryDSN
+	}
+	It("uses the default", func() {
+		Expect(parse()).To(Equal("default"))
+	})
+	It("prefers the env file over the default", func() {
+		environ = []string{"SENTRY_DSN_FILE=" + path}
+		Expect(parse()).To(Equal("file"))
+	})
+	It("prefers the env file over the config file", func() {
+		args = []string{"-sentry-dsn=config"}
+		environ = []string{"SENTRY_DSN_FILE=" + path}
+		Expect(parse()).To(Equal("file"))
+	})
+	It("prefers the env over the env file", func() {
+		environ = []string{"SENTRY_DSN=env", "SENTRY_DSN_FILE=" + path}
+		Expect(parse()).To(Equal("env"))
+	})
+	It("prefers the env over the config file", func() {
+		args = []string{"-sentry-dsn=config"}
+		environ = []string{"SENTRY_DSN=env"}
+		Expect(parse()).To(Equal("env"))
+	})
+	It("prefers an explicit arg over the env", func() {
+		explicitArgs = []string{"-sentry-dsn=arg"}
+		environ = []string{"SENTRY_DSN=env"}
+		Expect(parse()).To(Equal("arg"))
+	})
+	It("prefers an explicit arg over the env file", func() {
+		explicitArgs = []string{"--sentry-dsn", "arg"}
+		environ = []string{"SENTRY_DSN_FILE=" + path}
+		Expect(parse()).To(Equal("arg"))
+	})
+	It("prefers an explicit arg over the config file", func() {
+		args = []string{"-sentry-dsn=config"}
+		explicitArgs = []string{"-sentry-dsn=arg"}
+		Expect(parse()).To(Equal("arg"))
+	})
+	It("reads a field without arg from the env file", func() {
+		environ = []string{"TOKEN_FILE=" + path}
+		parse()
+		Expect(app.Token).To(Equal("file"))
+	})
+})
@@ -0,0 +1,69 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package service
+
+import (
+	stderrors "errors"
+)
+
+const (
+	// ExitCodeSuccess is returned if the application finished without error.
+	ExitCodeSuccess = 0
+	// ExitCodeFailure is returned if the application failed and no ExitCodeMapper decided otherwise.
+	ExitCodeFailure = 1
+	// ExitCodeSentrySetup is returned if Sentry could not be set up. Reserved by the framework.
+	ExitCodeSentrySetup = 2
+	// ExitCodeSentryDSNMissing is returned if no Sentry DSN is given. Reserved by the framework.
+	ExitCodeSentryDSNMissing = 3
+	// ExitCodeParseArguments is returned if the arguments could not be parsed. Reserved by the framework.
+	ExitCodeParseArguments = 4
+	// ExitCodeAddressInUse is returned if the application failed with an AddressInUseError. Reserved by the framework.
+	ExitCodeAddressInUse = 5
+	// ExitCodeOnStart is returned if the OnStart hook failed and the application was not started. Reserved by the framework.
+	ExitCodeOnStart = 6
+	// ExitCodeTracingSetup is returned if the TracingSetup failed. Reserved by the framework.
+	ExitCodeTracingSetup = 7
+)
+
+// ExitCodeMapper returns the exit code for the error of the application.
+// Returning ExitCodeSuccess falls back to ExitCodeFailure. Codes 2-7 are reserved by the framework
+// and should not be returned to keep them distinguishable.
+type ExitCodeMapper func(err error) int
+
+// applicationError marks the error returned by the application, so it can be unwrapped
+// after the service wrapped it.
+type applicationError struct {
+	err error
+}
+
+func (a *applicationError) Error() string {
+	return a.err.Error()
+}
+
+func (a *applicationError) Unwrap() error {
+	return a.err
+}
+
+// exitCode for the given error of the service. Errors known to the framework get their reserved code,
+// otherwise the mapper receives the unwrapped application error.
+func exitCode(err error, mapper ExitCodeMapper) int {
+	if err == nil {
+		return ExitCodeSuccess
+	}
+	if stderrors.Is(err, ErrAddressInUse) {
+		return ExitCodeAddressInUse
+	}
+	if mapper == nil {
+		return ExitCodeFailure
+	}
+	var appErr *applicationError
+	if stderrors.As(err, &appErr) {
+		err = appErr.err
+	}
+	if code := mapper(err); code != ExitCodeSuccess {
+		return code
+	}
+	return ExitCodeFailure
+}
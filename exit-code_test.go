@@ -0,0 +1,72 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package service_test
+
+import (
+	"context"
+	stderrors "errors"
+	"os"
+
+	libsentry "github.com/bborbe/sentry"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/bborbe/service"
+	"github.com/bborbe/service/mocks"
+)
+
+var _ = Describe("ExitCode", func() {
+	var appErr error
+	var serviceErr error
+	BeforeEach(func() {
+		appErr = stderrors.New("banana")
+		app := &mocks.ServiceApplication{}
+		app.RunReturns(appErr)
+		serviceErr = service.NewService(&mocks.SentryClient{}, app).Run(context.Background())
+	})
+	It("returns success without error", func() {
+		Expect(service.ExitCode(nil, nil)).To(Equal(service.ExitCodeSuccess))
+	})
+	It("returns failure without mapper", func() {
+		Expect(service.ExitCode(serviceErr, nil)).To(Equal(service.ExitCodeFailure))
+	})
+	It("passes the unwrapped application error to the mapper", func() {
+		var mapped error
+		code := service.ExitCode(serviceErr, func(err error) int {
+			mapped = err
+			return 7
+		})
+		Expect(code).To(Equal(7))
+		Expect(mapped).To(Equal(appErr))
+	})
+	It("keeps the message of the service error", func() {
+		Expect(serviceErr.Error()).To(Equal("application failed: banana"))
+	})
+	It("falls back to failure if the mapper returns success", func() {
+		Expect(service.ExitCode(serviceErr, func(err error) int {
+			return service.ExitCodeSuccess
+		})).To(Equal(service.ExitCodeFailure))
+	})
+	It("maps error classes", func() {
+		mapper := func(err error) int {
+			if stderrors.Is(err, os.ErrNotExist) {
+				return 10
+			}
+			return service.ExitCodeFailure
+		}
+		app := &mocks.ServiceApplication{}
+		app.RunStub = func(ctx context.Context, sentryClient libsentry.Client) error {
+			return os.ErrNotExist
+		}
+		err := service.NewService(&mocks.SentryClient{}, app).Run(context.Background())
+		Expect(service.ExitCode(err, mapper)).To(Equal(10))
+	})
+	It("keeps the reserved code for an address in use", func() {
+		err := &service.AddressInUseError{Addr: ":8080", Cause: stderrors.New("bind")}
+		Expect(service.ExitCode(err, func(err error) int {
+			return 7
+		})).To(Equal(service.ExitCodeAddressInUse))
+	})
+})
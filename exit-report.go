@@ -0,0 +1,79 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package service
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"strconv"
+
+	libsentry "github.com/bborbe/sentry"
+	"github.com/getsentry/sentry-go"
+)
+
+const (
+	// ExitReasonFinished is reported if the application returned without error.
+	ExitReasonFinished = "finished"
+	// ExitReasonSignal is reported if the application stopped after a shutdown signal.
+	ExitReasonSignal = "signal"
+	// ExitReasonError is reported if the application failed.
+	ExitReasonError = "error"
+	// ExitReasonPanic is reported if the application panicked.
+	ExitReasonPanic = "panic"
+	// ExitReasonRunTimeout is reported if the application stopped because of the RunTimeout.
+	ExitReasonRunTimeout = "run timeout"
+	// ExitReasonOnStart is reported if the OnStart hook failed.
+	ExitReasonOnStart = "on start failed"
+)
+
+// exitReason returns why the application stopped. Excluded errors count as clean stop.
+func exitReason(ctx context.Context, err error, excludeErrors libsentry.ExcludeErrors) string {
+	if isRunTimeout(ctx, err) {
+		return ExitReasonRunTimeout
+	}
+	if err != nil && !excludeErrors.IsExcluded(err) {
+		var panicErr *PanicError
+		if stderrors.As(err, &panicErr) {
+			return ExitReasonPanic
+		}
+		return ExitReasonError
+	}
+	if SignalFromContext(ctx) != nil {
+		return ExitReasonSignal
+	}
+	return ExitReasonFinished
+}
+
+// reportExit adds the exit code and reason as breadcrumb and captures them as final message.
+// The message of an excluded error is left out.
+func reportExit(ctx context.Context, sentryClient ScopedSentryClient, excludeErrors libsentry.ExcludeErrors, code int, reason string, err error) {
+	data := map[string]interface{}{
+		"exit_code": code,
+		"reason":    reason,
+	}
+	if sig := SignalFromContext(ctx); sig != nil {
+		data["signal"] = sig.String()
+	}
+	if err != nil && !excludeErrors.IsExcluded(err) {
+		data["error"] = err.Error()
+	}
+	level := sentry.LevelInfo
+	if code != ExitCodeSuccess {
+		level = sentry.LevelError
+	}
+	message := fmt.Sprintf("exit with code %d: %s", code, reason)
+	sentryClient.AddBreadcrumb(&sentry.Breadcrumb{
+		Category: "service",
+		Message:  message,
+		Level:    level,
+		Data:     data,
+	})
+	scope := sentry.NewScope()
+	scope.SetLevel(level)
+	scope.SetTag("exit_code", strconv.Itoa(code))
+	scope.SetTag("exit_reason", reason)
+	sentryClient.CaptureMessage(message, &sentry.EventHint{Context: ctx}, scope)
+}
@@ -0,0 +1,120 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package service_test
+
+import (
+	"context"
+	stderrors "errors"
+
+	"github.com/getsentry/sentry-go"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/bborbe/service"
+	"github.com/bborbe/service/servicetest"
+)
+
+var _ = Describe("ExitReason", func() {
+	var ctx context.Context
+	BeforeEach(func() {
+		ctx = context.Background()
+	})
+	DescribeTable("returns the reason",
+		func(err error, expected string) {
+			Expect(service.ExitReason(ctx, err, service.DefaultExcludeErrors())).To(Equal(expected))
+		},
+		Entry("finished", nil, service.ExitReasonFinished),
+		Entry("error", stderrors.New("banana"), service.ExitReasonError),
+		Entry("panic", service.NewPanicError("banana"), service.ExitReasonPanic),
+		Entry("excluded error", context.Canceled, service.ExitReasonFinished),
+	)
+})
+
+var _ = Describe("WithExitReporting", func() {
+	var transport servicetest.SentryTransport
+	var app *mainApplication
+	var fns []service.OptionsFn
+	BeforeEach(func() {
+		transport = servicetest.NewSentryTransport()
+		app = &mainApplication{
+			RunFunc: func(ctx context.Context) error {
+				return nil
+			},
+		}
+		fns = []service.OptionsFn{
+			service.WithoutTimezoneOverride(),
+			service.WithRegisterer(nil),
+			service.WithSentryTransport(transport),
+		}
+	})
+	main := func(fns ...service.OptionsFn) int {
+		return service.Main(context.Background(), app, &app.SentryDSN, nil, fns...)
+	}
+	messages := func() []*sentry.Event {
+		var result []*sentry.Event
+		for _, event := range transport.Events() {
+			if event.Message != "" {
+				result = append(result, event)
+			}
+		}
+		return result
+	}
+	It("reports nothing by default", func() {
+		Expect(main(fns...)).To(Equal(service.ExitCodeSuccess))
+		Expect(messages()).To(BeEmpty())
+	})
+	It("reports a clean finish", func() {
+		Expect(main(append(fns, service.WithExitReporting())...)).To(Equal(service.ExitCodeSuccess))
+		Expect(messages()).To(HaveLen(1))
+		event := messages()[0]
+		Expect(event.Message).To(Equal("exit with code 0: finished"))
+		Expect(event.Level).To(Equal(sentry.LevelInfo))
+		Expect(event.Tags).To(HaveKeyWithValue("exit_code", "0"))
+		Expect(event.Tags).To(HaveKeyWithValue("exit_reason", service.ExitReasonFinished))
+		Expect(event.Breadcrumbs).NotTo(BeEmpty())
+		Expect(event.Breadcrumbs[len(event.Breadcrumbs)-1].Message).To(Equal("exit with code 0: finished"))
+	})
+	It("reports the error with the mapped exit code", func() {
+		app.RunFunc = func(ctx context.Context) error {
+			return stderrors.New("banana")
+		}
+		Expect(main(append(fns, service.WithExitReporting(), service.WithExitCodeMapper(func(err error) int {
+			return 42
+		}))...)).To(Equal(42))
+		Expect(messages()).To(HaveLen(1))
+		Expect(messages()[0].Message).To(Equal("exit with code 42: error"))
+		Expect(messages()[0].Level).To(Equal(sentry.LevelError))
+	})
+	It("reports a panic", func() {
+		app.RunFunc = func(ctx context.Context) error {
+			panic("banana")
+		}
+		Expect(main(append(fns, service.WithExitReporting())...)).To(Equal(service.ExitCodeFailure))
+		Expect(messages()).To(HaveLen(1))
+		Expect(messages()[0].Tags).To(HaveKeyWithValue("exit_reason", service.ExitReasonPanic))
+	})
+	It("reports a failed OnStart", func() {
+		Expect(main(append(fns, service.WithExitReporting(), service.WithOnStart(func(ctx context.Context) error {
+			return stderrors.New("banana")
+		}))...)).To(Equal(service.ExitCodeOnStart))
+		Expect(messages()).To(HaveLen(1))
+		Expect(messages()[0].Tags).To(HaveKeyWithValue("exit_reason", service.ExitReasonOnStart))
+	})
+	It("exits normally if the exit message is dropped", func() {
+		Expect(main(append(fns, service.WithExitReporting(), service.WithBeforeSend(func(event *sentry.Event, hint *sentry.EventHint) *sentry.Event {
+			return nil
+		}))...)).To(Equal(service.ExitCodeSuccess))
+		Expect(messages()).To(BeEmpty())
+	})
+	It("exits with the code of the error if the exit message is dropped", func() {
+		app.RunFunc = func(ctx context.Context) error {
+			return stderrors.New("banana")
+		}
+		Expect(main(append(fns, service.WithExitReporting(), service.WithBeforeSend(func(event *sentry.Event, hint *sentry.EventHint) *sentry.Event {
+			return nil
+		}))...)).To(Equal(service.ExitCodeFailure))
+		Expect(transport.Events()).To(BeEmpty())
+	})
+})
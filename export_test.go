@@ -0,0 +1,121 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package service
+
+import (
+	"context"
+	"flag"
+	"io"
+
+	"github.com/bborbe/run"
+	libsentry "github.com/bborbe/sentry"
+)
+
+var NewShutdownDeadline = newShutdownDeadline
+
+var ContextWithSignalCh = contextWithSignalCh
+
+var DiagnosticDumpOnSignalCh = diagnosticDumpOnSignalCh
+
+var NewPhaseGauge = newPhaseGauge
+
+var RunWithStartupSelfCheck = runWithStartupSelfCheck
+
+var NewSentryFailureDetector = newSentryFailureDetector
+
+var NewDegradingSentryClient = newDegradingSentryClient
+
+var CreateReadinessFile = createReadinessFile
+
+var RunWithReadinessFile = runWithReadinessFile
+
+var MaxProcs = maxProcs
+
+var SetLocation = setLocation
+
+var ParseArguments = parseArguments
+
+var ContextWithSig = contextWithSig
+
+var MonitorGoroutines = monitorGoroutines
+
+var StartPeriodicFlush = startPeriodicFlush
+
+var CaptureErrors = captureErrors
+
+var ExitCode = exitCode
+
+var ShutdownTimeoutFromGracePeriod = shutdownTimeoutFromGracePeriod
+
+var NewStartupTimer = newStartupTimer
+
+var NewExcludingSentryClient = newExcludingSentryClient
+
+var SetupProcess = setupProcess
+
+var RunShutdownPhases = runShutdownPhases
+
+var CheckSentryConnectivity = checkSentryConnectivity
+
+var NewSamplingSentryClient = newSamplingSentryClient
+
+var RedactArgs = redactArgs
+
+var CommandLineContext = commandLineContext
+
+var NewShutdownSLO = newShutdownSLO
+
+var ConfigFileArgs = configFileArgs
+
+var RunWithStartupTimeout = runWithStartupTimeout
+var ValidateRequired = validateRequired
+
+var RunWithPprof = runWithPprof
+
+var NewFanOutSentryClient = newFanOutSentryClient
+
+type FilteredSentryClient = filteredSentryClient
+
+func NewFilteredSentryClient(client libsentry.Client, filter func(err error) bool) FilteredSentryClient {
+	return filteredSentryClient{client: client, filter: filter}
+}
+
+var FormatBanner = formatBanner
+
+var ReloadOnSignalCh = reloadOnSignalCh
+
+var NewDelayedReadiness = newDelayedReadiness
+
+var ExitReason = exitReason
+
+var EnvFileEnviron = envFileEnviron
+
+var FlagNames = flagNames
+
+// ParseArgsAndEnv parses with a fresh flag.CommandLine, argument.ParseArgs defines its flags there.
+func ParseArgsAndEnv(ctx context.Context, data interface{}, args []string, explicitArgs map[string]bool, environ []string) error {
+	commandLineMux.Lock()
+	defer commandLineMux.Unlock()
+	commandLine := flag.CommandLine
+	defer func() {
+		flag.CommandLine = commandLine
+	}()
+	flag.CommandLine = flag.NewFlagSet(commandLine.Name(), flag.ContinueOnError)
+	flag.CommandLine.SetOutput(io.Discard)
+	return parseArgsAndEnv(ctx, data, args, explicitArgs, environ)
+}
+
+// MainCmdWithArgsAndEnv runs MainCmd with the given args and environ instead of the ones of the process.
+func MainCmdWithArgsAndEnv(ctx context.Context, app run.Runnable, args []string, environ []string, fns ...OptionsFn) int {
+	return runCmd(ctx, app, nil, args, func(ctx context.Context, data interface{}, args []string, explicitArgs map[string]bool) error {
+		return ParseArgsAndEnv(ctx, data, args, explicitArgs, environ)
+	}, fns...)
+}
+
+var NewAdditionalSentryClients = newAdditionalSentryClients
+
+func (f filteredSentryClient) Client() libsentry.Client {
+	return f.client
+}
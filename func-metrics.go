@@ -0,0 +1,69 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package service
+
+import (
+	"context"
+	stderrors "errors"
+
+	"github.com/bborbe/run"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type funcMetrics struct {
+	errors *prometheus.CounterVec
+	panics *prometheus.CounterVec
+}
+
+type funcMetricsContextKey struct{}
+
+// ContextWithFuncMetrics returns a context that lets Run and RunNamed count the errors and panics of their funcs
+// in service_function_errors_total and service_function_panics_total labeled by func name.
+// Without registerer ctx is returned unchanged and nothing is counted.
+func ContextWithFuncMetrics(ctx context.Context, registerer prometheus.Registerer) context.Context {
+	if registerer == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, funcMetricsContextKey{}, &funcMetrics{
+		errors: register(registerer, prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "function_errors_total",
+			Help:      "Number of errors returned by funcs of Run.",
+		}, []string{"name"})),
+		panics: register(registerer, prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "function_panics_total",
+			Help:      "Number of panics recovered in funcs of Run.",
+		}, []string{"name"})),
+	})
+}
+
+func funcMetricsFromContext(ctx context.Context) *funcMetrics {
+	metrics, _ := ctx.Value(funcMetricsContextKey{}).(*funcMetrics)
+	return metrics
+}
+
+// countFailures counts errors and panics of the funcs. Errors of canceling the context are not counted.
+func (f *funcMetrics) countFailures(names []string, funcs []run.Func) []run.Func {
+	result := make([]run.Func, len(funcs))
+	for i, fn := range funcs {
+		name := names[i]
+		fn := CatchPanic(fn)
+		result[i] = func(ctx context.Context) error {
+			err := fn(ctx)
+			if err == nil || isCanceledBy(ctx, err) {
+				return err
+			}
+			var panicErr *PanicError
+			if stderrors.As(err, &panicErr) {
+				f.panics.WithLabelValues(name).Inc()
+			} else {
+				f.errors.WithLabelValues(name).Inc()
+			}
+			return err
+		}
+	}
+	return result
+}
@@ -0,0 +1,51 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package service_test
+
+import (
+	"context"
+	stderrors "errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/bborbe/service"
+)
+
+var _ = Describe("ContextWithFuncMetrics", func() {
+	var ctx context.Context
+	var registry *prometheus.Registry
+	BeforeEach(func() {
+		registry = prometheus.NewRegistry()
+		ctx = service.ContextWithFuncMetrics(context.Background(), registry)
+	})
+	It("counts errors by func name", func() {
+		_ = service.RunNamed(ctx,
+			service.NamedFunc{Name: "consumer", Func: func(ctx context.Context) error {
+				return stderrors.New("banana")
+			}},
+			service.NamedFunc{Name: "server", Func: func(ctx context.Context) error {
+				<-ctx.Done()
+				return ctx.Err()
+			}},
+		)
+		Expect(metricLabels(registry, "service_function_errors_total")).To(ConsistOf(map[string]string{"name": "consumer"}))
+		Expect(metricValue(registry, "service_function_errors_total")).To(Equal(1.0))
+		Expect(metricLabels(registry, "service_function_panics_total")).To(BeEmpty())
+	})
+	It("counts panics by func name", func() {
+		_ = service.Run(ctx, func(ctx context.Context) error {
+			panic("banana")
+		})
+		Expect(metricLabels(registry, "service_function_panics_total")).To(ConsistOf(map[string]string{"name": "func 0"}))
+		Expect(metricValue(registry, "service_function_panics_total")).To(Equal(1.0))
+		Expect(metricLabels(registry, "service_function_errors_total")).To(BeEmpty())
+	})
+	It("registers nothing without registerer", func() {
+		ctx = service.ContextWithFuncMetrics(context.Background(), nil)
+		Expect(ctx).To(Equal(context.Background()))
+	})
+})
@@ -0,0 +1,53 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package service
+
+import (
+	"context"
+	"runtime"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// DefaultGoroutinePollInterval is used by WithGoroutineLimit if no interval is configured.
+const DefaultGoroutinePollInterval = 10 * time.Second
+
+// monitorGoroutines polls numGoroutine every interval and calls onExceeded
+// each time the count is above the soft limit. It returns when ctx is done.
+func monitorGoroutines(
+	ctx context.Context,
+	softLimit int,
+	interval time.Duration,
+	numGoroutine func() int,
+	onExceeded func(count int),
+) {
+	ticker := ClockFromContext(ctx).NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C():
+			if count := numGoroutine(); count > softLimit {
+				onExceeded(count)
+			}
+		}
+	}
+}
+
+// startGoroutineMonitor logs a warning and counts service_goroutine_limit_exceeded_total
+// while the number of goroutines is above the soft limit.
+func startGoroutineMonitor(ctx context.Context, softLimit int, interval time.Duration, registerer prometheus.Registerer) {
+	exceeded := register(registerer, prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Name:      "goroutine_limit_exceeded_total",
+		Help:      "Number of polls that found more goroutines than the soft limit.",
+	}))
+	go monitorGoroutines(ctx, softLimit, interval, runtime.NumGoroutine, func(count int) {
+		LoggerFromContext(ctx).Warningf("%d goroutines exceed soft limit %d", count, softLimit)
+		exceeded.Inc()
+	})
+}
@@ -0,0 +1,53 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package service_test
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/bborbe/service"
+)
+
+var _ = Describe("MonitorGoroutines", func() {
+	var ctx context.Context
+	var cancel context.CancelFunc
+	var count atomic.Int64
+	var exceeded chan int
+	var done chan struct{}
+	BeforeEach(func() {
+		ctx, cancel = context.WithCancel(context.Background())
+		count.Store(5)
+		exceeded = make(chan int, 100)
+		done = make(chan struct{})
+		go func() {
+			defer close(done)
+			service.MonitorGoroutines(ctx, 10, time.Millisecond, func() int {
+				return int(count.Load())
+			}, func(count int) {
+				exceeded <- count
+			})
+		}()
+	})
+	AfterEach(func() {
+		cancel()
+		<-done
+	})
+	It("does not warn below the soft limit", func() {
+		Consistently(exceeded, 20*time.Millisecond).ShouldNot(Receive())
+	})
+	It("warns if the soft limit is exceeded", func() {
+		count.Store(11)
+		Eventually(exceeded).Should(Receive(Equal(11)))
+	})
+	It("stops on shutdown", func() {
+		cancel()
+		Eventually(done).Should(BeClosed())
+	})
+})
@@ -0,0 +1,60 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package service
+
+import (
+	"os"
+	"strconv"
+	"time"
+)
+
+// DefaultGracePeriodMargin is subtracted from the grace period, so the service finishes its shutdown
+// before Kubernetes kills it.
+const DefaultGracePeriodMargin = 5 * time.Second
+
+// WithGracePeriodFromEnv sets the ShutdownTimeout to the grace period in the given env var minus
+// DefaultGracePeriodMargin, e.g. the terminationGracePeriodSeconds injected by the downward API.
+// The value is in seconds or a duration like 30s. A grace period not longer than the margin is halved.
+// If the env var is missing or invalid the ShutdownTimeout configured before is kept.
+func WithGracePeriodFromEnv(envVar string) OptionsFn {
+	return func(options *Options) {
+		value, ok := os.LookupEnv(envVar)
+		if !ok {
+			return
+		}
+		shutdownTimeout, err := shutdownTimeoutFromGracePeriod(value, DefaultGracePeriodMargin)
+		if err != nil {
+			options.Logger.Warningf("parse grace period of env %s failed: %v", envVar, err)
+			return
+		}
+		options.ShutdownTimeout = shutdownTimeout
+	}
+}
+
+// shutdownTimeoutFromGracePeriod returns the grace period minus the margin.
+func shutdownTimeoutFromGracePeriod(value string, margin time.Duration) (time.Duration, error) {
+	gracePeriod, err := parseGracePeriod(value)
+	if err != nil {
+		return 0, err
+	}
+	if gracePeriod <= margin {
+		return gracePeriod / 2, nil
+	}
+	return gracePeriod - margin, nil
+}
+
+func parseGracePeriod(value string) (time.Duration, error) {
+	if seconds, err := strconv.ParseUint(value, 10, 32); err == nil {
+		return time.Duration(seconds) * time.Second, nil
+	}
+	gracePeriod, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, err
+	}
+	if gracePeriod < 0 {
+		return 0, strconv.ErrRange
+	}
+	return gracePeriod, nil
+}
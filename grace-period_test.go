@@ -0,0 +1,62 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package service_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/bborbe/service"
+)
+
+var _ = DescribeTable("ShutdownTimeoutFromGracePeriod",
+	func(value string, expected time.Duration) {
+		shutdownTimeout, err := service.ShutdownTimeoutFromGracePeriod(value, 5*time.Second)
+		Expect(err).To(BeNil())
+		Expect(shutdownTimeout).To(Equal(expected))
+	},
+	Entry("seconds", "30", 25*time.Second),
+	Entry("duration", "1m", 55*time.Second),
+	Entry("not longer than margin", "4", 2*time.Second),
+	Entry("zero", "0", time.Duration(0)),
+)
+
+var _ = Describe("WithGracePeriodFromEnv", func() {
+	const envVar = "SERVICE_TEST_GRACE_PERIOD"
+	var options service.Options
+	JustBeforeEach(func() {
+		options = service.NewOptions(
+			service.WithShutdownTimeout(10*time.Second),
+			service.WithGracePeriodFromEnv(envVar),
+		)
+	})
+	Context("env set", func() {
+		BeforeEach(func() {
+			GinkgoT().Setenv(envVar, "30")
+		})
+		It("sets the shutdown timeout with margin", func() {
+			Expect(options.ShutdownTimeout).To(Equal(25 * time.Second))
+		})
+	})
+	Context("env missing", func() {
+		It("keeps the configured default", func() {
+			Expect(options.ShutdownTimeout).To(Equal(10 * time.Second))
+		})
+	})
+	Context("env invalid", func() {
+		BeforeEach(func() {
+			GinkgoT().Setenv(envVar, "banana")
+		})
+		It("keeps the configured default", func() {
+			Expect(options.ShutdownTimeout).To(Equal(10 * time.Second))
+		})
+	})
+	It("rejects a negative duration", func() {
+		_, err := service.ShutdownTimeoutFromGracePeriod("-1s", 5*time.Second)
+		Expect(err).NotTo(BeNil())
+	})
+})
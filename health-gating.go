@@ -0,0 +1,88 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package service
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"sync/atomic"
+
+	"github.com/bborbe/run"
+)
+
+// NewReadinessProbe returns a probe that is not ready.
+func NewReadinessProbe() *ReadinessProbe {
+	return &ReadinessProbe{}
+}
+
+// ReadinessProbe is a readiness flag that is served as HTTP probe.
+// It can also be combined with ReadinessCriteria as Predicate.
+type ReadinessProbe struct {
+	ready atomic.Bool
+}
+
+// Ready reports whether the probe is ready.
+func (r *ReadinessProbe) Ready() bool {
+	return r.ready.Load()
+}
+
+// SetReady sets the state of the probe.
+func (r *ReadinessProbe) SetReady(ready bool) {
+	r.ready.Store(ready)
+}
+
+// ServeHTTP responds 200 if ready, otherwise 503.
+func (r *ReadinessProbe) ServeHTTP(resp http.ResponseWriter, req *http.Request) {
+	if !r.Ready() {
+		http.Error(resp, "not ready", http.StatusServiceUnavailable)
+		return
+	}
+	resp.WriteHeader(http.StatusOK)
+	_, _ = resp.Write([]byte("OK"))
+}
+
+// GatedFunc is a func of RunWithHealthGating. It calls ready once its initialization is done.
+type GatedFunc func(ctx context.Context, ready func()) error
+
+// RunWithHealthGating runs all funcs like Run. The probe becomes ready once every func called ready
+// and becomes not ready again as soon as the shutdown begins. A func that never calls ready
+// keeps the probe not ready.
+func RunWithHealthGating(ctx context.Context, probe *ReadinessProbe, funcs ...GatedFunc) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var mux sync.Mutex
+	pending := len(funcs)
+	probe.SetReady(false)
+	stop := context.AfterFunc(ctx, func() {
+		mux.Lock()
+		defer mux.Unlock()
+		probe.SetReady(false)
+	})
+	defer stop()
+
+	wrapped := make([]run.Func, len(funcs))
+	for i, fn := range funcs {
+		var once sync.Once
+		ready := func() {
+			once.Do(func() {
+				mux.Lock()
+				defer mux.Unlock()
+				pending--
+				if pending == 0 && ctx.Err() == nil {
+					probe.SetReady(true)
+				}
+			})
+		}
+		wrapped[i] = func(ctx context.Context) error {
+			// the shutdown begins with the first returning func
+			defer cancel()
+			return fn(ctx, ready)
+		}
+	}
+	defer probe.SetReady(false)
+	return Run(ctx, wrapped...)
+}
@@ -0,0 +1,85 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package service_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/bborbe/service"
+)
+
+var _ = Describe("RunWithHealthGating", func() {
+	var ctx context.Context
+	var cancel context.CancelFunc
+	var probe *service.ReadinessProbe
+	var errCh chan error
+	BeforeEach(func() {
+		ctx, cancel = context.WithTimeout(context.Background(), time.Second)
+		probe = service.NewReadinessProbe()
+		errCh = make(chan error, 1)
+	})
+	AfterEach(func() {
+		cancel()
+	})
+	readyFunc := func(ctx context.Context, ready func()) error {
+		ready()
+		<-ctx.Done()
+		return nil
+	}
+	It("is not ready before the start", func() {
+		Expect(probe.Ready()).To(BeFalse())
+	})
+	It("becomes ready once all funcs signaled", func() {
+		go func() {
+			errCh <- service.RunWithHealthGating(ctx, probe, readyFunc, readyFunc)
+		}()
+		Eventually(probe.Ready).Should(BeTrue())
+		cancel()
+		Eventually(errCh).Should(Receive(BeNil()))
+		Expect(probe.Ready()).To(BeFalse())
+	})
+	It("stays not ready if a func never signals", func() {
+		go func() {
+			errCh <- service.RunWithHealthGating(ctx, probe, readyFunc, func(ctx context.Context, ready func()) error {
+				<-ctx.Done()
+				return nil
+			})
+		}()
+		Consistently(probe.Ready, 50*time.Millisecond).Should(BeFalse())
+		cancel()
+		Eventually(errCh).Should(Receive(BeNil()))
+	})
+	It("becomes not ready as soon as the shutdown begins", func() {
+		release := make(chan struct{})
+		go func() {
+			errCh <- service.RunWithHealthGating(ctx, probe, readyFunc, func(ctx context.Context, ready func()) error {
+				ready()
+				<-release
+				return nil
+			})
+		}()
+		Eventually(probe.Ready).Should(BeTrue())
+		cancel()
+		Eventually(probe.Ready).Should(BeFalse())
+		close(release)
+		Eventually(errCh).Should(Receive(BeNil()))
+	})
+	It("serves the state as HTTP probe", func() {
+		recorder := httptest.NewRecorder()
+		probe.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/readiness", nil))
+		Expect(recorder.Code).To(Equal(http.StatusServiceUnavailable))
+
+		probe.SetReady(true)
+		recorder = httptest.NewRecorder()
+		probe.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/readiness", nil))
+		Expect(recorder.Code).To(Equal(http.StatusOK))
+	})
+})
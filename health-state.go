@@ -0,0 +1,121 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package service
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+
+	"github.com/bborbe/run"
+)
+
+// HealthState holds the liveness and readiness of the service for Kubernetes probes.
+// Run sets it live once its funcs start and not ready as soon as its shutdown begins,
+// if the state was added to the context with ContextWithHealthState.
+type HealthState interface {
+	// SetReady sets the readiness.
+	SetReady(ready bool)
+	// SetLive sets the liveness.
+	SetLive(live bool)
+	// Ready reports whether the service accepts traffic.
+	Ready() bool
+	// Live reports whether the service is alive.
+	Live() bool
+	// Handler responds 200 if the service is live and ready, otherwise 503.
+	Handler() http.Handler
+	// LiveHandler responds 200 if the service is live, otherwise 503.
+	LiveHandler() http.Handler
+}
+
+// NewHealthState returns a HealthState that is neither live nor ready.
+func NewHealthState() HealthState {
+	return &healthState{}
+}
+
+type healthState struct {
+	ready atomic.Bool
+	live  atomic.Bool
+}
+
+func (h *healthState) SetReady(ready bool) {
+	h.ready.Store(ready)
+}
+
+func (h *healthState) SetLive(live bool) {
+	h.live.Store(live)
+}
+
+func (h *healthState) Ready() bool {
+	return h.ready.Load()
+}
+
+func (h *healthState) Live() bool {
+	return h.live.Load()
+}
+
+func (h *healthState) Handler() http.Handler {
+	return healthHandler(func() bool {
+		return h.Live() && h.Ready()
+	})
+}
+
+func (h *healthState) LiveHandler() http.Handler {
+	return healthHandler(h.Live)
+}
+
+func healthHandler(healthy func() bool) http.Handler {
+	return http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		if !healthy() {
+			http.Error(resp, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		resp.WriteHeader(http.StatusOK)
+		_, _ = resp.Write([]byte("OK"))
+	})
+}
+
+type healthStateContextKey struct{}
+
+// ContextWithHealthState returns a context that lets Run update the given HealthState.
+func ContextWithHealthState(ctx context.Context, healthState HealthState) context.Context {
+	return context.WithValue(ctx, healthStateContextKey{}, healthState)
+}
+
+// HealthStateFromContext returns the HealthState of the context or nil.
+func HealthStateFromContext(ctx context.Context) HealthState {
+	healthState, _ := ctx.Value(healthStateContextKey{}).(HealthState)
+	return healthState
+}
+
+// runWithHealthState cancels like CancelOnFirstFinishWait, or like CancelOnFirstErrorWait without cancelOnSuccess,
+// but owns the cancel to turn the HealthState not ready the moment the group is canceled.
+func runWithHealthState(ctx context.Context, healthState HealthState, cancelOnSuccess bool, funcs []run.Func) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	stop := context.AfterFunc(ctx, func() {
+		healthState.SetReady(false)
+	})
+	defer stop()
+
+	wrapped := make([]run.Func, len(funcs))
+	for i, fn := range decorateFuncs(funcs) {
+		wrapped[i] = func(ctx context.Context) error {
+			err := fn(ctx)
+			if err != nil || cancelOnSuccess {
+				// not ready before the remaining funcs see the cancel
+				healthState.SetReady(false)
+				cancel()
+			}
+			return err
+		}
+	}
+	healthState.SetLive(true)
+	if !cancelOnSuccess {
+		return run.CancelOnFirstErrorWait(ctx, wrapped...)
+	}
+	return run.CancelOnFirstFinishWait(ctx, wrapped...)
+}
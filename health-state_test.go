@@ -0,0 +1,101 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package service_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/bborbe/service"
+)
+
+var _ = Describe("HealthState", func() {
+	var healthState service.HealthState
+	BeforeEach(func() {
+		healthState = service.NewHealthState()
+	})
+	serve := func(handler http.Handler) int {
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+		return recorder.Code
+	}
+	It("is neither live nor ready", func() {
+		Expect(healthState.Live()).To(BeFalse())
+		Expect(healthState.Ready()).To(BeFalse())
+		Expect(serve(healthState.Handler())).To(Equal(http.StatusServiceUnavailable))
+		Expect(serve(healthState.LiveHandler())).To(Equal(http.StatusServiceUnavailable))
+	})
+	It("serves 200 if live and ready", func() {
+		healthState.SetLive(true)
+		healthState.SetReady(true)
+		Expect(serve(healthState.Handler())).To(Equal(http.StatusOK))
+		Expect(serve(healthState.LiveHandler())).To(Equal(http.StatusOK))
+	})
+	It("serves 503 if live but not ready", func() {
+		healthState.SetLive(true)
+		Expect(serve(healthState.Handler())).To(Equal(http.StatusServiceUnavailable))
+		Expect(serve(healthState.LiveHandler())).To(Equal(http.StatusOK))
+	})
+	It("returns nil without HealthState in context", func() {
+		Expect(service.HealthStateFromContext(context.Background())).To(BeNil())
+	})
+	Context("Run", func() {
+		var ctx context.Context
+		var cancel context.CancelFunc
+		var errCh chan error
+		BeforeEach(func() {
+			ctx, cancel = context.WithTimeout(context.Background(), time.Second)
+			ctx = service.ContextWithHealthState(ctx, healthState)
+			errCh = make(chan error, 1)
+		})
+		AfterEach(func() {
+			cancel()
+		})
+		It("sets live once the funcs start and not ready on shutdown", func() {
+			started := make(chan struct{})
+			finish := make(chan struct{})
+			readyOnShutdown := make(chan bool, 1)
+			go func() {
+				errCh <- service.Run(ctx,
+					func(ctx context.Context) error {
+						healthState.SetReady(true)
+						close(started)
+						<-finish
+						return nil
+					},
+					func(ctx context.Context) error {
+						<-ctx.Done()
+						readyOnShutdown <- healthState.Ready()
+						return nil
+					},
+				)
+			}()
+			<-started
+			Expect(healthState.Live()).To(BeTrue())
+			Expect(healthState.Ready()).To(BeTrue())
+			close(finish)
+			Eventually(errCh).Should(Receive(BeNil()))
+			Expect(readyOnShutdown).To(Receive(BeFalse()))
+		})
+		It("sets not ready if the context is canceled", func() {
+			go func() {
+				errCh <- service.Run(ctx, func(ctx context.Context) error {
+					healthState.SetReady(true)
+					<-ctx.Done()
+					return nil
+				})
+			}()
+			Eventually(healthState.Ready).Should(BeTrue())
+			cancel()
+			Eventually(errCh).Should(Receive(BeNil()))
+			Expect(healthState.Ready()).To(BeFalse())
+		})
+	})
+})
@@ -0,0 +1,105 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package service_test
+
+import (
+	"bytes"
+	"flag"
+	"io"
+	"net/http"
+	"os"
+	"sync"
+
+	"github.com/golang/glog"
+
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func metricLabels(gatherer prometheus.Gatherer, name string) []map[string]string {
+	families, err := gatherer.Gather()
+	Expect(err).To(BeNil())
+	var result []map[string]string
+	for _, family := range families {
+		if family.GetName() != name {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			labels := map[string]string{}
+			for _, label := range metric.GetLabel() {
+				labels[label.GetName()] = label.GetValue()
+			}
+			result = append(result, labels)
+		}
+	}
+	return result
+}
+
+func metricValue(gatherer prometheus.Gatherer, name string) float64 {
+	families, err := gatherer.Gather()
+	Expect(err).To(BeNil())
+	var result float64
+	for _, family := range families {
+		if family.GetName() != name {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			result += metric.GetCounter().GetValue()
+			result += metric.GetGauge().GetValue()
+			result += float64(metric.GetHistogram().GetSampleCount())
+		}
+	}
+	return result
+}
+
+type syncBuffer struct {
+	mux sync.Mutex
+	buf bytes.Buffer
+}
+
+func (s *syncBuffer) Write(p []byte) (int, error) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	return s.buf.Write(p)
+}
+
+func (s *syncBuffer) String() string {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	return s.buf.String()
+}
+
+type roundTripperFunc func(req *http.Request) (*http.Response, error)
+
+func (r roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return r(req)
+}
+
+// captureLog returns everything glog writes to stderr while fn runs.
+func captureLog(fn func()) string {
+	logtostderr := flag.Lookup("logtostderr").Value.String()
+	Expect(flag.Set("logtostderr", "true")).To(Succeed())
+	defer func() {
+		_ = flag.Set("logtostderr", logtostderr)
+	}()
+
+	reader, writer, err := os.Pipe()
+	Expect(err).To(BeNil())
+	stderr := os.Stderr
+	os.Stderr = writer
+	defer func() {
+		os.Stderr = stderr
+	}()
+
+	outputCh := make(chan string, 1)
+	go func() {
+		output, _ := io.ReadAll(reader)
+		outputCh <- string(output)
+	}()
+	fn()
+	glog.Flush()
+	Expect(writer.Close()).To(Succeed())
+	return <-outputCh
+}
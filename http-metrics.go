@@ -0,0 +1,100 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package service
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const unmatchedRoute = "unmatched"
+
+// HTTPMetricsMiddleware records request rate, errors and duration of all requests
+// labeled by method, route pattern and status code.
+// The route pattern is taken from the http.ServeMux that handled the request,
+// so the raw path never ends up in a label. The mux must be the handler wrapped by the middleware,
+// or be reached with the same *http.Request: the pattern set on a request copy, e.g. of
+// req.WithContext in a middleware between them, is not visible and the route is labeled unmatched.
+// NewHTTPServer applies it automatically with the registerer of ContextWithHTTPMetrics.
+// A handler wrapped a second time is counted only once.
+func HTTPMetricsMiddleware(registerer prometheus.Registerer) func(http.Handler) http.Handler {
+	requests := register(registerer, prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Subsystem: "http",
+		Name:      "requests_total",
+		Help:      "Total number of http requests.",
+	}, []string{"method", "path", "code"}))
+	durations := register(registerer, prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: metricsNamespace,
+		Subsystem: "http",
+		Name:      "request_duration_seconds",
+		Help:      "Duration of http requests in seconds.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "path", "code"}))
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+			if _, ok := resp.(*statusRecorder); ok {
+				// counted by the outer middleware
+				next.ServeHTTP(resp, req)
+				return
+			}
+			start := time.Now()
+			recorder := &statusRecorder{ResponseWriter: resp, status: http.StatusOK}
+			next.ServeHTTP(recorder, req)
+
+			path := req.Pattern
+			if path == "" {
+				path = unmatchedRoute
+			}
+			code := strconv.Itoa(recorder.status)
+			requests.WithLabelValues(req.Method, path, code).Inc()
+			durations.WithLabelValues(req.Method, path, code).Observe(time.Since(start).Seconds())
+		})
+	}
+}
+
+type httpMetricsContextKey struct{}
+
+// ContextWithHTTPMetrics returns a context that lets NewHTTPServer apply HTTPMetricsMiddleware
+// with the given registerer. Main adds its Registerer. Without registerer ctx is returned unchanged.
+func ContextWithHTTPMetrics(ctx context.Context, registerer prometheus.Registerer) context.Context {
+	if registerer == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, httpMetricsContextKey{}, registerer)
+}
+
+func httpMetricsFromContext(ctx context.Context) prometheus.Registerer {
+	registerer, _ := ctx.Value(httpMetricsContextKey{}).(prometheus.Registerer)
+	return registerer
+}
+
+type statusRecorder struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+}
+
+func (s *statusRecorder) WriteHeader(status int) {
+	if !s.wroteHeader {
+		s.status = status
+		s.wroteHeader = true
+	}
+	s.ResponseWriter.WriteHeader(status)
+}
+
+func (s *statusRecorder) Write(b []byte) (int, error) {
+	s.wroteHeader = true
+	return s.ResponseWriter.Write(b)
+}
+
+// Unwrap allows http.ResponseController to reach the original writer.
+func (s *statusRecorder) Unwrap() http.ResponseWriter {
+	return s.ResponseWriter
+}
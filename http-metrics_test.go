@@ -0,0 +1,120 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package service_test
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/bborbe/service"
+	"github.com/bborbe/service/servicetest"
+)
+
+var _ = Describe("HTTPMetricsMiddleware", func() {
+	var registry *prometheus.Registry
+	var handler http.Handler
+	BeforeEach(func() {
+		registry = prometheus.NewRegistry()
+		mux := http.NewServeMux()
+		mux.HandleFunc("GET /users/{id}", func(resp http.ResponseWriter, req *http.Request) {
+			resp.WriteHeader(http.StatusTeapot)
+		})
+		handler = service.HTTPMetricsMiddleware(registry)(mux)
+	})
+	It("labels requests with the route pattern instead of the raw path", func() {
+		for _, path := range []string{"/users/1", "/users/2"} {
+			handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+		}
+		labels := metricLabels(registry, "service_http_requests_total")
+		Expect(labels).To(HaveLen(1))
+		Expect(labels[0]).To(Equal(map[string]string{"method": "GET", "path": "GET /users/{id}", "code": "418"}))
+		Expect(metricValue(registry, "service_http_requests_total")).To(Equal(2.0))
+	})
+	It("labels unknown routes as unmatched", func() {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/unknown", nil))
+		labels := metricLabels(registry, "service_http_requests_total")
+		Expect(labels).To(HaveLen(1))
+		Expect(labels[0]).To(HaveKeyWithValue("path", "unmatched"))
+		Expect(labels[0]).To(HaveKeyWithValue("code", "404"))
+	})
+	It("records the request duration", func() {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/users/1", nil))
+		Expect(metricLabels(registry, "service_http_request_duration_seconds")).To(HaveLen(1))
+	})
+	It("can be created twice with the same registry", func() {
+		Expect(func() {
+			service.HTTPMetricsMiddleware(registry)
+		}).NotTo(Panic())
+	})
+	It("counts a request wrapped twice only once", func() {
+		handler = service.HTTPMetricsMiddleware(registry)(handler)
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/users/1", nil))
+		Expect(metricValue(registry, "service_http_requests_total")).To(Equal(1.0))
+	})
+})
+
+var _ = Describe("NewHTTPServer with ContextWithHTTPMetrics", func() {
+	var registry *prometheus.Registry
+	var mux *http.ServeMux
+	BeforeEach(func() {
+		registry = prometheus.NewRegistry()
+		mux = http.NewServeMux()
+		mux.HandleFunc("GET /users/{id}", func(resp http.ResponseWriter, req *http.Request) {
+			resp.WriteHeader(http.StatusOK)
+		})
+	})
+	serve := func(ctx context.Context) {
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		Expect(err).To(BeNil())
+		addr := listener.Addr().String()
+		Expect(listener.Close()).To(BeNil())
+		ctx, cancel := context.WithCancel(ctx)
+		errCh := make(chan error, 1)
+		go func() {
+			errCh <- service.NewHTTPServer(addr, mux)(ctx)
+		}()
+		Eventually(func() error {
+			resp, err := http.Get("http://" + addr + "/users/1")
+			if err != nil {
+				return err
+			}
+			return resp.Body.Close()
+		}).Should(Succeed())
+		cancel()
+		Eventually(errCh).Should(Receive(BeNil()))
+	}
+	It("records the requests of the server", func() {
+		serve(service.ContextWithHTTPMetrics(context.Background(), registry))
+		Expect(metricLabels(registry, "service_http_requests_total")).To(ContainElement(
+			HaveKeyWithValue("path", "GET /users/{id}"),
+		))
+	})
+	It("records nothing without registerer", func() {
+		serve(context.Background())
+		Expect(metricLabels(registry, "service_http_requests_total")).To(BeEmpty())
+	})
+	It("lets Main record the requests with its registerer", func() {
+		app := &mainApplication{
+			RunFunc: func(ctx context.Context) error {
+				serve(ctx)
+				return nil
+			},
+		}
+		Expect(service.Main(context.Background(), app, &app.SentryDSN, nil,
+			service.WithoutTimezoneOverride(),
+			service.WithRegisterer(registry),
+			service.WithSentryTransport(servicetest.NewSentryTransport()),
+		)).To(Equal(service.ExitCodeSuccess))
+		Expect(metricLabels(registry, "service_http_requests_total")).To(ContainElement(
+			HaveKeyWithValue("path", "GET /users/{id}"),
+		))
+	})
+})
@@ -0,0 +1,114 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package service
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+
+	"github.com/bborbe/errors"
+	"github.com/bborbe/run"
+)
+
+// DefaultHTTPShutdownTimeout is the time in-flight requests get to complete after the context was canceled.
+const DefaultHTTPShutdownTimeout = 5 * time.Second
+
+// ErrAddressInUse is matched by errors.Is for an AddressInUseError.
+var ErrAddressInUse = stderrors.New("address already in use")
+
+// AddressInUseError is returned by NewHTTPServer if the listen address is taken by another process.
+// Main exits with ExitCodeAddressInUse for it.
+type AddressInUseError struct {
+	// Addr that could not be bound.
+	Addr string
+	// Cause is the error of the listener.
+	Cause error
+}
+
+func (a *AddressInUseError) Error() string {
+	return fmt.Sprintf("listen on %s failed, address already in use by another process", a.Addr)
+}
+
+// Unwrap returns the error of the listener.
+func (a *AddressInUseError) Unwrap() error {
+	return a.Cause
+}
+
+// Is matches ErrAddressInUse.
+func (a *AddressInUseError) Is(target error) bool {
+	return target == ErrAddressInUse
+}
+
+// NewHTTPServer returns a func serving the handler on addr until ctx is canceled.
+// It relies on the given ctx instead of a nested cancel: on cancellation the server stops accepting
+// connections and in-flight requests get DefaultHTTPShutdownTimeout to complete before the func returns.
+// With the registerer of ContextWithHTTPMetrics, e.g. in Main, the handler is wrapped with HTTPMetricsMiddleware.
+// Pass the http.ServeMux directly to get the route patterns as labels.
+//
+//	service.Run(ctx, service.NewHTTPServer(":8080", router), consumer)
+func NewHTTPServer(addr string, handler http.Handler) run.Func {
+	return NewHTTPServerWithDrainTimeout(addr, handler, DefaultHTTPShutdownTimeout)
+}
+
+// NewHTTPServerWithDrainTimeout works like NewHTTPServer, but in-flight requests get the given drainTimeout.
+// Connections still open after it are closed and an error wrapping context.DeadlineExceeded is returned.
+func NewHTTPServerWithDrainTimeout(addr string, handler http.Handler, drainTimeout time.Duration) run.Func {
+	return func(ctx context.Context) error {
+		var listenConfig net.ListenConfig
+		// a canceled ctx shuts the server down right after the listen instead of failing it
+		listener, err := listenConfig.Listen(context.WithoutCancel(ctx), "tcp", addr)
+		if err != nil {
+			if stderrors.Is(err, syscall.EADDRINUSE) {
+				return &AddressInUseError{Addr: addr, Cause: err}
+			}
+			return errors.Wrapf(ctx, err, "listen on %s failed", addr)
+		}
+		handler := handler
+		if registerer := httpMetricsFromContext(ctx); registerer != nil {
+			handler = HTTPMetricsMiddleware(registerer)(handler)
+		}
+		server := &http.Server{
+			Handler:           handler,
+			ReadHeaderTimeout: 10 * time.Second,
+		}
+		drained := make(chan struct{})
+		var drainErr error
+		stop := context.AfterFunc(ctx, func() {
+			defer close(drained)
+			shutdownCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), drainTimeout)
+			defer cancel()
+			if err := server.Shutdown(shutdownCtx); err != nil {
+				// force close hung connections
+				_ = server.Close()
+				drainErr = errors.Wrapf(ctx, err, "drain http server on %s within %v failed", addr, drainTimeout)
+			}
+		})
+
+		logger := LoggerFromContext(ctx)
+		if logger.V(2) {
+			logger.Infof("http server listens on %s", listener.Addr())
+		}
+		if err := server.Serve(listener); err != nil && !stderrors.Is(err, http.ErrServerClosed) {
+			if !stop() {
+				<-drained
+			}
+			return errors.Wrapf(ctx, err, "serve http on %s failed", addr)
+		}
+		// Serve returns as soon as Shutdown begins, wait for in-flight requests
+		<-drained
+		if drainErr != nil {
+			return drainErr
+		}
+		if logger.V(2) {
+			logger.Infof("http server on %s stopped", addr)
+		}
+		return nil
+	}
+}
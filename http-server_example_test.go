@@ -0,0 +1,38 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package service_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/bborbe/service"
+)
+
+func ExampleNewHTTPServer() {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	router := http.NewServeMux()
+	router.HandleFunc("/", func(resp http.ResponseWriter, req *http.Request) {
+		_, _ = resp.Write([]byte("ok"))
+	})
+
+	// the server uses the context of Run, no nested cancel required
+	err := service.Run(
+		ctx,
+		service.NewHTTPServer("127.0.0.1:0", router),
+		func(ctx context.Context) error {
+			fmt.Println("work done")
+			return nil
+		},
+	)
+	fmt.Println("server stopped:", err)
+
+	// Output:
+	// work done
+	// server stopped: <nil>
+}
@@ -0,0 +1,139 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package service_test
+
+import (
+	"context"
+	stderrors "errors"
+	"io"
+	"net"
+	"net/http"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/bborbe/service"
+)
+
+var _ = Describe("NewHTTPServer", func() {
+	var ctx context.Context
+	var cancel context.CancelFunc
+	var addr string
+	BeforeEach(func() {
+		ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		Expect(err).To(BeNil())
+		addr = listener.Addr().String()
+		Expect(listener.Close()).To(BeNil())
+	})
+	AfterEach(func() {
+		cancel()
+	})
+	It("serves until the context is canceled", func() {
+		errCh := make(chan error, 1)
+		go func() {
+			errCh <- service.NewHTTPServer(addr, http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+				_, _ = resp.Write([]byte("banana"))
+			}))(ctx)
+		}()
+		var body []byte
+		Eventually(func() error {
+			resp, err := http.Get("http://" + addr)
+			if err != nil {
+				return err
+			}
+			defer resp.Body.Close()
+			body, err = io.ReadAll(resp.Body)
+			return err
+		}).Should(Succeed())
+		Expect(string(body)).To(Equal("banana"))
+		cancel()
+		Eventually(errCh).Should(Receive(BeNil()))
+	})
+	It("lets in-flight requests complete on cancellation", func() {
+		requestStarted := make(chan struct{})
+		release := make(chan struct{})
+		errCh := make(chan error, 1)
+		go func() {
+			errCh <- service.NewHTTPServer(addr, http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+				close(requestStarted)
+				<-release
+				_, _ = resp.Write([]byte("banana"))
+			}))(ctx)
+		}()
+		respCh := make(chan string, 1)
+		go func() {
+			defer GinkgoRecover()
+			var resp *http.Response
+			Eventually(func() (err error) {
+				resp, err = http.Get("http://" + addr)
+				return err
+			}).Should(Succeed())
+			defer resp.Body.Close()
+			body, err := io.ReadAll(resp.Body)
+			Expect(err).To(BeNil())
+			respCh <- string(body)
+		}()
+		Eventually(requestStarted).Should(BeClosed())
+
+		cancel()
+		Consistently(errCh, 100*time.Millisecond).ShouldNot(Receive())
+		_, err := net.DialTimeout("tcp", addr, time.Second)
+		Expect(err).NotTo(BeNil())
+
+		close(release)
+		Eventually(respCh).Should(Receive(Equal("banana")))
+		Eventually(errCh).Should(Receive(BeNil()))
+	})
+	It("closes hung connections after the drain timeout", func() {
+		requestStarted := make(chan struct{})
+		release := make(chan struct{})
+		defer close(release)
+		errCh := make(chan error, 1)
+		go func() {
+			errCh <- service.NewHTTPServerWithDrainTimeout(addr, http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+				close(requestStarted)
+				<-release
+			}), 100*time.Millisecond)(ctx)
+		}()
+		clientErrCh := make(chan error, 1)
+		go func() {
+			defer GinkgoRecover()
+			var err error
+			Eventually(func() error {
+				var conn net.Conn
+				conn, err = net.Dial("tcp", addr)
+				if err != nil {
+					return err
+				}
+				defer conn.Close()
+				_, _ = conn.Write([]byte("GET / HTTP/1.1\r\nHost: banana\r\n\r\n"))
+				_, err = io.ReadAll(conn)
+				return nil
+			}).Should(Succeed())
+			clientErrCh <- err
+		}()
+		Eventually(requestStarted).Should(BeClosed())
+
+		cancel()
+		var err error
+		Eventually(errCh).Should(Receive(&err))
+		Expect(stderrors.Is(err, context.DeadlineExceeded)).To(BeTrue())
+		Eventually(clientErrCh).Should(Receive())
+	})
+	It("returns an AddressInUseError if the address is taken", func() {
+		listener, err := net.Listen("tcp", addr)
+		Expect(err).To(BeNil())
+		defer listener.Close()
+
+		err = service.NewHTTPServer(addr, http.NotFoundHandler())(ctx)
+		var addressInUseError *service.AddressInUseError
+		Expect(stderrors.As(err, &addressInUseError)).To(BeTrue())
+		Expect(addressInUseError.Addr).To(Equal(addr))
+		Expect(stderrors.Is(err, service.ErrAddressInUse)).To(BeTrue())
+		Expect(service.ExitCode(err, nil)).To(Equal(service.ExitCodeAddressInUse))
+	})
+})
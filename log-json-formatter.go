@@ -0,0 +1,144 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+)
+
+// Keys of the fields written by the JSON log formatter.
+// Map them with WithLogFieldNames to the names required by the log schema.
+const (
+	LogFieldTimestamp = "ts"
+	LogFieldLevel     = "level"
+	LogFieldMessage   = "msg"
+	LogFieldError     = "error"
+)
+
+// LogEntry is a single structured log line.
+type LogEntry struct {
+	Time    time.Time
+	Level   string
+	Message string
+	Error   error
+}
+
+// JSONLogFormatter formats a LogEntry as single line JSON.
+type JSONLogFormatter interface {
+	Format(entry LogEntry) []byte
+}
+
+// NewJSONLogFormatter returns a formatter writing the fields ts, level, msg and error.
+// The given field names replace the default names, e.g. {"ts": "@timestamp", "level": "log.level"}.
+func NewJSONLogFormatter(fieldNames map[string]string) JSONLogFormatter {
+	names := map[string]string{
+		LogFieldTimestamp: LogFieldTimestamp,
+		LogFieldLevel:     LogFieldLevel,
+		LogFieldMessage:   LogFieldMessage,
+		LogFieldError:     LogFieldError,
+	}
+	for key, name := range fieldNames {
+		if _, ok := names[key]; ok && name != "" {
+			names[key] = name
+		}
+	}
+	return &jsonLogFormatter{
+		names: names,
+	}
+}
+
+type jsonLogFormatter struct {
+	names map[string]string
+}
+
+func (j *jsonLogFormatter) Format(entry LogEntry) []byte {
+	buf := &bytes.Buffer{}
+	buf.WriteByte('{')
+	j.writeField(buf, LogFieldTimestamp, entry.Time.UTC().Format(time.RFC3339Nano))
+	buf.WriteByte(',')
+	j.writeField(buf, LogFieldLevel, entry.Level)
+	buf.WriteByte(',')
+	j.writeField(buf, LogFieldMessage, entry.Message)
+	if entry.Error != nil {
+		buf.WriteByte(',')
+		j.writeField(buf, LogFieldError, entry.Error.Error())
+	}
+	buf.WriteString("}\n")
+	return buf.Bytes()
+}
+
+func (j *jsonLogFormatter) writeField(buf *bytes.Buffer, key string, value string) {
+	writeJSONString(buf, j.names[key])
+	buf.WriteByte(':')
+	writeJSONString(buf, value)
+}
+
+// writeJSONString writes value as JSON string without escaping <, > and &, which are common in log messages like "=>".
+func writeJSONString(buf *bytes.Buffer, value string) {
+	encoder := json.NewEncoder(buf)
+	encoder.SetEscapeHTML(false)
+	// encode of a string never fails
+	_ = encoder.Encode(value)
+	// remove the newline of the encoder
+	buf.Truncate(buf.Len() - 1)
+}
+
+// NewJSONLogger returns a Logger writing single line JSON in the format of the formatter to w.
+// The verbosity follows the -v flag of glog.
+func NewJSONLogger(w io.Writer, formatter JSONLogFormatter) Logger {
+	return &jsonLogger{
+		w:         w,
+		formatter: formatter,
+		now:       time.Now,
+	}
+}
+
+type jsonLogger struct {
+	mux       sync.Mutex
+	w         io.Writer
+	formatter JSONLogFormatter
+	now       func() time.Time
+}
+
+func (j *jsonLogger) Infof(format string, args ...interface{}) {
+	j.log("info", format, args)
+}
+
+func (j *jsonLogger) Warningf(format string, args ...interface{}) {
+	j.log("warning", format, args)
+}
+
+func (j *jsonLogger) Errorf(format string, args ...interface{}) {
+	j.log("error", format, args)
+}
+
+func (j *jsonLogger) V(level int) bool {
+	return bool(glog.V(glog.Level(level)))
+}
+
+// log writes the entry, the first error of the args becomes the error field.
+func (j *jsonLogger) log(level string, format string, args []interface{}) {
+	entry := LogEntry{
+		Time:    j.now(),
+		Level:   level,
+		Message: fmt.Sprintf(format, args...),
+	}
+	for _, arg := range args {
+		if err, ok := arg.(error); ok {
+			entry.Error = err
+			break
+		}
+	}
+	j.mux.Lock()
+	defer j.mux.Unlock()
+	_, _ = j.w.Write(j.formatter.Format(entry))
+}
@@ -0,0 +1,152 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package service_test
+
+import (
+	"context"
+	"encoding/json"
+	stderrors "errors"
+	"os"
+	"strings"
+	"syscall"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/bborbe/service"
+	"github.com/bborbe/service/servicetest"
+)
+
+var _ = Describe("JSONLogFormatter", func() {
+	var entry service.LogEntry
+	BeforeEach(func() {
+		entry = service.LogEntry{
+			Time:    time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC),
+			Level:   "error",
+			Message: "application failed",
+			Error:   stderrors.New("banana"),
+		}
+	})
+	It("writes single line json with default field names", func() {
+		line := service.NewJSONLogFormatter(nil).Format(entry)
+		Expect(string(line)).To(Equal(`{"ts":"2026-10-16T12:00:00Z","level":"error","msg":"application failed","error":"banana"}` + "\n"))
+	})
+	It("omits the error field without error", func() {
+		entry.Error = nil
+		line := service.NewJSONLogFormatter(nil).Format(entry)
+		Expect(string(line)).NotTo(ContainSubstring(`"error":`))
+	})
+	It("writes remapped field names", func() {
+		options := service.NewOptions(service.WithLogFieldNames(map[string]string{
+			service.LogFieldTimestamp: "@timestamp",
+			service.LogFieldLevel:     "log.level",
+			service.LogFieldMessage:   "message",
+			service.LogFieldError:     "error.message",
+		}))
+		line := service.NewJSONLogFormatter(options.LogFieldNames).Format(entry)
+		var fields map[string]string
+		Expect(json.Unmarshal(line, &fields)).To(BeNil())
+		Expect(fields).To(Equal(map[string]string{
+			"@timestamp":    "2026-10-16T12:00:00Z",
+			"log.level":     "error",
+			"message":       "application failed",
+			"error.message": "banana",
+		}))
+	})
+	It("keeps default names for unmapped fields", func() {
+		line := service.NewJSONLogFormatter(map[string]string{service.LogFieldMessage: "message"}).Format(entry)
+		var fields map[string]string
+		Expect(json.Unmarshal(line, &fields)).To(BeNil())
+		Expect(fields).To(HaveKey("message"))
+		Expect(fields).To(HaveKey("ts"))
+		Expect(fields).To(HaveKey("level"))
+	})
+})
+
+var _ = Describe("JSONLogger", func() {
+	var buf *syncBuffer
+	var logger service.Logger
+	BeforeEach(func() {
+		buf = &syncBuffer{}
+		logger = service.NewJSONLogger(buf, service.NewJSONLogFormatter(map[string]string{
+			service.LogFieldMessage: "message",
+		}))
+	})
+	parse := func(line string) map[string]string {
+		var fields map[string]string
+		Expect(json.Unmarshal([]byte(line), &fields)).To(Succeed())
+		return fields
+	}
+	It("writes info as json", func() {
+		logger.Infof("application %s", "started")
+		fields := parse(buf.String())
+		Expect(fields).To(HaveKeyWithValue("level", "info"))
+		Expect(fields).To(HaveKeyWithValue("message", "application started"))
+		Expect(fields).To(HaveKey("ts"))
+		Expect(fields).NotTo(HaveKey("error"))
+	})
+	It("writes the error argument as error field", func() {
+		logger.Errorf("application failed: %v", stderrors.New("banana"))
+		fields := parse(buf.String())
+		Expect(fields).To(HaveKeyWithValue("level", "error"))
+		Expect(fields).To(HaveKeyWithValue("error", "banana"))
+	})
+	It("writes one line per message", func() {
+		logger.Infof("a")
+		logger.Warningf("b")
+		Expect(strings.Count(buf.String(), "\n")).To(Equal(2))
+	})
+	It("logs the lifecycle of Main as json", func() {
+		app := &mainApplication{
+			RunFunc: func(ctx context.Context) error {
+				return nil
+			},
+		}
+		output := captureLog(func() {
+			Expect(service.Main(context.Background(), app, &app.SentryDSN, nil,
+				service.WithoutTimezoneOverride(),
+				service.WithRegisterer(nil),
+				service.WithSentryTransport(servicetest.NewSentryTransport()),
+				service.WithJSONLogging(),
+			)).To(Equal(service.ExitCodeSuccess))
+		})
+		Expect(output).To(ContainSubstring(`"level":"info","msg":"application started"`))
+		Expect(output).To(ContainSubstring(`"level":"info","msg":"application finished"`))
+	})
+	It("logs the timezone, startup and shutdown messages of Main as json", func() {
+		app := &mainApplication{
+			RunFunc: func(ctx context.Context) error {
+				return nil
+			},
+		}
+		output := captureLog(func() {
+			Expect(service.Main(context.Background(), app, &app.SentryDSN, nil,
+				service.WithoutTimezoneOverride(),
+				service.WithRegisterer(nil),
+				service.WithSentryTransport(servicetest.NewSentryTransport()),
+				service.WithShutdownPhase("checkpoint", func(ctx context.Context) error {
+					return stderrors.New("banana")
+				}, 1),
+				service.WithJSONLogging(),
+			)).To(Equal(service.ExitCodeSuccess))
+		})
+		Expect(output).To(ContainSubstring(`"level":"info","msg":"keep global timezone`))
+		Expect(output).To(ContainSubstring(`"level":"info","msg":"startup took`))
+		Expect(output).To(ContainSubstring(`"level":"warning","msg":"shutdown phase checkpoint failed: `))
+	})
+	It("logs a received signal as json", func() {
+		output := captureLog(func() {
+			signalCh := make(chan os.Signal, 1)
+			ctx := service.ContextWithSignalCh(context.Background(), signalCh, service.NewOptions(
+				service.WithRegisterer(nil),
+				service.WithJSONLogging(),
+			), func(code int) {})
+			signalCh <- syscall.SIGTERM
+			Eventually(ctx.Done()).Should(BeClosed())
+		})
+		Expect(output).To(ContainSubstring(`"level":"info","msg":"got signal terminated => cancel context "`))
+	})
+})
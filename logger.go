@@ -0,0 +1,71 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package service
+
+import (
+	"context"
+
+	"github.com/bborbe/run"
+	"github.com/golang/glog"
+)
+
+// Logger is the logging backend of Main, MainCmd, MainBasic, Run and the service.
+// Implement it to log with zap or slog instead of glog.
+type Logger interface {
+	Infof(format string, args ...interface{})
+	Warningf(format string, args ...interface{})
+	Errorf(format string, args ...interface{})
+	// V reports whether logs of the given verbosity are enabled.
+	V(level int) bool
+}
+
+// NewGlogLogger returns the default Logger writing to glog.
+func NewGlogLogger() Logger {
+	return glogLogger{}
+}
+
+type glogLogger struct{}
+
+func (glogLogger) Infof(format string, args ...interface{}) {
+	glog.InfoDepthf(1, format, args...)
+}
+
+func (glogLogger) Warningf(format string, args ...interface{}) {
+	glog.WarningDepthf(1, format, args...)
+}
+
+func (glogLogger) Errorf(format string, args ...interface{}) {
+	glog.ErrorDepthf(1, format, args...)
+}
+
+func (glogLogger) V(level int) bool {
+	return bool(glog.V(glog.Level(level)))
+}
+
+type loggerContextKey struct{}
+
+// ContextWithLogger returns a context that lets Run log with the given Logger.
+func ContextWithLogger(ctx context.Context, logger Logger) context.Context {
+	return context.WithValue(ctx, loggerContextKey{}, logger)
+}
+
+// LoggerFromContext returns the Logger of the context or the glog Logger.
+func LoggerFromContext(ctx context.Context) Logger {
+	if logger, ok := ctx.Value(loggerContextKey{}).(Logger); ok {
+		return logger
+	}
+	return NewGlogLogger()
+}
+
+// logErrors logs errors of fn as warning with the Logger of the context.
+func logErrors(fn run.Func) run.Func {
+	return func(ctx context.Context) error {
+		if err := fn(ctx); err != nil {
+			LoggerFromContext(ctx).Warningf("%v", err)
+			return err
+		}
+		return nil
+	}
+}
@@ -0,0 +1,127 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package service_test
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"syscall"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/bborbe/service"
+	"github.com/bborbe/service/servicetest"
+)
+
+type recordingLogger struct {
+	mux   sync.Mutex
+	lines []string
+}
+
+func (r *recordingLogger) record(level string, format string, args ...interface{}) {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	r.lines = append(r.lines, level+" "+fmt.Sprintf(format, args...))
+}
+
+func (r *recordingLogger) Infof(format string, args ...interface{}) {
+	r.record("I", format, args...)
+}
+
+func (r *recordingLogger) Warningf(format string, args ...interface{}) {
+	r.record("W", format, args...)
+}
+
+func (r *recordingLogger) Errorf(format string, args ...interface{}) {
+	r.record("E", format, args...)
+}
+
+func (r *recordingLogger) V(level int) bool {
+	return true
+}
+
+func (r *recordingLogger) Lines() []string {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	return append([]string{}, r.lines...)
+}
+
+var _ = Describe("Logger", func() {
+	var logger *recordingLogger
+	BeforeEach(func() {
+		logger = &recordingLogger{}
+	})
+	It("defaults to glog", func() {
+		Expect(service.LoggerFromContext(context.Background())).To(Equal(service.NewGlogLogger()))
+	})
+	It("returns the logger of the context", func() {
+		ctx := service.ContextWithLogger(context.Background(), logger)
+		Expect(service.LoggerFromContext(ctx)).To(BeIdenticalTo(logger))
+	})
+	It("logs errors of Run with the logger of the context", func() {
+		ctx := service.ContextWithLogger(context.Background(), logger)
+		Expect(service.Run(ctx, func(ctx context.Context) error {
+			return stderrors.New("banana")
+		})).NotTo(BeNil())
+		Expect(logger.Lines()).To(ContainElement("W banana"))
+	})
+	It("logs the lifecycle of Main", func() {
+		app := &mainApplication{
+			RunFunc: func(ctx context.Context) error {
+				return stderrors.New("banana")
+			},
+		}
+		Expect(service.Main(context.Background(), app, &app.SentryDSN, nil,
+			service.WithoutTimezoneOverride(),
+			service.WithRegisterer(nil),
+			service.WithSentryTransport(servicetest.NewSentryTransport()),
+			service.WithLogger(logger),
+		)).To(Equal(service.ExitCodeFailure))
+		Expect(logger.Lines()).To(ContainElements(
+			"I application started",
+			ContainSubstring("E application failed: banana"),
+		))
+	})
+	It("logs the framework messages of Main instead of glog", func() {
+		app := &mainApplication{
+			RunFunc: func(ctx context.Context) error {
+				return nil
+			},
+		}
+		Expect(service.Main(context.Background(), app, &app.SentryDSN, nil,
+			service.WithoutTimezoneOverride(),
+			service.WithRegisterer(nil),
+			service.WithSentryTransport(servicetest.NewSentryTransport()),
+			service.WithReadinessFile(filepath.Join(GinkgoT().TempDir(), "ready")),
+			service.WithLogger(logger),
+		)).To(Equal(service.ExitCodeSuccess))
+		Expect(logger.Lines()).To(ContainElements(
+			HavePrefix("I keep global timezone"),
+			HavePrefix("I readiness file"),
+			HavePrefix("I startup took"),
+		))
+	})
+	It("logs a received signal", func() {
+		signalCh := make(chan os.Signal, 1)
+		ctx := service.ContextWithSignalCh(context.Background(), signalCh, service.NewOptions(
+			service.WithRegisterer(nil),
+			service.WithLogger(logger),
+		), func(code int) {})
+		signalCh <- syscall.SIGTERM
+		Eventually(ctx.Done()).Should(BeClosed())
+		Expect(logger.Lines()).To(ContainElement("I got signal terminated => cancel context "))
+	})
+	It("logs the lifecycle of MainBasic", func() {
+		Expect(service.MainBasic(context.Background(), func(ctx context.Context) error {
+			return nil
+		}, service.WithoutTimezoneOverride(), service.WithLogger(logger))).To(Equal(service.ExitCodeSuccess))
+		Expect(logger.Lines()).To(ContainElements("I application started", "I application finished"))
+	})
+})
@@ -0,0 +1,96 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package service
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/bborbe/run"
+	libsentry "github.com/bborbe/sentry"
+	"github.com/golang/glog"
+)
+
+// MainBasic runs fn without argument parsing and Sentry until it returns or a shutdown signal is received.
+// WithShutdownTimeout bounds the wait for fn after the shutdown began, so a func ignoring
+// the cancellation does not block the exit. It returns the exit code.
+func MainBasic(
+	ctx context.Context,
+	fn run.Func,
+	fns ...OptionsFn,
+) int {
+	return runBasic(ctx, fn, nil, fns...)
+}
+
+// MainBasicWithSentry works like MainBasic, but captures an error or panic of fn to Sentry like the service of Main,
+// errors excluded by the options are skipped. If the DSN is empty it behaves exactly like MainBasic.
+func MainBasicWithSentry(
+	ctx context.Context,
+	fn run.Func,
+	sentryDSN *string,
+	fns ...OptionsFn,
+) int {
+	return runBasic(ctx, fn, sentryDSN, fns...)
+}
+
+func runBasic(
+	ctx context.Context,
+	fn run.Func,
+	sentryDSN *string,
+	fns ...OptionsFn,
+) int {
+	defer glog.Flush()
+	options := NewOptions(fns...)
+	setupProcess(options, "2")
+
+	ctx, cancel := context.WithCancel(ContextWithLogger(ctx, options.Logger))
+	defer cancel()
+
+	ctx = contextWithSig(ctx, options)
+	ctx = contextWithPanicRecovery(ctx, options)
+	ctx = ContextWithHTTPMetrics(ctx, options.Registerer)
+	shutdown := newShutdownDeadline(options.ShutdownTimeout)
+
+	runFn := CatchPanic(fn)
+	if sentryDSN != nil && *sentryDSN != "" {
+		sentryClientOptions, err := NewSentryClientOptions(ctx, *sentryDSN, http.DefaultTransport, options)
+		if err != nil {
+			options.Logger.Errorf("build Sentry client options failed: %v", err)
+			return ExitCodeSentrySetup
+		}
+		sentryClient, err := newSentryClient(ctx, *sentryClientOptions, options.Logger, options.ExcludeErrors...)
+		if err != nil {
+			options.Logger.Errorf("setting up Sentry failed: %+v", err)
+			return ExitCodeSentrySetup
+		}
+		ctx = ContextWithSentryClient(ctx, sentryClient)
+		defer func() {
+			shutdown.Start(options.Clock.Now())
+			flushed := sentryClient.Flush(shutdown.FlushTimeout(options.Clock.Now(), sentryFlushTimeout))
+			if shutdown.Enabled() && !flushed {
+				// Close flushes again and would exceed the shutdown budget
+				options.Logger.Warningf("flush sentry within shutdown budget failed")
+				return
+			}
+			_ = sentryClient.Close()
+		}()
+		runFn = NewServiceWithOptions(sentryClient, funcApplication(fn), options).Run
+	}
+
+	options.Logger.Infof("application started")
+	if err := shutdown.Run(ctx, runFn); err != nil {
+		options.Logger.Errorf("%v", err)
+		return exitCode(err, options.ExitCodeMapper)
+	}
+	options.Logger.Infof("application finished")
+	return ExitCodeSuccess
+}
+
+// funcApplication is an Application running the func.
+type funcApplication run.Func
+
+func (f funcApplication) Run(ctx context.Context, sentryClient libsentry.Client) error {
+	return f(ctx)
+}
@@ -0,0 +1,113 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package service_test
+
+import (
+	"context"
+	stderrors "errors"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/bborbe/service"
+	"github.com/bborbe/service/servicetest"
+)
+
+var _ = Describe("MainBasic", func() {
+	var ctx context.Context
+	var cancel context.CancelFunc
+	var release chan struct{}
+	BeforeEach(func() {
+		ctx, cancel = context.WithCancel(context.Background())
+		release = make(chan struct{})
+	})
+	AfterEach(func() {
+		close(release)
+		cancel()
+	})
+	It("returns 0 if fn succeeds", func() {
+		Expect(service.MainBasic(ctx, func(ctx context.Context) error {
+			return nil
+		}, service.WithoutTimezoneOverride())).To(Equal(0))
+	})
+	It("returns 1 if fn fails", func() {
+		Expect(service.MainBasic(ctx, func(ctx context.Context) error {
+			return stderrors.New("banana")
+		}, service.WithoutTimezoneOverride())).To(Equal(1))
+	})
+	It("returns the code of the ExitCodeMapper", func() {
+		Expect(service.MainBasic(ctx, func(ctx context.Context) error {
+			return stderrors.New("banana")
+		}, service.WithoutTimezoneOverride(), service.WithExitCodeMapper(func(err error) int {
+			return 42
+		}))).To(Equal(42))
+	})
+	It("returns the address in use code", func() {
+		Expect(service.MainBasic(ctx, func(ctx context.Context) error {
+			return service.ErrAddressInUse
+		}, service.WithoutTimezoneOverride())).To(Equal(service.ExitCodeAddressInUse))
+	})
+	It("exits bounded if fn ignores the cancellation", func() {
+		time.AfterFunc(10*time.Millisecond, cancel)
+		start := time.Now()
+		code := service.MainBasic(ctx, func(ctx context.Context) error {
+			<-release
+			return nil
+		}, service.WithoutTimezoneOverride(), service.WithShutdownTimeout(50*time.Millisecond))
+		Expect(code).To(Equal(1))
+		Expect(time.Since(start)).To(BeNumerically("<", time.Second))
+	})
+})
+
+var _ = Describe("MainBasicWithSentry", func() {
+	var ctx context.Context
+	var transport servicetest.SentryTransport
+	var sentryDSN string
+	var fns []service.OptionsFn
+	BeforeEach(func() {
+		ctx = context.Background()
+		transport = servicetest.NewSentryTransport()
+		sentryDSN = "https://key@sentry.example.com/1"
+		fns = []service.OptionsFn{
+			service.WithoutTimezoneOverride(),
+			service.WithSentryTransport(transport),
+		}
+	})
+	It("returns 0 without capturing if fn succeeds", func() {
+		Expect(service.MainBasicWithSentry(ctx, func(ctx context.Context) error {
+			return nil
+		}, &sentryDSN, fns...)).To(Equal(service.ExitCodeSuccess))
+		Expect(transport.Events()).To(BeEmpty())
+	})
+	It("captures the error of fn", func() {
+		Expect(service.MainBasicWithSentry(ctx, func(ctx context.Context) error {
+			return stderrors.New("banana")
+		}, &sentryDSN, fns...)).To(Equal(service.ExitCodeFailure))
+		Expect(transport.Events()).To(HaveLen(1))
+		Expect(transport.Events()[0].Exception[0].Value).To(Equal("banana"))
+	})
+	It("captures a panic of fn", func() {
+		Expect(service.MainBasicWithSentry(ctx, func(ctx context.Context) error {
+			panic("banana")
+		}, &sentryDSN, fns...)).To(Equal(service.ExitCodeFailure))
+		Expect(transport.Events()).To(HaveLen(1))
+	})
+	It("skips errors excluded by the options", func() {
+		Expect(service.MainBasicWithSentry(ctx, func(ctx context.Context) error {
+			return stderrors.New("banana")
+		}, &sentryDSN, append(fns, service.WithExcludeErrors(func(err error) bool {
+			return err.Error() == "banana"
+		}))...)).To(Equal(service.ExitCodeFailure))
+		Expect(transport.Events()).To(BeEmpty())
+	})
+	It("behaves like MainBasic with empty sentry dsn", func() {
+		sentryDSN = ""
+		Expect(service.MainBasicWithSentry(ctx, func(ctx context.Context) error {
+			return stderrors.New("banana")
+		}, &sentryDSN, fns...)).To(Equal(service.ExitCodeFailure))
+		Expect(transport.Events()).To(BeEmpty())
+	})
+})
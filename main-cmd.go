@@ -0,0 +1,112 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package service
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/bborbe/run"
+	libsentry "github.com/bborbe/sentry"
+	"github.com/getsentry/sentry-go"
+	"github.com/golang/glog"
+)
+
+// MainCmd runs a command line tool. Unlike Main it has no Sentry integration and
+// only logs its own lifecycle with V(3), so the output of the tool is not cluttered.
+// It parses the arguments into app like Main and returns the exit code.
+func MainCmd(
+	ctx context.Context,
+	app run.Runnable,
+	fns ...OptionsFn,
+) int {
+	return runCmd(ctx, app, nil, osArgs(), parseProcessArgs, fns...)
+}
+
+// MainCmdWithSentry works like MainCmd, but captures a returned error or panic to Sentry like Main.
+// If the DSN is empty after the arguments are parsed it behaves exactly like MainCmd.
+func MainCmdWithSentry(
+	ctx context.Context,
+	app run.Runnable,
+	sentryDSN *string,
+	fns ...OptionsFn,
+) int {
+	return runCmd(ctx, app, sentryDSN, osArgs(), parseProcessArgs, fns...)
+}
+
+// runCmd parses the args with the same precedence as Main, explicit arg > env > env file > default.
+func runCmd(
+	ctx context.Context,
+	app run.Runnable,
+	sentryDSN *string,
+	args []string,
+	parse func(ctx context.Context, data interface{}, args []string, explicitArgs map[string]bool) error,
+	fns ...OptionsFn,
+) int {
+	defer glog.Flush()
+	options := NewOptions(fns...)
+	setupProcess(options, "")
+
+	if err := parse(ctx, app, args, flagNames(args)); err != nil {
+		options.Logger.Errorf("parse app failed: %v", err)
+		return ExitCodeParseArguments
+	}
+
+	ctx, cancel := context.WithCancel(ContextWithLogger(ctx, options.Logger))
+	defer cancel()
+	ctx = contextWithPanicRecovery(ctx, options)
+
+	runFn := CatchPanic(app.Run)
+	if sentryDSN != nil && *sentryDSN != "" {
+		sentryClientOptions, err := NewSentryClientOptions(ctx, *sentryDSN, http.DefaultTransport, options)
+		if err != nil {
+			options.Logger.Errorf("build Sentry client options failed: %v", err)
+			return ExitCodeSentrySetup
+		}
+		sentryClient, err := newSentryClient(ctx, *sentryClientOptions, options.Logger, options.ExcludeErrors...)
+		if err != nil {
+			options.Logger.Errorf("setting up Sentry failed: %+v", err)
+			return ExitCodeSentrySetup
+		}
+		ctx = ContextWithSentryClient(ctx, sentryClient)
+		defer func() {
+			_ = sentryClient.Close()
+		}()
+		runFn = captureErrors(sentryClient, runFn)
+	}
+
+	ctx = contextWithSig(ctx, options)
+	shutdown := newShutdownDeadline(options.ShutdownTimeout)
+
+	if options.Logger.V(3) {
+		options.Logger.Infof("command started")
+	}
+	if err := shutdown.Run(ctx, runFn); err != nil {
+		options.Logger.Errorf("%v", err)
+		return exitCode(err, options.ExitCodeMapper)
+	}
+	if options.Logger.V(3) {
+		options.Logger.Infof("command finished")
+	}
+	return ExitCodeSuccess
+}
+
+// captureErrors captures every error returned by fn to Sentry.
+func captureErrors(sentryClient libsentry.Client, fn run.Func) run.Func {
+	return func(ctx context.Context) error {
+		if err := fn(ctx); err != nil {
+			sentryClient.CaptureException(
+				err,
+				&sentry.EventHint{
+					Context:           ctx,
+					OriginalException: err,
+				},
+				sentry.NewScope(),
+			)
+			return err
+		}
+		return nil
+	}
+}
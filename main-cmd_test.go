@@ -0,0 +1,147 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package service_test
+
+import (
+	"context"
+	stderrors "errors"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/bborbe/service"
+	"github.com/bborbe/service/mocks"
+)
+
+type cmdApplication struct {
+	SentryDSN string
+	RunFunc   func(ctx context.Context) error `json:"-"`
+}
+
+func (c *cmdApplication) Run(ctx context.Context) error {
+	return c.RunFunc(ctx)
+}
+
+var _ = Describe("MainCmd", func() {
+	var ctx context.Context
+	var app *cmdApplication
+	BeforeEach(func() {
+		ctx = context.Background()
+		app = &cmdApplication{
+			RunFunc: func(ctx context.Context) error {
+				return nil
+			},
+		}
+	})
+	It("returns 0 if the command succeeds", func() {
+		Expect(service.MainCmd(ctx, app, service.WithoutTimezoneOverride())).To(Equal(0))
+	})
+	It("returns 1 if the command fails", func() {
+		app.RunFunc = func(ctx context.Context) error {
+			return stderrors.New("banana")
+		}
+		Expect(service.MainCmd(ctx, app, service.WithoutTimezoneOverride())).To(Equal(1))
+	})
+	It("returns 1 if the command panics", func() {
+		app.RunFunc = func(ctx context.Context) error {
+			panic("banana")
+		}
+		Expect(service.MainCmd(ctx, app, service.WithoutTimezoneOverride())).To(Equal(1))
+	})
+	It("returns the code of the ExitCodeMapper", func() {
+		app.RunFunc = func(ctx context.Context) error {
+			return stderrors.New("banana")
+		}
+		Expect(service.MainCmd(ctx, app, service.WithoutTimezoneOverride(), service.WithExitCodeMapper(func(err error) int {
+			return 42
+		}))).To(Equal(42))
+	})
+	It("returns the address in use code", func() {
+		app.RunFunc = func(ctx context.Context) error {
+			return service.ErrAddressInUse
+		}
+		Expect(service.MainCmd(ctx, app, service.WithoutTimezoneOverride())).To(Equal(service.ExitCodeAddressInUse))
+	})
+	It("behaves like MainCmd with empty sentry dsn", func() {
+		app.RunFunc = func(ctx context.Context) error {
+			return stderrors.New("banana")
+		}
+		Expect(service.MainCmdWithSentry(ctx, app, &app.SentryDSN, service.WithoutTimezoneOverride())).To(Equal(1))
+	})
+})
+
+type cmdArgsApplication struct {
+	SentryDSN string `required:"false" arg:"sentry-dsn" env:"SENTRY_DSN" usage:"SentryDSN" default:"default"`
+}
+
+func (c *cmdArgsApplication) Run(ctx context.Context) error {
+	return nil
+}
+
+var _ = Describe("MainCmd precedence", func() {
+	var ctx context.Context
+	var path string
+	var app *cmdArgsApplication
+	BeforeEach(func() {
+		ctx = context.Background()
+		path = filepath.Join(GinkgoT().TempDir(), "secret")
+		Expect(os.WriteFile(path, []byte("file\n"), 0600)).To(Succeed())
+		app = &cmdArgsApplication{}
+	})
+	mainCmd := func(args []string, environ []string) string {
+		Expect(service.MainCmdWithArgsAndEnv(ctx, app, args, environ, service.WithoutTimezoneOverride())).To(Equal(service.ExitCodeSuccess))
+		return app.SentryDSN
+	}
+	It("uses the default", func() {
+		Expect(mainCmd(nil, nil)).To(Equal("default"))
+	})
+	It("prefers the env file over the default", func() {
+		Expect(mainCmd(nil, []string{"SENTRY_DSN_FILE=" + path})).To(Equal("file"))
+	})
+	It("prefers the env over the env file", func() {
+		Expect(mainCmd(nil, []string{"SENTRY_DSN=env", "SENTRY_DSN_FILE=" + path})).To(Equal("env"))
+	})
+	It("prefers an explicit arg over the env", func() {
+		Expect(mainCmd([]string{"-sentry-dsn=arg"}, []string{"SENTRY_DSN=env"})).To(Equal("arg"))
+	})
+	It("prefers an explicit arg over the env file", func() {
+		Expect(mainCmd([]string{"-sentry-dsn=arg"}, []string{"SENTRY_DSN_FILE=" + path})).To(Equal("arg"))
+	})
+	It("exits with the parse arguments code for an unreadable env file", func() {
+		app := &cmdArgsApplication{}
+		Expect(service.MainCmdWithArgsAndEnv(ctx, app, nil, []string{"SENTRY_DSN_FILE=" + filepath.Join(path, "missing")}, service.WithoutTimezoneOverride())).To(Equal(service.ExitCodeParseArguments))
+	})
+})
+
+var _ = Describe("CaptureErrors", func() {
+	var sentryClient *mocks.SentryClient
+	BeforeEach(func() {
+		sentryClient = &mocks.SentryClient{}
+	})
+	It("captures a returned error", func() {
+		err := service.CaptureErrors(sentryClient, func(ctx context.Context) error {
+			return stderrors.New("banana")
+		})(context.Background())
+		Expect(err).NotTo(BeNil())
+		Expect(sentryClient.CaptureExceptionCallCount()).To(Equal(1))
+	})
+	It("captures a recovered panic", func() {
+		err := service.CaptureErrors(sentryClient, service.CatchPanic(func(ctx context.Context) error {
+			panic("banana")
+		}))(context.Background())
+		var panicErr *service.PanicError
+		Expect(stderrors.As(err, &panicErr)).To(BeTrue())
+		Expect(sentryClient.CaptureExceptionCallCount()).To(Equal(1))
+	})
+	It("captures nothing on success", func() {
+		err := service.CaptureErrors(sentryClient, func(ctx context.Context) error {
+			return nil
+		})(context.Background())
+		Expect(err).To(BeNil())
+		Expect(sentryClient.CaptureExceptionCallCount()).To(Equal(0))
+	})
+})
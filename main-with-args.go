@@ -0,0 +1,95 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package service
+
+import (
+	"context"
+	"flag"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/bborbe/argument/v2"
+	"github.com/bborbe/errors"
+)
+
+// MainWithArgs works like Main, but parses the arguments of the application from the given args
+// instead of os.Args and the environment. The args contain only the flags of the application,
+// without the program name. Invalid or missing required arguments return ExitCodeParseArguments.
+func MainWithArgs(
+	ctx context.Context,
+	app Application,
+	sentryDSN *string,
+	sentryProxy *string,
+	args []string,
+	fns ...OptionsFn,
+) int {
+	return runMain(ctx, app, sentryDSN, sentryProxy, args, parseArgs, func(service Service) Service {
+		return service
+	}, fns...)
+}
+
+// commandLineMux guards the swap of flag.CommandLine, because argument.ParseArgs always defines its flags there.
+var commandLineMux sync.Mutex
+
+// parseArgs fills the data from args with a fresh flag.FlagSet, prints it like argument.Parse
+// and validates the required fields. The global flag.CommandLine is restored afterwards.
+func parseArgs(ctx context.Context, data interface{}, args []string, _ map[string]bool) error {
+	commandLineMux.Lock()
+	defer commandLineMux.Unlock()
+
+	commandLine := flag.CommandLine
+	defer func() {
+		flag.CommandLine = commandLine
+	}()
+	flag.CommandLine = flag.NewFlagSet(commandLine.Name(), flag.ContinueOnError)
+	flag.CommandLine.SetOutput(io.Discard)
+
+	if err := argument.ParseArgs(ctx, data, args); err != nil {
+		return errors.Wrapf(ctx, err, "parse args failed")
+	}
+	if err := argument.Print(ctx, data); err != nil {
+		return errors.Wrapf(ctx, err, "print failed")
+	}
+	if err := validateRequired(ctx, data); err != nil {
+		return errors.Wrapf(ctx, err, "validate required failed")
+	}
+	return nil
+}
+
+// parseProcessArgs works like argument.Parse, but with the given args instead of os.Args
+// and the values of X_FILE env vars for fields tagged env:"X". The precedence is
+// explicit arg > env > env file > args of the config file > default.
+func parseProcessArgs(ctx context.Context, data interface{}, args []string, explicitArgs map[string]bool) error {
+	return parseArgsAndEnv(ctx, data, args, explicitArgs, os.Environ())
+}
+
+// parseArgsAndEnv fills the data from args and environ, the explicitArgs are the flags given on the command line.
+func parseArgsAndEnv(ctx context.Context, data interface{}, args []string, explicitArgs map[string]bool, environ []string) error {
+	defaultValues, err := argument.DefaultValues(ctx, data)
+	if err != nil {
+		return errors.Wrapf(ctx, err, "default values failed")
+	}
+	if err := argument.Fill(ctx, data, defaultValues); err != nil {
+		return errors.Wrapf(ctx, err, "fill failed")
+	}
+	if err := argument.ParseArgs(ctx, data, args); err != nil {
+		return errors.Wrapf(ctx, err, "parse args failed")
+	}
+	environ, err = envFileEnviron(ctx, data, environ)
+	if err != nil {
+		return errors.Wrapf(ctx, err, "env file failed")
+	}
+	if err := argument.ParseEnv(ctx, data, environWithoutExplicitArgs(data, explicitArgs, environ)); err != nil {
+		return errors.Wrapf(ctx, err, "parse env failed")
+	}
+	if err := argument.Print(ctx, data); err != nil {
+		return errors.Wrapf(ctx, err, "print failed")
+	}
+	if err := validateRequired(ctx, data); err != nil {
+		return errors.Wrapf(ctx, err, "validate required failed")
+	}
+	return nil
+}
@@ -0,0 +1,128 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package service_test
+
+import (
+	"context"
+	stderrors "errors"
+	"flag"
+	"strings"
+
+	libsentry "github.com/bborbe/sentry"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/bborbe/service"
+	"github.com/bborbe/service/servicetest"
+)
+
+type argsApplication struct {
+	SentryDSN string `required:"false" arg:"sentry-dsn" usage:"SentryDSN"`
+	Name      string `required:"true" arg:"name" usage:"name of the banana"`
+	Fail      bool   `required:"false" arg:"fail" usage:"let the application fail"`
+}
+
+func (a *argsApplication) Run(ctx context.Context, sentryClient libsentry.Client) error {
+	if a.Fail {
+		return stderrors.New("banana")
+	}
+	return nil
+}
+
+var _ = Describe("MainWithArgs", func() {
+	DescribeTable("returns the exit code",
+		func(args []string, expected int) {
+			app := &argsApplication{}
+			Expect(service.MainWithArgs(
+				context.Background(),
+				app,
+				&app.SentryDSN,
+				nil,
+				args,
+				service.WithoutTimezoneOverride(),
+				service.WithRegisterer(nil),
+				service.WithSentryTransport(servicetest.NewSentryTransport()),
+			)).To(Equal(expected))
+		},
+		Entry("success", []string{"-name=banana"}, service.ExitCodeSuccess),
+		Entry("application error", []string{"-name=banana", "-fail"}, service.ExitCodeFailure),
+		Entry("missing required argument", []string{}, service.ExitCodeParseArguments),
+		Entry("unknown flag", []string{"-name=banana", "-apple"}, service.ExitCodeParseArguments),
+	)
+	It("fills the application from the args", func() {
+		app := &argsApplication{}
+		Expect(service.MainWithArgs(
+			context.Background(),
+			app,
+			&app.SentryDSN,
+			nil,
+			[]string{"-name=banana"},
+			service.WithoutTimezoneOverride(),
+			service.WithRegisterer(nil),
+			service.WithSentryTransport(servicetest.NewSentryTransport()),
+		)).To(Equal(service.ExitCodeSuccess))
+		Expect(app.Name).To(Equal("banana"))
+	})
+	DescribeTable("validates the configuration only",
+		func(args []string, expected int) {
+			app := &argsApplication{}
+			Expect(service.MainWithArgs(
+				context.Background(),
+				app,
+				nil,
+				nil,
+				args,
+				service.WithoutTimezoneOverride(),
+				service.WithRegisterer(nil),
+				service.WithValidateOnly(),
+			)).To(Equal(expected))
+		},
+		Entry("valid configuration without running the application", []string{"-name=banana", "-fail"}, service.ExitCodeSuccess),
+		Entry("missing required argument", []string{}, service.ExitCodeParseArguments),
+	)
+	It("keeps the global command line untouched", func() {
+		commandLine := flag.CommandLine
+		app := &argsApplication{}
+		service.MainWithArgs(context.Background(), app, &app.SentryDSN, nil, []string{"-name=banana"},
+			service.WithoutTimezoneOverride(),
+			service.WithRegisterer(nil),
+			service.WithSentryTransport(servicetest.NewSentryTransport()),
+		)
+		Expect(flag.CommandLine).To(BeIdenticalTo(commandLine))
+		Expect(flag.CommandLine.Lookup("name")).To(BeNil())
+	})
+})
+
+var _ = Describe("MainWithArgs WithExcludeErrors", func() {
+	var transport servicetest.SentryTransport
+	BeforeEach(func() {
+		transport = servicetest.NewSentryTransport()
+	})
+	mainWithArgs := func(fns ...service.OptionsFn) int {
+		app := &argsApplication{}
+		return service.MainWithArgs(
+			context.Background(),
+			app,
+			&app.SentryDSN,
+			nil,
+			[]string{"-name=banana", "-fail"},
+			append([]service.OptionsFn{
+				service.WithoutTimezoneOverride(),
+				service.WithRegisterer(nil),
+				service.WithSentryTransport(transport),
+			}, fns...)...,
+		)
+	}
+	It("captures the error of the application", func() {
+		Expect(mainWithArgs()).To(Equal(service.ExitCodeFailure))
+		Expect(transport.Events()).NotTo(BeEmpty())
+	})
+	It("captures nothing for a custom excluded error", func() {
+		Expect(mainWithArgs(service.WithExcludeErrors(func(err error) bool {
+			return strings.Contains(err.Error(), "banana")
+		}))).To(Equal(service.ExitCodeFailure))
+		Expect(transport.Events()).To(BeEmpty())
+	})
+})
@@ -6,15 +6,13 @@ package service
 
 import (
 	"context"
+	stderrors "errors"
 	"flag"
 	"net/http"
 	"os"
-	"os/signal"
 	"runtime"
-	"syscall"
-	"time"
 
-	"github.com/bborbe/argument/v2"
+	"github.com/bborbe/run"
 	libsentry "github.com/bborbe/sentry"
 	"github.com/getsentry/sentry-go"
 	"github.com/golang/glog"
@@ -31,26 +29,97 @@ func Main(
 	sentryDSN *string,
 	sentryProxy *string,
 	fns ...OptionsFn,
+) int {
+	return runMain(ctx, app, sentryDSN, sentryProxy, osArgs(), parseProcessArgs, func(service Service) Service {
+		return service
+	}, fns...)
+}
+
+// MainSupervised works like Main, but restarts the application according to the given policy if it fails.
+// Each failure is captured to Sentry. The restarts stop as soon as the service shuts down.
+func MainSupervised(
+	ctx context.Context,
+	app Application,
+	sentryDSN *string,
+	sentryProxy *string,
+	policy RestartPolicy,
+	fns ...OptionsFn,
+) int {
+	return runMain(ctx, app, sentryDSN, sentryProxy, osArgs(), parseProcessArgs, func(service Service) Service {
+		return NewRestartingService(service, policy)
+	}, fns...)
+}
+
+func runMain(
+	ctx context.Context,
+	app Application,
+	sentryDSN *string,
+	sentryProxy *string,
+	args []string,
+	parse func(ctx context.Context, data interface{}, args []string, explicitArgs map[string]bool) error,
+	wrapService func(service Service) Service,
+	fns ...OptionsFn,
 ) int {
 	defer glog.Flush()
-	glog.CopyStandardLogTo("info")
-	runtime.GOMAXPROCS(runtime.NumCPU())
-	_ = flag.Set("logtostderr", "true")
-	_ = flag.Set("v", "2")
+	options := NewOptions(fns...)
+	startup := newStartupTimer(options.Registerer, options.Clock.Now, options.Logger)
+	setupProcess(options, "2")
+	startup.Done(StartupPhaseSetup)
 
-	time.Local = time.UTC
-	glog.V(2).Infof("set global timezone to UTC")
+	// before the config file and early Sentry client, which log with the Logger of the context
+	ctx = ContextWithLogger(ctx, options.Logger)
 
-	if err := argument.Parse(ctx, app); err != nil {
-		glog.Errorf("parse app failed: %v", err)
-		return 4
+	var earlySentryClient libsentry.Client
+	if options.EarlySentryDSNEnv != "" {
+		earlySentryClient = newEarlySentryClient(ctx, options.EarlySentryDSNEnv)
+	}
+	// the args of the config file are no explicit args
+	explicitArgs := flagNames(args)
+	args, err := configFileArgs(ctx, app, args, options.ConfigFile)
+	if err == nil {
+		err = parseArguments(ctx, app, earlySentryClient, func(ctx context.Context, data interface{}) error {
+			return parse(ctx, data, args, explicitArgs)
+		})
+	}
+	if earlySentryClient != nil {
+		_ = earlySentryClient.Close()
+	}
+	if err != nil {
+		options.Logger.Errorf("parse app failed: %v", err)
+		if options.ValidationErrorWriter != nil {
+			if err := writeValidationErrors(options.ValidationErrorWriter, err); err != nil {
+				options.Logger.Warningf("write validation errors failed: %v", err)
+			}
+		}
+		return ExitCodeParseArguments
+	}
+	startup.Done(StartupPhaseArguments)
+	if options.Banner != nil {
+		options.Logger.Infof("%s", formatBanner(*options.Banner, app))
+	}
+	if options.ValidateOnly {
+		options.Logger.Infof("configuration valid")
+		return ExitCodeSuccess
 	}
 
-	options := NewOptions(fns...)
+	ctx, cancel := context.WithCancel(ContextWithClock(ctx, options.Clock))
+	defer cancel()
+
+	phase := newPhaseGauge(options.Registerer)
+	phase.Set(PhaseStarting)
+	defer phase.Set(PhaseStopped)
+
+	if options.GoroutineLimit > 0 {
+		startGoroutineMonitor(ctx, options.GoroutineLimit, options.GoroutinePollInterval, options.Registerer)
+	}
+
+	if options.DiagnosticDumpSignal != nil {
+		diagnosticDumpOnSignal(ctx, options.DiagnosticDumpSignal, options.DiagnosticDumpWriter)
+	}
 
 	if sentryDSN == nil {
-		glog.Errorf("sentryDSN args missing")
-		return 3
+		options.Logger.Errorf("sentryDSN args missing")
+		return ExitCodeSentryDSNMissing
 	}
 	httpTransport := http.DefaultTransport
 	if sentryProxy != nil {
@@ -58,60 +127,272 @@ func Main(
 			httpTransport,
 			*sentryProxy,
 		)
-		glog.V(2).Infof("use sentryProxy %s", *sentryProxy)
+		if options.Logger.V(2) {
+			options.Logger.Infof("use sentryProxy %s", *sentryProxy)
+		}
 	}
-	sentryClient, err := libsentry.NewClient(
+	if options.SentryConnectivityCheck && options.SentryTransport == nil {
+		if err := checkSentryConnectivity(ctx, *sentryDSN, httpTransport, DefaultSentryConnectivityTimeout); err != nil {
+			options.Logger.Errorf("sentry connectivity check failed: %v", err)
+			return ExitCodeSentrySetup
+		}
+	}
+	sentryFailureDetector := newSentryFailureDetector(httpTransport, options.SentryFailureThreshold, options.Registerer, options.Logger)
+	sentryClientOptions, err := NewSentryClientOptions(ctx, *sentryDSN, sentryFailureDetector, options)
+	if err != nil {
+		options.Logger.Errorf("build Sentry client options failed: %v", err)
+		return ExitCodeSentrySetup
+	}
+	sentryClient, err := newSentryClient(
 		ctx,
-		sentry.ClientOptions{
-			Dsn:              *sentryDSN,
-			TracesSampleRate: 1.0,
-			HTTPTransport:    httpTransport,
-		},
+		*sentryClientOptions,
+		options.Logger,
 		options.ExcludeErrors...,
 	)
 	if err != nil {
-		glog.Errorf("setting up Sentry failed: %+v", err)
-		return 2
+		options.Logger.Errorf("setting up Sentry failed: %+v", err)
+		return ExitCodeSentrySetup
+	}
+	sentryClient = newDegradingSentryClient(sentryClient, sentryFailureDetector, options.Logger)
+	if len(options.AdditionalSentryDSNs) > 0 {
+		additionalSentryClients, err := newAdditionalSentryClients(ctx, options.AdditionalSentryDSNs, httpTransport, options)
+		if err != nil {
+			options.Logger.Errorf("setting up additional Sentry failed: %+v", err)
+			return ExitCodeSentrySetup
+		}
+		sentryClient = newFanOutSentryClient(sentryClient, additionalSentryClients)
 	}
+	if options.ReloadableExcludes != nil {
+		sentryClient = newExcludingSentryClient(sentryClient, options.ReloadableExcludes, options.Logger)
+	}
+	if options.SeveritySampler != nil {
+		sentryClient = newSamplingSentryClient(sentryClient, options.SeveritySampler, defaultRandom, options.Logger)
+	}
+	// outermost, so the application can type assert it
+	scopedSentryClient := NewScopedSentryClient(sentryClient)
+	scopedSentryClient.ConfigureScope(func(scope *sentry.Scope) {
+		scope.SetContext(CommandLineContextKey, commandLineContext(app, args, options.SentryContextEnv, os.LookupEnv))
+	})
+	sentryClient = scopedSentryClient
+	ctx = ContextWithSentryClient(ctx, sentryClient)
+	startup.Done(StartupPhaseSentry)
+	shutdown := newShutdownDeadline(options.ShutdownTimeout)
 	defer func() {
-		_ = sentryClient.Flush(2 * time.Second)
+		shutdown.Start(options.Clock.Now())
+		flushed := sentryClient.Flush(shutdown.FlushTimeout(options.Clock.Now(), sentryFlushTimeout))
+		if shutdown.Enabled() && !flushed {
+			// Close flushes again and would exceed the shutdown budget
+			options.Logger.Warningf("flush sentry within shutdown budget failed")
+			return
+		}
 		_ = sentryClient.Close()
 	}()
+	tracerProvider, shutdownTracing, err := setupTracing(ctx, options.TracingEndpoint, options.TracingSetup)
+	if err != nil {
+		options.Logger.Errorf("setting up tracing failed: %v", err)
+		return ExitCodeTracingSetup
+	}
+	defer func() {
+		// runs before the sentry flush to capture a failing shutdown
+		shutdown.Start(options.Clock.Now())
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), shutdown.FlushTimeout(options.Clock.Now(), DefaultTracingShutdownTimeout))
+		defer cancel()
+		if err := shutdownTracing(ctx); err != nil {
+			options.Logger.Warningf("shutdown tracing failed: %v", err)
+		}
+	}()
+	if tracerProvider != nil {
+		ctx = ContextWithTracerProvider(ctx, tracerProvider)
+	}
+	if options.SentryFlushInterval > 0 {
+		stopFlush := startPeriodicFlush(ctx, sentryClient, options.SentryFlushInterval)
+		defer stopFlush()
+	}
+
+	if options.LogBreadcrumbs {
+		options.BreadcrumbRecorder = NewBreadcrumbRecorder(options.BreadcrumbLevel, DefaultBreadcrumbLimit)
+		restore, err := teeStderr(options.BreadcrumbRecorder)
+		if err != nil {
+			options.Logger.Warningf("record log breadcrumbs failed: %v", err)
+		} else {
+			defer restore()
+		}
+	}
 
-	service := NewService(
+	service := wrapService(NewServiceWithOptions(
 		sentryClient,
 		app,
-	)
+		options,
+	))
 
-	glog.V(0).Infof("application started")
-	if err := service.Run(contextWithSig(ctx)); err != nil {
-		glog.Error(err)
-		return 1
+	ctx = contextWithSig(ctx, options)
+	ctx, cancelRunTimeout := contextWithRunTimeout(ctx, options.RunTimeout)
+	defer cancelRunTimeout()
+	closerGroup := NewCloserGroup()
+	ctx = ContextWithCloserGroup(ctx, closerGroup)
+	healthState := options.HealthState
+	var delayedReadiness *delayedReadiness
+	if options.ReadinessDelay > 0 {
+		if healthState == nil {
+			healthState = NewHealthState()
+		}
+		delayedReadiness = newDelayedReadiness(healthState, options.ReadinessDelay)
+		healthState = delayedReadiness
+	}
+	var readiness *readinessSignal
+	if options.StartupTimeout > 0 {
+		if healthState == nil {
+			healthState = NewHealthState()
+		}
+		// outside of the delay, the application is ready before the delay elapsed
+		readiness = newReadinessSignal(healthState)
+		healthState = readiness
+	}
+	if healthState != nil {
+		ctx = ContextWithHealthState(ctx, healthState)
+	}
+	ctx = ContextWithFuncMetrics(ctx, options.FuncMetricsRegisterer)
+	ctx = ContextWithHTTPMetrics(ctx, options.Registerer)
+	ctx = contextWithPanicRecovery(ctx, options)
+	if options.UnexpectedCompletionHandler != nil {
+		ctx = ContextWithUnexpectedCompletionHandler(ctx, options.UnexpectedCompletionHandler)
+	}
+	if options.ReloadHandler != nil {
+		reloadOnSignal(ctx, reloadSignal, options.ReloadHandler, sentryClient, options.Logger)
+	}
+	if options.OnStart != nil {
+		if err := options.OnStart(ctx); err != nil {
+			options.Logger.Errorf("on start failed: %v", err)
+			closeCloserGroup(context.WithoutCancel(ctx), closerGroup, sentryClient)
+			if options.ExitReporting {
+				reportExit(ctx, scopedSentryClient, options.ExcludeErrors, ExitCodeOnStart, ExitReasonOnStart, err)
+			}
+			return ExitCodeOnStart
+		}
+		startup.Done(StartupPhaseOnStart)
+	}
+	stopDraining := context.AfterFunc(ctx, func() {
+		phase.Set(PhaseDraining)
+	})
+	defer stopDraining()
+	var onStarted []func()
+	if delayedReadiness != nil {
+		onStarted = append(onStarted, func() {
+			delayedReadiness.Start(ctx)
+		})
+	}
+	if options.ReadinessCriteria != nil {
+		if setter, ok := options.ReadinessCriteria.(clockSetter); ok {
+			setter.setClock(options.Clock)
+		}
+		onStarted = append(onStarted, func() {
+			options.ReadinessCriteria.Started(options.Clock.Now())
+		})
+		stop := context.AfterFunc(ctx, options.ReadinessCriteria.Draining)
+		defer stop()
+	}
+	if options.ReadinessFile != "" {
+		onStarted = append(onStarted, func() {
+			if err := createReadinessFile(ctx, options.ReadinessFile); err != nil {
+				options.Logger.Warningf("%v", err)
+			}
+		})
+	}
+	started := func() {
+		for _, fn := range onStarted {
+			fn()
+		}
 	}
-	glog.V(0).Infof("application finished")
-	return 0
-}
-
-func contextWithSig(ctx context.Context) context.Context {
-	ctxWithCancel, cancel := context.WithCancel(ctx)
-	go func() {
-		defer cancel()
 
-		signalCh := make(chan os.Signal, 1)
-		defer close(signalCh)
+	runFn := run.Func(service.Run)
+	if options.StartupSelfCheck != nil {
+		runFn = runWithStartupSelfCheck(runFn, options.StartupSelfCheck, options.StartupSelfCheckShutdown, started)
+	} else {
+		// inside the readiness file wrapper, which removes a stale file first
+		runFn = runWithStarted(runFn, started)
+	}
+	if options.ReadinessFile != "" {
+		runFn = runWithReadinessFile(runFn, options.ReadinessFile)
+	}
+	if readiness != nil {
+		runFn = runWithStartupTimeout(runFn, readiness.Signaled(), options.StartupTimeout)
+	}
+	if options.PprofListen != "" {
+		runFn = runWithPprof(runFn, options.PprofListen)
+	}
+	if options.Coordinator != nil {
+		runFn = drainCoordinator(options.Coordinator, runFn)
+	}
 
-		signal.Notify(signalCh, os.Interrupt, syscall.SIGINT, syscall.SIGTERM)
+	phase.Set(PhaseRunning)
+	startup.Done(StartupPhaseRunning)
+	startup.Log()
+	options.Logger.Infof("application started")
+	err = shutdown.Run(ctx, runFn)
+	var startupTimeoutErr *StartupTimeoutError
+	if stderrors.As(err, &startupTimeoutErr) {
+		sentryClient.CaptureException(
+			startupTimeoutErr,
+			&sentry.EventHint{
+				Context:           ctx,
+				OriginalException: startupTimeoutErr,
+			},
+			sentry.NewScope(),
+		)
+	}
+	// the shutdown begins with the cancel or the return of the application
+	shutdown.Start(options.Clock.Now())
+	if err := runShutdownPhases(context.WithoutCancel(ctx), options.ShutdownPhases, shutdown, options.Logger); err != nil {
+		sentryClient.CaptureException(
+			err,
+			&sentry.EventHint{
+				Context:           ctx,
+				OriginalException: err,
+			},
+			sentry.NewScope(),
+		)
+	}
+	if options.OnStop != nil {
+		if err := options.OnStop(context.WithoutCancel(ctx)); err != nil {
+			options.Logger.Warningf("on stop failed: %v", err)
+		}
+	}
+	closeCloserGroup(context.WithoutCancel(ctx), closerGroup, sentryClient)
+	newShutdownSLO(options.Registerer, options.ExpectedShutdownDuration, options.Logger).Observe(shutdown.Elapsed(options.Clock.Now()))
+	code := ExitCodeSuccess
+	switch {
+	case isRunTimeout(ctx, err):
+		options.Logger.Infof("application stopped after run timeout of %v", options.RunTimeout)
+		code = options.RunTimeoutExitCode
+	case err != nil:
+		options.Logger.Errorf("%v", err)
+		code = exitCode(err, options.ExitCodeMapper)
+	default:
+		options.Logger.Infof("application finished")
+	}
+	if options.ExitReporting {
+		// before the deferred flush
+		reportExit(ctx, scopedSentryClient, options.ExcludeErrors, code, exitReason(ctx, err, options.ExcludeErrors), err)
+	}
+	return code
+}
 
-		select {
-		case signal, ok := <-signalCh:
-			if !ok {
-				glog.V(2).Infof("signal channel closed => cancel context ")
-				return
-			}
-			glog.V(2).Infof("got signal %s => cancel context ", signal)
-		case <-ctx.Done():
+// setupProcess configures logging, GOMAXPROCS and the timezone for all entry points.
+// An empty verbosity keeps the -v flag untouched. Without FlagDefaults no flag is changed.
+func setupProcess(options Options, verbosity string) {
+	glog.CopyStandardLogTo("info")
+	if options.FlagDefaults {
+		_ = flag.Set("logtostderr", "true")
+		if verbosity != "" {
+			_ = flag.Set("v", verbosity)
 		}
-	}()
+	}
+
+	procs := maxProcs(options.MaxProcs, cgroupRoot)
+	runtime.GOMAXPROCS(procs)
+	if options.Logger.V(2) {
+		options.Logger.Infof("set GOMAXPROCS to %d", procs)
+	}
 
-	return ctxWithCancel
+	setTimezone(options.Timezone, options.Logger)
 }
@@ -0,0 +1,111 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package service_test
+
+import (
+	"context"
+	stderrors "errors"
+	"flag"
+
+	libsentry "github.com/bborbe/sentry"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/bborbe/service"
+	"github.com/bborbe/service/servicetest"
+)
+
+type mainApplication struct {
+	SentryDSN string
+	RunFunc   func(ctx context.Context) error `json:"-"`
+}
+
+func (m *mainApplication) Run(ctx context.Context, sentryClient libsentry.Client) error {
+	return m.RunFunc(ctx)
+}
+
+var _ = Describe("Main", func() {
+	var ctx context.Context
+	var app *mainApplication
+	var calls []string
+	var fns []service.OptionsFn
+	BeforeEach(func() {
+		ctx = context.Background()
+		calls = nil
+		app = &mainApplication{
+			RunFunc: func(ctx context.Context) error {
+				calls = append(calls, "run")
+				return nil
+			},
+		}
+		fns = []service.OptionsFn{
+			service.WithoutTimezoneOverride(),
+			service.WithRegisterer(nil),
+			service.WithSentryTransport(servicetest.NewSentryTransport()),
+			service.WithOnStart(func(ctx context.Context) error {
+				calls = append(calls, "start")
+				return nil
+			}),
+			service.WithOnStop(func(ctx context.Context) error {
+				Expect(ctx.Err()).To(BeNil())
+				calls = append(calls, "stop")
+				return nil
+			}),
+		}
+	})
+	It("calls the hooks around the application", func() {
+		Expect(service.Main(ctx, app, &app.SentryDSN, nil, fns...)).To(Equal(service.ExitCodeSuccess))
+		Expect(calls).To(Equal([]string{"start", "run", "stop"}))
+	})
+	It("runs the shutdown phases between the application and OnStop", func() {
+		fns = append(fns, service.WithShutdownPhase("checkpoint", func(ctx context.Context) error {
+			calls = append(calls, "checkpoint")
+			return nil
+		}, 1))
+		Expect(service.Main(ctx, app, &app.SentryDSN, nil, fns...)).To(Equal(service.ExitCodeSuccess))
+		Expect(calls).To(Equal([]string{"start", "run", "checkpoint", "stop"}))
+	})
+	It("calls OnStop if the application fails", func() {
+		app.RunFunc = func(ctx context.Context) error {
+			calls = append(calls, "run")
+			return stderrors.New("banana")
+		}
+		Expect(service.Main(ctx, app, &app.SentryDSN, nil, fns...)).To(Equal(service.ExitCodeFailure))
+		Expect(calls).To(Equal([]string{"start", "run", "stop"}))
+	})
+	It("does not start the application if OnStart fails", func() {
+		fns = append(fns, service.WithOnStart(func(ctx context.Context) error {
+			calls = append(calls, "start")
+			return stderrors.New("banana")
+		}))
+		Expect(service.Main(ctx, app, &app.SentryDSN, nil, fns...)).To(Equal(service.ExitCodeOnStart))
+		Expect(calls).To(Equal([]string{"start"}))
+	})
+})
+
+var _ = Describe("SetupProcess", func() {
+	var logtostderr string
+	var verbosity string
+	BeforeEach(func() {
+		logtostderr = flag.Lookup("logtostderr").Value.String()
+		verbosity = flag.Lookup("v").Value.String()
+		Expect(flag.Set("logtostderr", "false")).To(Succeed())
+		Expect(flag.Set("v", "0")).To(Succeed())
+	})
+	AfterEach(func() {
+		_ = flag.Set("logtostderr", logtostderr)
+		_ = flag.Set("v", verbosity)
+	})
+	It("sets the glog flags by default", func() {
+		service.SetupProcess(service.NewOptions(service.WithoutTimezoneOverride()), "2")
+		Expect(flag.Lookup("logtostderr").Value.String()).To(Equal("true"))
+		Expect(flag.Lookup("v").Value.String()).To(Equal("2"))
+	})
+	It("keeps the glog flags without flag defaults", func() {
+		service.SetupProcess(service.NewOptions(service.WithoutTimezoneOverride(), service.WithFlagDefaults(false)), "2")
+		Expect(flag.Lookup("logtostderr").Value.String()).To(Equal("false"))
+		Expect(flag.Lookup("v").Value.String()).To(Equal("0"))
+	})
+})
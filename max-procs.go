@@ -0,0 +1,70 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package service
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+const cgroupRoot = "/sys/fs/cgroup"
+
+// maxProcs returns the configured procs if set, otherwise the CPU quota of the cgroup
+// found below root rounded up, otherwise NumCPU.
+func maxProcs(configured int, root string) int {
+	if configured > 0 {
+		return configured
+	}
+	numCPU := runtime.NumCPU()
+	quota, ok := cgroupCPUQuota(root)
+	if !ok {
+		return numCPU
+	}
+	procs := int(math.Ceil(quota))
+	if procs < 1 {
+		return 1
+	}
+	if procs > numCPU {
+		return numCPU
+	}
+	return procs
+}
+
+// cgroupCPUQuota reads the CPU quota in cores from cgroup v2 cpu.max or cgroup v1 cpu.cfs_quota_us.
+// It returns false if no quota is configured.
+func cgroupCPUQuota(root string) (float64, bool) {
+	if content, err := os.ReadFile(filepath.Join(root, "cpu.max")); err == nil {
+		fields := strings.Fields(string(content))
+		if len(fields) != 2 || fields[0] == "max" {
+			return 0, false
+		}
+		return parseCPUQuota(fields[0], fields[1])
+	}
+	quota, err := os.ReadFile(filepath.Join(root, "cpu", "cpu.cfs_quota_us"))
+	if err != nil {
+		return 0, false
+	}
+	period, err := os.ReadFile(filepath.Join(root, "cpu", "cpu.cfs_period_us"))
+	if err != nil {
+		return 0, false
+	}
+	return parseCPUQuota(strings.TrimSpace(string(quota)), strings.TrimSpace(string(period)))
+}
+
+func parseCPUQuota(quota string, period string) (float64, bool) {
+	q, err := strconv.ParseFloat(quota, 64)
+	if err != nil || q <= 0 {
+		return 0, false
+	}
+	p, err := strconv.ParseFloat(period, 64)
+	if err != nil || p <= 0 {
+		return 0, false
+	}
+	return q / p, true
+}
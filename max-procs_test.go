@@ -0,0 +1,57 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package service_test
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/bborbe/service"
+)
+
+var _ = Describe("MaxProcs", func() {
+	var root string
+	BeforeEach(func() {
+		root = GinkgoT().TempDir()
+	})
+	write := func(name string, content string) {
+		path := filepath.Join(root, name)
+		Expect(os.MkdirAll(filepath.Dir(path), 0700)).To(Succeed())
+		Expect(os.WriteFile(path, []byte(content), 0600)).To(Succeed())
+	}
+	It("uses the configured procs", func() {
+		write("cpu.max", "100000 100000\n")
+		Expect(service.MaxProcs(3, root)).To(Equal(3))
+	})
+	It("uses NumCPU without cgroup", func() {
+		Expect(service.MaxProcs(0, root)).To(Equal(runtime.NumCPU()))
+	})
+	It("uses NumCPU without cgroup v2 quota", func() {
+		write("cpu.max", "max 100000\n")
+		Expect(service.MaxProcs(0, root)).To(Equal(runtime.NumCPU()))
+	})
+	It("uses NumCPU without cgroup v1 quota", func() {
+		write("cpu/cpu.cfs_quota_us", "-1\n")
+		write("cpu/cpu.cfs_period_us", "100000\n")
+		Expect(service.MaxProcs(0, root)).To(Equal(runtime.NumCPU()))
+	})
+	It("rounds a cgroup v2 quota up", func() {
+		write("cpu.max", "50000 100000\n")
+		Expect(service.MaxProcs(0, root)).To(Equal(1))
+	})
+	It("uses a cgroup v1 quota", func() {
+		write("cpu/cpu.cfs_quota_us", "100000\n")
+		write("cpu/cpu.cfs_period_us", "100000\n")
+		Expect(service.MaxProcs(0, root)).To(Equal(1))
+	})
+	It("caps the quota at NumCPU", func() {
+		write("cpu.max", "100000000 100000\n")
+		Expect(service.MaxProcs(0, root)).To(Equal(runtime.NumCPU()))
+	})
+})
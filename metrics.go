@@ -0,0 +1,31 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package service
+
+import (
+	stderrors "errors"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const metricsNamespace = "service"
+
+// register the collector and return the already registered one if an equal collector exists.
+// Without registerer the collector is returned unregistered.
+func register[T prometheus.Collector](registerer prometheus.Registerer, collector T) T {
+	if registerer == nil {
+		return collector
+	}
+	if err := registerer.Register(collector); err != nil {
+		var alreadyRegisteredError prometheus.AlreadyRegisteredError
+		if stderrors.As(err, &alreadyRegisteredError) {
+			if existing, ok := alreadyRegisteredError.ExistingCollector.(T); ok {
+				return existing
+			}
+		}
+		panic(err)
+	}
+	return collector
+}
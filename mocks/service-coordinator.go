@@ -0,0 +1,284 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package mocks
+
+import (
+	"context"
+	"sync"
+
+	"github.com/bborbe/run"
+	"github.com/bborbe/service"
+)
+
+type ServiceCoordinator struct {
+	ActiveStub        func() int
+	activeMutex       sync.RWMutex
+	activeArgsForCall []struct {
+	}
+	activeReturns struct {
+		result1 int
+	}
+	activeReturnsOnCall map[int]struct {
+		result1 int
+	}
+	RunStub        func(context.Context, ...run.Func) error
+	runMutex       sync.RWMutex
+	runArgsForCall []struct {
+		arg1 context.Context
+		arg2 []run.Func
+	}
+	runReturns struct {
+		result1 error
+	}
+	runReturnsOnCall map[int]struct {
+		result1 error
+	}
+	ShutdownStub        func()
+	shutdownMutex       sync.RWMutex
+	shutdownArgsForCall []struct {
+	}
+	WaitStub        func(context.Context) error
+	waitMutex       sync.RWMutex
+	waitArgsForCall []struct {
+		arg1 context.Context
+	}
+	waitReturns struct {
+		result1 error
+	}
+	waitReturnsOnCall map[int]struct {
+		result1 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *ServiceCoordinator) Active() int {
+	fake.activeMutex.Lock()
+	ret, specificReturn := fake.activeReturnsOnCall[len(fake.activeArgsForCall)]
+	fake.activeArgsForCall = append(fake.activeArgsForCall, struct {
+	}{})
+	stub := fake.ActiveStub
+	fakeReturns := fake.activeReturns
+	fake.recordInvocation("Active", []interface{}{})
+	fake.activeMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *ServiceCoordinator) ActiveCallCount() int {
+	fake.activeMutex.RLock()
+	defer fake.activeMutex.RUnlock()
+	return len(fake.activeArgsForCall)
+}
+
+func (fake *ServiceCoordinator) ActiveCalls(stub func() int) {
+	fake.activeMutex.Lock()
+	defer fake.activeMutex.Unlock()
+	fake.ActiveStub = stub
+}
+
+func (fake *ServiceCoordinator) ActiveReturns(result1 int) {
+	fake.activeMutex.Lock()
+	defer fake.activeMutex.Unlock()
+	fake.ActiveStub = nil
+	fake.activeReturns = struct {
+		result1 int
+	}{result1}
+}
+
+func (fake *ServiceCoordinator) ActiveReturnsOnCall(i int, result1 int) {
+	fake.activeMutex.Lock()
+	defer fake.activeMutex.Unlock()
+	fake.ActiveStub = nil
+	if fake.activeReturnsOnCall == nil {
+		fake.activeReturnsOnCall = make(map[int]struct {
+			result1 int
+		})
+	}
+	fake.activeReturnsOnCall[i] = struct {
+		result1 int
+	}{result1}
+}
+
+func (fake *ServiceCoordinator) Run(arg1 context.Context, arg2 ...run.Func) error {
+	fake.runMutex.Lock()
+	ret, specificReturn := fake.runReturnsOnCall[len(fake.runArgsForCall)]
+	fake.runArgsForCall = append(fake.runArgsForCall, struct {
+		arg1 context.Context
+		arg2 []run.Func
+	}{arg1, arg2})
+	stub := fake.RunStub
+	fakeReturns := fake.runReturns
+	fake.recordInvocation("Run", []interface{}{arg1, arg2})
+	fake.runMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2...)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *ServiceCoordinator) RunCallCount() int {
+	fake.runMutex.RLock()
+	defer fake.runMutex.RUnlock()
+	return len(fake.runArgsForCall)
+}
+
+func (fake *ServiceCoordinator) RunCalls(stub func(context.Context, ...run.Func) error) {
+	fake.runMutex.Lock()
+	defer fake.runMutex.Unlock()
+	fake.RunStub = stub
+}
+
+func (fake *ServiceCoordinator) RunArgsForCall(i int) (context.Context, []run.Func) {
+	fake.runMutex.RLock()
+	defer fake.runMutex.RUnlock()
+	argsForCall := fake.runArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *ServiceCoordinator) RunReturns(result1 error) {
+	fake.runMutex.Lock()
+	defer fake.runMutex.Unlock()
+	fake.RunStub = nil
+	fake.runReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *ServiceCoordinator) RunReturnsOnCall(i int, result1 error) {
+	fake.runMutex.Lock()
+	defer fake.runMutex.Unlock()
+	fake.RunStub = nil
+	if fake.runReturnsOnCall == nil {
+		fake.runReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.runReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *ServiceCoordinator) Shutdown() {
+	fake.shutdownMutex.Lock()
+	fake.shutdownArgsForCall = append(fake.shutdownArgsForCall, struct {
+	}{})
+	stub := fake.ShutdownStub
+	fake.recordInvocation("Shutdown", []interface{}{})
+	fake.shutdownMutex.Unlock()
+	if stub != nil {
+		fake.ShutdownStub()
+	}
+}
+
+func (fake *ServiceCoordinator) ShutdownCallCount() int {
+	fake.shutdownMutex.RLock()
+	defer fake.shutdownMutex.RUnlock()
+	return len(fake.shutdownArgsForCall)
+}
+
+func (fake *ServiceCoordinator) ShutdownCalls(stub func()) {
+	fake.shutdownMutex.Lock()
+	defer fake.shutdownMutex.Unlock()
+	fake.ShutdownStub = stub
+}
+
+func (fake *ServiceCoordinator) Wait(arg1 context.Context) error {
+	fake.waitMutex.Lock()
+	ret, specificReturn := fake.waitReturnsOnCall[len(fake.waitArgsForCall)]
+	fake.waitArgsForCall = append(fake.waitArgsForCall, struct {
+		arg1 context.Context
+	}{arg1})
+	stub := fake.WaitStub
+	fakeReturns := fake.waitReturns
+	fake.recordInvocation("Wait", []interface{}{arg1})
+	fake.waitMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *ServiceCoordinator) WaitCallCount() int {
+	fake.waitMutex.RLock()
+	defer fake.waitMutex.RUnlock()
+	return len(fake.waitArgsForCall)
+}
+
+func (fake *ServiceCoordinator) WaitCalls(stub func(context.Context) error) {
+	fake.waitMutex.Lock()
+	defer fake.waitMutex.Unlock()
+	fake.WaitStub = stub
+}
+
+func (fake *ServiceCoordinator) WaitArgsForCall(i int) context.Context {
+	fake.waitMutex.RLock()
+	defer fake.waitMutex.RUnlock()
+	argsForCall := fake.waitArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *ServiceCoordinator) WaitReturns(result1 error) {
+	fake.waitMutex.Lock()
+	defer fake.waitMutex.Unlock()
+	fake.WaitStub = nil
+	fake.waitReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *ServiceCoordinator) WaitReturnsOnCall(i int, result1 error) {
+	fake.waitMutex.Lock()
+	defer fake.waitMutex.Unlock()
+	fake.WaitStub = nil
+	if fake.waitReturnsOnCall == nil {
+		fake.waitReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.waitReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *ServiceCoordinator) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.activeMutex.RLock()
+	defer fake.activeMutex.RUnlock()
+	fake.runMutex.RLock()
+	defer fake.runMutex.RUnlock()
+	fake.shutdownMutex.RLock()
+	defer fake.shutdownMutex.RUnlock()
+	fake.waitMutex.RLock()
+	defer fake.waitMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *ServiceCoordinator) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ service.Coordinator = new(ServiceCoordinator)
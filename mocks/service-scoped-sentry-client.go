@@ -0,0 +1,412 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package mocks
+
+import (
+	"sync"
+	"time"
+
+	"github.com/bborbe/service"
+	sentry "github.com/getsentry/sentry-go"
+)
+
+type ServiceScopedSentryClient struct {
+	AddBreadcrumbStub        func(*sentry.Breadcrumb)
+	addBreadcrumbMutex       sync.RWMutex
+	addBreadcrumbArgsForCall []struct {
+		arg1 *sentry.Breadcrumb
+	}
+	CaptureExceptionStub        func(error, *sentry.EventHint, sentry.EventModifier) *sentry.EventID
+	captureExceptionMutex       sync.RWMutex
+	captureExceptionArgsForCall []struct {
+		arg1 error
+		arg2 *sentry.EventHint
+		arg3 sentry.EventModifier
+	}
+	captureExceptionReturns struct {
+		result1 *sentry.EventID
+	}
+	captureExceptionReturnsOnCall map[int]struct {
+		result1 *sentry.EventID
+	}
+	CaptureMessageStub        func(string, *sentry.EventHint, sentry.EventModifier) *sentry.EventID
+	captureMessageMutex       sync.RWMutex
+	captureMessageArgsForCall []struct {
+		arg1 string
+		arg2 *sentry.EventHint
+		arg3 sentry.EventModifier
+	}
+	captureMessageReturns struct {
+		result1 *sentry.EventID
+	}
+	captureMessageReturnsOnCall map[int]struct {
+		result1 *sentry.EventID
+	}
+	CloseStub        func() error
+	closeMutex       sync.RWMutex
+	closeArgsForCall []struct {
+	}
+	closeReturns struct {
+		result1 error
+	}
+	closeReturnsOnCall map[int]struct {
+		result1 error
+	}
+	ConfigureScopeStub        func(func(scope *sentry.Scope))
+	configureScopeMutex       sync.RWMutex
+	configureScopeArgsForCall []struct {
+		arg1 func(scope *sentry.Scope)
+	}
+	FlushStub        func(time.Duration) bool
+	flushMutex       sync.RWMutex
+	flushArgsForCall []struct {
+		arg1 time.Duration
+	}
+	flushReturns struct {
+		result1 bool
+	}
+	flushReturnsOnCall map[int]struct {
+		result1 bool
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *ServiceScopedSentryClient) AddBreadcrumb(arg1 *sentry.Breadcrumb) {
+	fake.addBreadcrumbMutex.Lock()
+	fake.addBreadcrumbArgsForCall = append(fake.addBreadcrumbArgsForCall, struct {
+		arg1 *sentry.Breadcrumb
+	}{arg1})
+	stub := fake.AddBreadcrumbStub
+	fake.recordInvocation("AddBreadcrumb", []interface{}{arg1})
+	fake.addBreadcrumbMutex.Unlock()
+	if stub != nil {
+		fake.AddBreadcrumbStub(arg1)
+	}
+}
+
+func (fake *ServiceScopedSentryClient) AddBreadcrumbCallCount() int {
+	fake.addBreadcrumbMutex.RLock()
+	defer fake.addBreadcrumbMutex.RUnlock()
+	return len(fake.addBreadcrumbArgsForCall)
+}
+
+func (fake *ServiceScopedSentryClient) AddBreadcrumbCalls(stub func(*sentry.Breadcrumb)) {
+	fake.addBreadcrumbMutex.Lock()
+	defer fake.addBreadcrumbMutex.Unlock()
+	fake.AddBreadcrumbStub = stub
+}
+
+func (fake *ServiceScopedSentryClient) AddBreadcrumbArgsForCall(i int) *sentry.Breadcrumb {
+	fake.addBreadcrumbMutex.RLock()
+	defer fake.addBreadcrumbMutex.RUnlock()
+	argsForCall := fake.addBreadcrumbArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *ServiceScopedSentryClient) CaptureException(arg1 error, arg2 *sentry.EventHint, arg3 sentry.EventModifier) *sentry.EventID {
+	fake.captureExceptionMutex.Lock()
+	ret, specificReturn := fake.captureExceptionReturnsOnCall[len(fake.captureExceptionArgsForCall)]
+	fake.captureExceptionArgsForCall = append(fake.captureExceptionArgsForCall, struct {
+		arg1 error
+		arg2 *sentry.EventHint
+		arg3 sentry.EventModifier
+	}{arg1, arg2, arg3})
+	stub := fake.CaptureExceptionStub
+	fakeReturns := fake.captureExceptionReturns
+	fake.recordInvocation("CaptureException", []interface{}{arg1, arg2, arg3})
+	fake.captureExceptionMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *ServiceScopedSentryClient) CaptureExceptionCallCount() int {
+	fake.captureExceptionMutex.RLock()
+	defer fake.captureExceptionMutex.RUnlock()
+	return len(fake.captureExceptionArgsForCall)
+}
+
+func (fake *ServiceScopedSentryClient) CaptureExceptionCalls(stub func(error, *sentry.EventHint, sentry.EventModifier) *sentry.EventID) {
+	fake.captureExceptionMutex.Lock()
+	defer fake.captureExceptionMutex.Unlock()
+	fake.CaptureExceptionStub = stub
+}
+
+func (fake *ServiceScopedSentryClient) CaptureExceptionArgsForCall(i int) (error, *sentry.EventHint, sentry.EventModifier) {
+	fake.captureExceptionMutex.RLock()
+	defer fake.captureExceptionMutex.RUnlock()
+	argsForCall := fake.captureExceptionArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *ServiceScopedSentryClient) CaptureExceptionReturns(result1 *sentry.EventID) {
+	fake.captureExceptionMutex.Lock()
+	defer fake.captureExceptionMutex.Unlock()
+	fake.CaptureExceptionStub = nil
+	fake.captureExceptionReturns = struct {
+		result1 *sentry.EventID
+	}{result1}
+}
+
+func (fake *ServiceScopedSentryClient) CaptureExceptionReturnsOnCall(i int, result1 *sentry.EventID) {
+	fake.captureExceptionMutex.Lock()
+	defer fake.captureExceptionMutex.Unlock()
+	fake.CaptureExceptionStub = nil
+	if fake.captureExceptionReturnsOnCall == nil {
+		fake.captureExceptionReturnsOnCall = make(map[int]struct {
+			result1 *sentry.EventID
+		})
+	}
+	fake.captureExceptionReturnsOnCall[i] = struct {
+		result1 *sentry.EventID
+	}{result1}
+}
+
+func (fake *ServiceScopedSentryClient) CaptureMessage(arg1 string, arg2 *sentry.EventHint, arg3 sentry.EventModifier) *sentry.EventID {
+	fake.captureMessageMutex.Lock()
+	ret, specificReturn := fake.captureMessageReturnsOnCall[len(fake.captureMessageArgsForCall)]
+	fake.captureMessageArgsForCall = append(fake.captureMessageArgsForCall, struct {
+		arg1 string
+		arg2 *sentry.EventHint
+		arg3 sentry.EventModifier
+	}{arg1, arg2, arg3})
+	stub := fake.CaptureMessageStub
+	fakeReturns := fake.captureMessageReturns
+	fake.recordInvocation("CaptureMessage", []interface{}{arg1, arg2, arg3})
+	fake.captureMessageMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *ServiceScopedSentryClient) CaptureMessageCallCount() int {
+	fake.captureMessageMutex.RLock()
+	defer fake.captureMessageMutex.RUnlock()
+	return len(fake.captureMessageArgsForCall)
+}
+
+func (fake *ServiceScopedSentryClient) CaptureMessageCalls(stub func(string, *sentry.EventHint, sentry.EventModifier) *sentry.EventID) {
+	fake.captureMessageMutex.Lock()
+	defer fake.captureMessageMutex.Unlock()
+	fake.CaptureMessageStub = stub
+}
+
+func (fake *ServiceScopedSentryClient) CaptureMessageArgsForCall(i int) (string, *sentry.EventHint, sentry.EventModifier) {
+	fake.captureMessageMutex.RLock()
+	defer fake.captureMessageMutex.RUnlock()
+	argsForCall := fake.captureMessageArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *ServiceScopedSentryClient) CaptureMessageReturns(result1 *sentry.EventID) {
+	fake.captureMessageMutex.Lock()
+	defer fake.captureMessageMutex.Unlock()
+	fake.CaptureMessageStub = nil
+	fake.captureMessageReturns = struct {
+		result1 *sentry.EventID
+	}{result1}
+}
+
+func (fake *ServiceScopedSentryClient) CaptureMessageReturnsOnCall(i int, result1 *sentry.EventID) {
+	fake.captureMessageMutex.Lock()
+	defer fake.captureMessageMutex.Unlock()
+	fake.CaptureMessageStub = nil
+	if fake.captureMessageReturnsOnCall == nil {
+		fake.captureMessageReturnsOnCall = make(map[int]struct {
+			result1 *sentry.EventID
+		})
+	}
+	fake.captureMessageReturnsOnCall[i] = struct {
+		result1 *sentry.EventID
+	}{result1}
+}
+
+func (fake *ServiceScopedSentryClient) Close() error {
+	fake.closeMutex.Lock()
+	ret, specificReturn := fake.closeReturnsOnCall[len(fake.closeArgsForCall)]
+	fake.closeArgsForCall = append(fake.closeArgsForCall, struct {
+	}{})
+	stub := fake.CloseStub
+	fakeReturns := fake.closeReturns
+	fake.recordInvocation("Close", []interface{}{})
+	fake.closeMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *ServiceScopedSentryClient) CloseCallCount() int {
+	fake.closeMutex.RLock()
+	defer fake.closeMutex.RUnlock()
+	return len(fake.closeArgsForCall)
+}
+
+func (fake *ServiceScopedSentryClient) CloseCalls(stub func() error) {
+	fake.closeMutex.Lock()
+	defer fake.closeMutex.Unlock()
+	fake.CloseStub = stub
+}
+
+func (fake *ServiceScopedSentryClient) CloseReturns(result1 error) {
+	fake.closeMutex.Lock()
+	defer fake.closeMutex.Unlock()
+	fake.CloseStub = nil
+	fake.closeReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *ServiceScopedSentryClient) CloseReturnsOnCall(i int, result1 error) {
+	fake.closeMutex.Lock()
+	defer fake.closeMutex.Unlock()
+	fake.CloseStub = nil
+	if fake.closeReturnsOnCall == nil {
+		fake.closeReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.closeReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *ServiceScopedSentryClient) ConfigureScope(arg1 func(scope *sentry.Scope)) {
+	fake.configureScopeMutex.Lock()
+	fake.configureScopeArgsForCall = append(fake.configureScopeArgsForCall, struct {
+		arg1 func(scope *sentry.Scope)
+	}{arg1})
+	stub := fake.ConfigureScopeStub
+	fake.recordInvocation("ConfigureScope", []interface{}{arg1})
+	fake.configureScopeMutex.Unlock()
+	if stub != nil {
+		fake.ConfigureScopeStub(arg1)
+	}
+}
+
+func (fake *ServiceScopedSentryClient) ConfigureScopeCallCount() int {
+	fake.configureScopeMutex.RLock()
+	defer fake.configureScopeMutex.RUnlock()
+	return len(fake.configureScopeArgsForCall)
+}
+
+func (fake *ServiceScopedSentryClient) ConfigureScopeCalls(stub func(func(scope *sentry.Scope))) {
+	fake.configureScopeMutex.Lock()
+	defer fake.configureScopeMutex.Unlock()
+	fake.ConfigureScopeStub = stub
+}
+
+func (fake *ServiceScopedSentryClient) ConfigureScopeArgsForCall(i int) func(scope *sentry.Scope) {
+	fake.configureScopeMutex.RLock()
+	defer fake.configureScopeMutex.RUnlock()
+	argsForCall := fake.configureScopeArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *ServiceScopedSentryClient) Flush(arg1 time.Duration) bool {
+	fake.flushMutex.Lock()
+	ret, specificReturn := fake.flushReturnsOnCall[len(fake.flushArgsForCall)]
+	fake.flushArgsForCall = append(fake.flushArgsForCall, struct {
+		arg1 time.Duration
+	}{arg1})
+	stub := fake.FlushStub
+	fakeReturns := fake.flushReturns
+	fake.recordInvocation("Flush", []interface{}{arg1})
+	fake.flushMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *ServiceScopedSentryClient) FlushCallCount() int {
+	fake.flushMutex.RLock()
+	defer fake.flushMutex.RUnlock()
+	return len(fake.flushArgsForCall)
+}
+
+func (fake *ServiceScopedSentryClient) FlushCalls(stub func(time.Duration) bool) {
+	fake.flushMutex.Lock()
+	defer fake.flushMutex.Unlock()
+	fake.FlushStub = stub
+}
+
+func (fake *ServiceScopedSentryClient) FlushArgsForCall(i int) time.Duration {
+	fake.flushMutex.RLock()
+	defer fake.flushMutex.RUnlock()
+	argsForCall := fake.flushArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *ServiceScopedSentryClient) FlushReturns(result1 bool) {
+	fake.flushMutex.Lock()
+	defer fake.flushMutex.Unlock()
+	fake.FlushStub = nil
+	fake.flushReturns = struct {
+		result1 bool
+	}{result1}
+}
+
+func (fake *ServiceScopedSentryClient) FlushReturnsOnCall(i int, result1 bool) {
+	fake.flushMutex.Lock()
+	defer fake.flushMutex.Unlock()
+	fake.FlushStub = nil
+	if fake.flushReturnsOnCall == nil {
+		fake.flushReturnsOnCall = make(map[int]struct {
+			result1 bool
+		})
+	}
+	fake.flushReturnsOnCall[i] = struct {
+		result1 bool
+	}{result1}
+}
+
+func (fake *ServiceScopedSentryClient) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.addBreadcrumbMutex.RLock()
+	defer fake.addBreadcrumbMutex.RUnlock()
+	fake.captureExceptionMutex.RLock()
+	defer fake.captureExceptionMutex.RUnlock()
+	fake.captureMessageMutex.RLock()
+	defer fake.captureMessageMutex.RUnlock()
+	fake.closeMutex.RLock()
+	defer fake.closeMutex.RUnlock()
+	fake.configureScopeMutex.RLock()
+	defer fake.configureScopeMutex.RUnlock()
+	fake.flushMutex.RLock()
+	defer fake.flushMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *ServiceScopedSentryClient) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ service.ScopedSentryClient = new(ServiceScopedSentryClient)
@@ -7,29 +7,717 @@ package service
 import (
 	"context"
 	stderrors "errors"
+	"io"
+	"os"
+	"syscall"
+	"time"
 
 	"github.com/bborbe/sentry"
+	"github.com/prometheus/client_golang/prometheus"
 )
 
 type Options struct {
 	ExcludeErrors sentry.ExcludeErrors
+	// ShutdownTimeout is the hard budget for the shutdown, starting with the cancellation of the context.
+	// Zero waits without limit.
+	ShutdownTimeout time.Duration
+	// ReadinessCriteria is marked as started and draining by Main.
+	ReadinessCriteria ReadinessCriteria
+	// CancelParentOnSignal is called in addition to the own cancel if a shutdown signal is received.
+	CancelParentOnSignal context.CancelFunc
+	// Coordinator is shut down and awaited by Main after the application returned.
+	Coordinator Coordinator
+	// DiagnosticDumpSignal triggers a diagnostic dump if set.
+	DiagnosticDumpSignal os.Signal
+	// DiagnosticDumpWriter receives the diagnostic dump.
+	DiagnosticDumpWriter io.Writer
+	// Registerer for all metrics of the service. Nil disables the metrics.
+	Registerer prometheus.Registerer
+	// TracesSampleRate of the Sentry client in range [0.0,1.0].
+	TracesSampleRate float64
+	// LogFieldNames maps the default field names of the JSON log format to custom names.
+	LogFieldNames map[string]string
+	// SentryEnvironment of the deployment, like staging or production.
+	SentryEnvironment string
+	// SentryRelease is the version of the service reported to Sentry.
+	SentryRelease string
+	// StartupSelfCheck runs once after the application started and must pass before the service is ready.
+	StartupSelfCheck StartupSelfCheck
+	// StartupSelfCheckShutdown stops the service if the StartupSelfCheck fails.
+	StartupSelfCheckShutdown bool
+	// SentryFailureThreshold of consecutive rejected Sentry requests after which capturing is disabled.
+	// Zero never disables capturing.
+	SentryFailureThreshold int
+	// BeforeSend is called with every Sentry event before it is sent.
+	BeforeSend BeforeSend
+	// SentryTags are added to every exception captured by the service.
+	SentryTags map[string]string
+	// ReadinessFile is created once the service is ready and removed on drain.
+	ReadinessFile string
+	// MaxProcs overrides GOMAXPROCS. Zero detects the CPU quota of the cgroup and falls back to NumCPU.
+	MaxProcs int
+	// Timezone Main sets time.Local to. Nil leaves time.Local untouched.
+	Timezone *time.Location
+	// EarlySentryDSNEnv is the env var the DSN is read from to capture panics during argument parsing.
+	EarlySentryDSNEnv string
+	// Signals that cancel the context of Main.
+	Signals []os.Signal
+	// ForceExitCode the process exits with if a shutdown signal is received a second time.
+	ForceExitCode int
+	// GoroutineLimit is the soft limit of goroutines above which a warning is logged. Zero disables the monitor.
+	GoroutineLimit int
+	// GoroutinePollInterval the goroutines are counted with.
+	GoroutinePollInterval time.Duration
+	// SentryFlushInterval the Sentry client is flushed with while the service runs. Zero disables it.
+	SentryFlushInterval time.Duration
+	// LogBreadcrumbs attaches the latest log lines as breadcrumbs to captured exceptions.
+	LogBreadcrumbs bool
+	// BreadcrumbLevel is the minimum level of log lines recorded as breadcrumbs.
+	BreadcrumbLevel BreadcrumbLevel
+	// BreadcrumbRecorder the service reads the breadcrumbs from. Main sets it if LogBreadcrumbs is enabled.
+	BreadcrumbRecorder BreadcrumbRecorder
+	// ExitCodeMapper decides the exit code of Main, MainCmd and MainBasic if the application fails.
+	ExitCodeMapper ExitCodeMapper
+	// SentryTransport replaces the HTTP transport of the Sentry client, e.g. to record events in tests.
+	SentryTransport SentryTransport
+	// HealthState Main adds to the context of the application, so Run updates it.
+	HealthState HealthState
+	// OnStart is called by Main before the application starts.
+	OnStart func(ctx context.Context) error
+	// OnStop is called by Main after the application stopped.
+	OnStop func(ctx context.Context) error
+	// RePanic lets a panic of the application continue after it was captured, instead of returning it as error.
+	RePanic bool
+	// ServiceName is added as extra service to exceptions captured by the service.
+	ServiceName string
+	// UnexpectedCompletionHandler Main adds to the context of the application, so Run reports to it.
+	UnexpectedCompletionHandler UnexpectedCompletionHandler
+	// Logger Main, MainCmd, MainBasic, the service and Run log with. Default is glog.
+	Logger Logger
+	// ReloadableExcludes provides exclusion rules evaluated on every capture in addition to ExcludeErrors.
+	ReloadableExcludes ExcludeErrorsProvider
+	// FlagDefaults lets the entry points set the glog flags logtostderr and v. Default is true.
+	FlagDefaults bool
+	// JSONLogging replaces the Logger with a JSON Logger writing to stderr with the LogFieldNames.
+	JSONLogging bool
+	// ShutdownPhases Main runs by ascending order after the application returned.
+	ShutdownPhases []ShutdownPhase
+	// SentryConnectivityCheck lets Main check that the Sentry host is reachable before the application starts.
+	SentryConnectivityCheck bool
+	// SeveritySampler decides the sample rate of each captured exception.
+	SeveritySampler SeveritySampler
+	// TracingEndpoint is passed to TracingSetup. Tracing is disabled if it is empty.
+	TracingEndpoint string
+	// TracingSetup creates the TracerProvider Main installs and shuts down on exit.
+	TracingSetup TracingSetup
+	// SentryContextEnv are the names of the env vars Main attaches to Sentry events with the command line.
+	SentryContextEnv []string
+	// ValidateOnly lets Main exit after the arguments were parsed and printed.
+	ValidateOnly bool
+	// ExpectedShutdownDuration is the soft shutdown SLO of Main. Zero disables it.
+	ExpectedShutdownDuration time.Duration
+	// ConfigFile is the path of the config file Main reads if no -config flag is given.
+	ConfigFile string
+	// StartupTimeout bounds the time until the application sets its HealthState ready. Zero disables it.
+	StartupTimeout time.Duration
+	// Clock is the source of time for the timers and deadlines of Main and the funcs of the package.
+	Clock Clock
+	// FuncMetricsRegisterer lets Run count the errors and panics of its funcs. Nil disables the metrics.
+	FuncMetricsRegisterer prometheus.Registerer
+	// ValidationErrorWriter receives the field errors as JSON if the arguments are invalid.
+	ValidationErrorWriter io.Writer
+	// PprofListen is the address of the pprof server of Main. Empty disables it.
+	PprofListen string
+	// PanicRecovery lets the service and Run recover panics. It is enabled by default.
+	PanicRecovery bool
+	// AdditionalSentryDSNs receive the exceptions matching their filter in addition to the primary DSN.
+	AdditionalSentryDSNs []AdditionalSentryDSN
+	// RunTimeout cancels the context of the application with context.DeadlineExceeded after it. Zero disables it.
+	RunTimeout time.Duration
+	// RunTimeoutExitCode is returned by Main if the application stopped because of the RunTimeout.
+	RunTimeoutExitCode int
+	// Banner is logged with the configuration after parsing the arguments. Nil logs no banner.
+	Banner *Banner
+	// ReloadHandler is called by Main on each SIGHUP. Nil leaves the signal untouched.
+	ReloadHandler ReloadHandler
+	// ReadinessDelay keeps the HealthState not ready for the duration after the application started.
+	ReadinessDelay time.Duration
+	// ExitReporting lets Main capture the exit code and reason as final Sentry message.
+	ExitReporting bool
+	// ErrorCallback is called by service.Run with each error of the application before it is captured, also for excluded errors.
+	ErrorCallback func(ctx context.Context, err error)
 }
 
 type OptionsFn func(option *Options)
 
+// DefaultExcludeErrors returns the errors excluded from Sentry and filtered by Run during the shutdown by default.
+func DefaultExcludeErrors() sentry.ExcludeErrors {
+	return sentry.ExcludeErrors{
+		func(err error) bool {
+			return stderrors.Is(err, context.Canceled)
+		},
+		func(err error) bool {
+			return stderrors.Is(err, context.DeadlineExceeded)
+		},
+	}
+}
+
 func NewOptions(fns ...OptionsFn) Options {
 	options := Options{
-		ExcludeErrors: sentry.ExcludeErrors{
-			func(err error) bool {
-				return stderrors.Is(err, context.Canceled)
-			},
-			func(err error) bool {
-				return stderrors.Is(err, context.DeadlineExceeded)
-			},
-		},
+		ExcludeErrors:          DefaultExcludeErrors(),
+		DiagnosticDumpWriter:   os.Stderr,
+		TracesSampleRate:       1.0,
+		SentryContextEnv:       DefaultSentryContextEnv,
+		Clock:                  NewClock(),
+		SentryFailureThreshold: DefaultSentryFailureThreshold,
+		Timezone:               time.UTC,
+		Signals:                []os.Signal{os.Interrupt, syscall.SIGINT, syscall.SIGTERM},
+		ForceExitCode:          DefaultForceExitCode,
+		GoroutinePollInterval:  DefaultGoroutinePollInterval,
+		BreadcrumbLevel:        DefaultBreadcrumbLevel,
+		Logger:                 NewGlogLogger(),
+		FlagDefaults:           true,
+		PanicRecovery:          true,
 	}
 	for _, fn := range fns {
 		fn(&options)
 	}
+	if options.JSONLogging {
+		options.Logger = NewJSONLogger(os.Stderr, NewJSONLogFormatter(options.LogFieldNames))
+	}
 	return options
 }
+
+// WithShutdownTimeout limits the time Main waits for the application to stop after the context is canceled.
+// The final Sentry flush only gets the remaining budget.
+func WithShutdownTimeout(shutdownTimeout time.Duration) OptionsFn {
+	return func(options *Options) {
+		options.ShutdownTimeout = shutdownTimeout
+	}
+}
+
+// WithReadinessCriteria lets Main mark the given criteria as started when the application starts
+// and as draining when the shutdown begins.
+func WithReadinessCriteria(readinessCriteria ReadinessCriteria) OptionsFn {
+	return func(options *Options) {
+		options.ReadinessCriteria = readinessCriteria
+	}
+}
+
+// WithCancelParentOnSignal calls the given cancel of the parent context if Main receives a shutdown signal,
+// so components embedding Main next to others all stop together.
+// The cancel is only called for a received signal. If the parent context is canceled first,
+// Main shuts down as usual and does not call the cancel again.
+// Calling a context.CancelFunc more than once is safe, so the parent may cancel itself at any time.
+func WithCancelParentOnSignal(cancel context.CancelFunc) OptionsFn {
+	return func(options *Options) {
+		options.CancelParentOnSignal = cancel
+	}
+}
+
+// WithCoordinator lets Main shut down all groups of the given coordinator after the application returned
+// and wait until they have drained before exiting.
+func WithCoordinator(coordinator Coordinator) OptionsFn {
+	return func(options *Options) {
+		options.Coordinator = coordinator
+	}
+}
+
+// WithDiagnosticDump writes the stacks of all goroutines, the memory stats and GOMAXPROCS
+// to the DiagnosticDumpWriter every time the given signal is received, without stopping the service.
+// The signal defaults to SIGQUIT if nil.
+// This overrides the default Go handling of the signal, which would dump all stacks and exit the process.
+func WithDiagnosticDump(sig os.Signal) OptionsFn {
+	return func(options *Options) {
+		if sig == nil {
+			sig = syscall.SIGQUIT
+		}
+		options.DiagnosticDumpSignal = sig
+	}
+}
+
+// WithDiagnosticDumpWriter sets where WithDiagnosticDump writes to, for example a file. Default is stderr.
+func WithDiagnosticDumpWriter(w io.Writer) OptionsFn {
+	return func(options *Options) {
+		options.DiagnosticDumpWriter = w
+	}
+}
+
+// WithRegisterer registers the metrics of the service, like service_phase, with the given registerer.
+// Without registerer no metrics are registered.
+func WithRegisterer(registerer prometheus.Registerer) OptionsFn {
+	return func(options *Options) {
+		options.Registerer = registerer
+	}
+}
+
+// WithTracesSampleRate sets the traces sample rate of the Sentry client. Default is 1.0.
+// Main exits with code 2 if the rate is not in range [0.0,1.0].
+func WithTracesSampleRate(tracesSampleRate float64) OptionsFn {
+	return func(options *Options) {
+		options.TracesSampleRate = tracesSampleRate
+	}
+}
+
+// WithLogFieldNames remaps the field names of the JSON log format,
+// e.g. {"ts": "@timestamp", "level": "log.level", "msg": "message"} for ECS.
+// Keys are LogFieldTimestamp, LogFieldLevel, LogFieldMessage and LogFieldError.
+func WithLogFieldNames(logFieldNames map[string]string) OptionsFn {
+	return func(options *Options) {
+		options.LogFieldNames = logFieldNames
+	}
+}
+
+// WithSentryEnvironment tags all Sentry events with the given deployment environment.
+// An empty environment leaves it unset.
+func WithSentryEnvironment(sentryEnvironment string) OptionsFn {
+	return func(options *Options) {
+		options.SentryEnvironment = sentryEnvironment
+	}
+}
+
+// WithStartupSelfCheck runs the given check once after the application started.
+// The ReadinessCriteria is only marked as started after the check passed,
+// so a failing check keeps the service not ready.
+// With shutdownOnFailure a failing check also stops the service with an error.
+func WithStartupSelfCheck(selfCheck StartupSelfCheck, shutdownOnFailure bool) OptionsFn {
+	return func(options *Options) {
+		options.StartupSelfCheck = selfCheck
+		options.StartupSelfCheckShutdown = shutdownOnFailure
+	}
+}
+
+// WithSentryRelease sets the release reported to Sentry, e.g. a version injected with
+// -ldflags "-X main.version=...". An empty release leaves it unset.
+func WithSentryRelease(sentryRelease string) OptionsFn {
+	return func(options *Options) {
+		options.SentryRelease = sentryRelease
+	}
+}
+
+// WithSentryReleaseFromBuild sets the release reported to Sentry to ReadBuildVersion.
+func WithSentryReleaseFromBuild() OptionsFn {
+	return WithSentryRelease(ReadBuildVersion())
+}
+
+// WithSentryFailureThreshold sets after how many consecutive requests rejected by Sentry with a 4xx status
+// capturing is disabled for the rest of the process. Zero never disables capturing.
+func WithSentryFailureThreshold(sentryFailureThreshold int) OptionsFn {
+	return func(options *Options) {
+		options.SentryFailureThreshold = sentryFailureThreshold
+	}
+}
+
+// WithBeforeSend scrubs or drops Sentry events before they are sent.
+// Returning nil from beforeSend drops the event.
+func WithBeforeSend(beforeSend BeforeSend) OptionsFn {
+	return func(options *Options) {
+		options.BeforeSend = beforeSend
+	}
+}
+
+// WithSentryTags adds the given tags, like cluster or region, to every exception captured by the service.
+// Tags already set on the event are kept.
+func WithSentryTags(sentryTags map[string]string) OptionsFn {
+	return func(options *Options) {
+		if options.SentryTags == nil {
+			options.SentryTags = make(map[string]string, len(sentryTags))
+		}
+		for key, value := range sentryTags {
+			options.SentryTags[key] = value
+		}
+	}
+}
+
+// WithReadinessFile creates the file at path once the service is ready and removes it
+// as soon as the service drains, for orchestrators probing readiness by file existence.
+func WithReadinessFile(path string) OptionsFn {
+	return func(options *Options) {
+		options.ReadinessFile = path
+	}
+}
+
+// WithMaxProcs sets GOMAXPROCS to n instead of detecting the CPU quota of the container.
+func WithMaxProcs(n int) OptionsFn {
+	return func(options *Options) {
+		options.MaxProcs = n
+	}
+}
+
+// WithTimezone lets Main set time.Local to loc instead of UTC.
+// time.Local is a global read without synchronization, so it is set before the application starts
+// and must not be changed concurrently by the application.
+func WithTimezone(loc *time.Location) OptionsFn {
+	return func(options *Options) {
+		options.Timezone = loc
+	}
+}
+
+// WithoutTimezoneOverride leaves time.Local as configured by the environment, e.g. by TZ.
+func WithoutTimezoneOverride() OptionsFn {
+	return WithTimezone(nil)
+}
+
+// WithEarlySentry creates a minimal Sentry client with the DSN of the given env var, e.g. SENTRY_DSN,
+// before the arguments are parsed, so a panic during argument parsing is captured.
+func WithEarlySentry(dsnEnv string) OptionsFn {
+	return func(options *Options) {
+		options.EarlySentryDSNEnv = dsnEnv
+	}
+}
+
+// WithSignals replaces the signals that cancel the context of Main, by default SIGINT and SIGTERM.
+func WithSignals(sigs ...os.Signal) OptionsFn {
+	return func(options *Options) {
+		options.Signals = sigs
+	}
+}
+
+// WithForceExitCode sets the code the process exits with immediately
+// if a shutdown signal is received a second time while shutting down.
+func WithForceExitCode(code int) OptionsFn {
+	return func(options *Options) {
+		options.ForceExitCode = code
+	}
+}
+
+// WithGoroutineLimit monitors the number of goroutines while the service runs and logs a warning
+// and counts service_goroutine_limit_exceeded_total each time it is above the soft limit.
+func WithGoroutineLimit(soft int) OptionsFn {
+	return func(options *Options) {
+		options.GoroutineLimit = soft
+	}
+}
+
+// WithGoroutinePollInterval sets how often the goroutines are counted for WithGoroutineLimit.
+func WithGoroutinePollInterval(interval time.Duration) OptionsFn {
+	return func(options *Options) {
+		options.GoroutinePollInterval = interval
+	}
+}
+
+// WithSentryFlushInterval flushes the Sentry client every interval while the service runs,
+// so captured events appear promptly. By default Main relies on the batching of Sentry.
+func WithSentryFlushInterval(interval time.Duration) OptionsFn {
+	return func(options *Options) {
+		options.SentryFlushInterval = interval
+	}
+}
+
+// WithLogBreadcrumbs attaches the latest log lines as breadcrumbs to exceptions captured by the service.
+func WithLogBreadcrumbs() OptionsFn {
+	return func(options *Options) {
+		options.LogBreadcrumbs = true
+	}
+}
+
+// WithBreadcrumbLevel sets the minimum level of log lines recorded as breadcrumbs, warning by default.
+func WithBreadcrumbLevel(minLevel BreadcrumbLevel) OptionsFn {
+	return func(options *Options) {
+		options.BreadcrumbLevel = minLevel
+	}
+}
+
+// WithExitCodeMapper lets Main, MainCmd and MainBasic return the exit code of the mapper if the application fails,
+// so shell scripts can branch on the failure class. Codes 2-7 are reserved by the framework.
+func WithExitCodeMapper(mapper ExitCodeMapper) OptionsFn {
+	return func(options *Options) {
+		options.ExitCodeMapper = mapper
+	}
+}
+
+// WithSentryTransport sends all Sentry events to the given transport instead of the network,
+// e.g. servicetest.NewSentryTransport to assert the payload in tests.
+// The sentry proxy and the failure detection only apply to the default HTTP transport.
+func WithSentryTransport(transport SentryTransport) OptionsFn {
+	return func(options *Options) {
+		options.SentryTransport = transport
+	}
+}
+
+// WithHealthState adds the given HealthState to the context of the application.
+// Run sets it live once its funcs start and not ready as soon as the shutdown begins.
+func WithHealthState(healthState HealthState) OptionsFn {
+	return func(options *Options) {
+		options.HealthState = healthState
+	}
+}
+
+// WithOnStart calls onStart before Main starts the application, e.g. to emit a starting event.
+// If it fails Main exits with ExitCodeOnStart without starting the application.
+func WithOnStart(onStart func(ctx context.Context) error) OptionsFn {
+	return func(options *Options) {
+		options.OnStart = onStart
+	}
+}
+
+// WithOnStop calls onStop after the application of Main stopped, even if it failed.
+// The context passed to onStop is not canceled by the shutdown. Its error is only logged.
+func WithOnStop(onStop func(ctx context.Context) error) OptionsFn {
+	return func(options *Options) {
+		options.OnStop = onStop
+	}
+}
+
+// WithRePanic lets a panic of the application continue after the service captured it to Sentry.
+// By default the panic is returned as PanicError.
+func WithRePanic(rePanic bool) OptionsFn {
+	return func(options *Options) {
+		options.RePanic = rePanic
+	}
+}
+
+// WithServiceName adds the name as extra service to every exception captured by the service.
+func WithServiceName(serviceName string) OptionsFn {
+	return func(options *Options) {
+		options.ServiceName = serviceName
+	}
+}
+
+// WithUnexpectedCompletionHandler calls the handler if a func of Run returns nil while its context is still alive.
+func WithUnexpectedCompletionHandler(handler UnexpectedCompletionHandler) OptionsFn {
+	return func(options *Options) {
+		options.UnexpectedCompletionHandler = handler
+	}
+}
+
+// WithLogger logs with the given Logger instead of glog.
+// Main, MainCmd and MainBasic add it to the context of the application, so Run logs with it as well.
+func WithLogger(logger Logger) OptionsFn {
+	return func(options *Options) {
+		options.Logger = logger
+	}
+}
+
+// WithReloadableExcludes drops exceptions matching the rules the provider returns at the time of the capture,
+// so operators can suppress a known error during an incident without a redeploy.
+func WithReloadableExcludes(provider ExcludeErrorsProvider) OptionsFn {
+	return func(options *Options) {
+		options.ReloadableExcludes = provider
+	}
+}
+
+// WithFlagDefaults controls whether Main, MainCmd and MainBasic set the glog flags logtostderr and v.
+// Disable it if the service is embedded in a program that configures the flags itself.
+func WithFlagDefaults(flagDefaults bool) OptionsFn {
+	return func(options *Options) {
+		options.FlagDefaults = flagDefaults
+	}
+}
+
+// WithJSONLogging writes the log messages of the framework, like the lifecycle and errors,
+// as single line JSON with the fields of WithLogFieldNames to stderr. Logs of the application are unchanged.
+func WithJSONLogging() OptionsFn {
+	return func(options *Options) {
+		options.JSONLogging = true
+	}
+}
+
+// WithShutdownPhase adds a named phase Main runs after the application returned, e.g. to persist a checkpoint.
+// Phases run one after another by ascending order, each with its own timeout within the ShutdownTimeout.
+// Failed phases are logged and captured, the following phases still run.
+func WithShutdownPhase(name string, fn func(ctx context.Context) error, order int) OptionsFn {
+	return func(options *Options) {
+		options.ShutdownPhases = append(options.ShutdownPhases, ShutdownPhase{
+			Name:  name,
+			Order: order,
+			Fn:    fn,
+		})
+	}
+}
+
+// WithSentryConnectivityCheck lets Main send a request to the host of the Sentry DSN before the application starts
+// and exit with ExitCodeSentrySetup if it is not reachable. It adds up to DefaultSentryConnectivityTimeout
+// to the startup and is skipped with WithSentryTransport.
+func WithSentryConnectivityCheck() OptionsFn {
+	return func(options *Options) {
+		options.SentryConnectivityCheck = true
+	}
+}
+
+// WithSeveritySampling captures each exception with the rate the sampler returns for it.
+// A rate of 1.0 or more always captures, a rate of 0.0 or less never.
+func WithSeveritySampling(sampler SeveritySampler) OptionsFn {
+	return func(options *Options) {
+		options.SeveritySampler = sampler
+	}
+}
+
+// WithTracing lets Main create a TracerProvider for the endpoint with the given setup before the application starts
+// and shut it down on exit before the final Sentry flush. The application finds it with TracerProviderFromContext.
+// An empty endpoint keeps tracing disabled. A failing setup exits with ExitCodeTracingSetup.
+func WithTracing(endpoint string, setup TracingSetup) OptionsFn {
+	return func(options *Options) {
+		options.TracingEndpoint = endpoint
+		options.TracingSetup = setup
+	}
+}
+
+// WithSentryContextEnv sets the env vars Main attaches to Sentry events with the redacted command line.
+// Default is DefaultSentryContextEnv. Only add env vars without secrets.
+func WithSentryContextEnv(names ...string) OptionsFn {
+	return func(options *Options) {
+		options.SentryContextEnv = names
+	}
+}
+
+// WithValidateOnly lets Main parse and print the arguments of the application and exit with ExitCodeSuccess
+// without setting up Sentry or running the application, e.g. to lint the configuration in CI.
+// Invalid arguments still exit with ExitCodeParseArguments.
+func WithValidateOnly() OptionsFn {
+	return func(options *Options) {
+		options.ValidateOnly = true
+	}
+}
+
+// WithExpectedShutdownDuration declares how long the shutdown of Main should take, from the cancel or
+// the return of the application until all closers were closed. A longer shutdown logs a warning and
+// increments service_shutdown_slo_violations_total. Unlike WithShutdownTimeout it never cuts the shutdown short.
+func WithExpectedShutdownDuration(expected time.Duration) OptionsFn {
+	return func(options *Options) {
+		options.ExpectedShutdownDuration = expected
+	}
+}
+
+// WithConfigFile lets Main read the arguments of the application from a JSON or YAML file before
+// args, env vars and env files, which still override its values. The keys of the file are the arg tags of the application.
+// A -config flag overrides the path. A missing file is skipped unless the path was set by flag.
+// Main handles the -config flag only with this option.
+func WithConfigFile(path string) OptionsFn {
+	return func(options *Options) {
+		options.ConfigFile = path
+	}
+}
+
+// WithStartupTimeout cancels the application and returns a StartupTimeoutError, captured to Sentry,
+// if it does not set the HealthState of its context ready within the timeout, see HealthStateFromContext.
+// Main creates a HealthState if none was given with WithHealthState. Zero disables the timeout.
+func WithStartupTimeout(timeout time.Duration) OptionsFn {
+	return func(options *Options) {
+		options.StartupTimeout = timeout
+	}
+}
+
+// WithClock replaces the real time of Main, e.g. with servicetest.NewFakeClock in tests.
+// Main adds it to the context of the application, see ClockFromContext.
+func WithClock(clock Clock) OptionsFn {
+	return func(options *Options) {
+		options.Clock = clock
+	}
+}
+
+// WithFuncMetrics lets Run and RunNamed in the application count errors and panics of their funcs
+// by name in the given registerer, see ContextWithFuncMetrics. Without it no metrics are registered.
+func WithFuncMetrics(registerer prometheus.Registerer) OptionsFn {
+	return func(options *Options) {
+		options.FuncMetricsRegisterer = registerer
+	}
+}
+
+// WithValidationErrorJSON lets Main write the field errors as JSON array to the given writer
+// if parsing or validating the arguments fails, e.g. for a config UI. Values are never included.
+func WithValidationErrorJSON(writer io.Writer) OptionsFn {
+	return func(options *Options) {
+		options.ValidationErrorWriter = writer
+	}
+}
+
+// WithPprof lets Main serve the pprof endpoints on the given address, separate from the port of the service.
+// The server runs next to the application and shuts down with it, see NewPprofServer.
+// If it fails, e.g. because the port is in use, the error is logged and the application keeps running.
+func WithPprof(listen string) OptionsFn {
+	return func(options *Options) {
+		options.PprofListen = listen
+	}
+}
+
+// WithPanicRecovery disabled lets panics of the application and the funcs of Run propagate
+// and crash Main with the original stack, e.g. for debugging. Keep it enabled in production.
+func WithPanicRecovery(enabled bool) OptionsFn {
+	return func(options *Options) {
+		options.PanicRecovery = enabled
+	}
+}
+
+// WithExcludeErrors excludes errors matching one of the given predicates from Sentry in addition to DefaultExcludeErrors.
+// Main passes them to the Sentry client and the service checks them before capturing an error of the application.
+func WithExcludeErrors(excludeErrors ...sentry.ExcludeError) OptionsFn {
+	return func(options *Options) {
+		options.ExcludeErrors = append(options.ExcludeErrors, excludeErrors...)
+	}
+}
+
+// WithAdditionalSentryDSN lets Main capture exceptions matching the filter also to the given DSN,
+// e.g. to route infrastructure errors to a separate project. The primary DSN still receives all of them.
+// A nil filter sends all exceptions to the additional DSN.
+func WithAdditionalSentryDSN(dsn string, filter func(err error) bool) OptionsFn {
+	return func(options *Options) {
+		options.AdditionalSentryDSNs = append(options.AdditionalSentryDSNs, AdditionalSentryDSN{
+			DSN:    dsn,
+			Filter: filter,
+		})
+	}
+}
+
+// WithRunTimeout limits the run of the application in Main, e.g. for batch jobs. Once exceeded the context
+// of the application is canceled with context.DeadlineExceeded and the cause ErrRunTimeout. If the application
+// returns nil or the deadline, Main exits with ExitCodeSuccess or the code of WithRunTimeoutExitCode.
+func WithRunTimeout(timeout time.Duration) OptionsFn {
+	return func(options *Options) {
+		options.RunTimeout = timeout
+	}
+}
+
+// WithRunTimeoutExitCode sets the exit code of Main for an application stopped by the RunTimeout.
+func WithRunTimeoutExitCode(exitCode int) OptionsFn {
+	return func(options *Options) {
+		options.RunTimeoutExitCode = exitCode
+	}
+}
+
+// WithBanner lets Main log the build information with the configuration of the application after parsing
+// the arguments, e.g. set by -ldflags. Like argument.Print it hides display:"hidden" and display:"length" fields.
+func WithBanner(version string, commit string, buildDate string) OptionsFn {
+	return func(options *Options) {
+		options.Banner = &Banner{
+			Version:   version,
+			Commit:    commit,
+			BuildDate: buildDate,
+		}
+	}
+}
+
+// WithReloadHandler lets Main call the handler on each SIGHUP, e.g. to reload log levels or feature flags.
+// Errors and panics of the handler are logged and captured to Sentry without stopping the service.
+// The handler should be idempotent and fast. Do not add SIGHUP to WithSignals at the same time.
+func WithReloadHandler(handler ReloadHandler) OptionsFn {
+	return func(options *Options) {
+		options.ReloadHandler = handler
+	}
+}
+
+// WithReadinessDelay keeps the readiness of the HealthState at 503 for the given delay after the application
+// started, even if its funcs are ready, e.g. until caches are warm. This includes the Handler of a HealthState
+// set by WithHealthState. The delay does not count into the StartupTimeout. A shutdown during the delay is not blocked.
+func WithReadinessDelay(delay time.Duration) OptionsFn {
+	return func(options *Options) {
+		options.ReadinessDelay = delay
+	}
+}
+
+// WithExitReporting lets Main capture a final message with the exit code and the reason the application
+// stopped, e.g. a signal, an error or a panic, also added as breadcrumb. Excluded errors count as clean stop.
+func WithExitReporting() OptionsFn {
+	return func(options *Options) {
+		options.ExitReporting = true
+	}
+}
+
+// WithErrorCallback lets service.Run call the callback with the raw error each time the application fails,
+// before deciding whether to capture it, e.g. to count errors or write an audit log.
+// Unlike BeforeSend it is also called for excluded errors and cannot drop the Sentry event.
+func WithErrorCallback(callback func(ctx context.Context, err error)) OptionsFn {
+	return func(options *Options) {
+		options.ErrorCallback = callback
+	}
+}
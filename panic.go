@@ -0,0 +1,134 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime/debug"
+
+	"github.com/bborbe/run"
+)
+
+// PanicError is returned for a panic recovered by CatchPanic.
+type PanicError struct {
+	// Value passed to panic.
+	Value interface{}
+	// Stack of the panicking goroutine.
+	Stack []byte
+	// FuncName of the NamedFunc that panicked, empty for unnamed funcs.
+	FuncName string
+}
+
+func (p *PanicError) Error() string {
+	if p.FuncName != "" {
+		return fmt.Sprintf("catch panic in func %s: %v", p.FuncName, p.Value)
+	}
+	return fmt.Sprintf("catch panic: %v", p.Value)
+}
+
+// Data returns the func name under FuncNameKey, so the service tags the captured event with it.
+func (p *PanicError) Data() map[string]string {
+	if p.FuncName == "" {
+		return nil
+	}
+	return map[string]string{FuncNameKey: p.FuncName}
+}
+
+// Unwrap returns the panic value if it is an error, so errors.Is and errors.As still match it.
+func (p *PanicError) Unwrap() error {
+	if err, ok := p.Value.(error); ok {
+		return err
+	}
+	return nil
+}
+
+// PanicHandler turns a recovered panic value into the error returned for it.
+// Returning nil swallows the panic.
+type PanicHandler func(recovered interface{}) error
+
+// CatchPanic recovers a panic of the given func and returns it as PanicError.
+func CatchPanic(fn run.Func) run.Func {
+	return CatchPanicWithHandler(fn, NewPanicError)
+}
+
+// NewPanicError returns a PanicError with the stack of the calling goroutine.
+// Called by a PanicHandler the stack contains the frames of the panic.
+func NewPanicError(recovered interface{}) error {
+	return &PanicError{
+		Value: recovered,
+		Stack: debug.Stack(),
+	}
+}
+
+// CatchPanicWithHandler recovers a panic of the given func and returns the error of the handler for it.
+// If the recovery is disabled in the context, the panic propagates, see ContextWithPanicRecovery.
+func CatchPanicWithHandler(fn run.Func, handler PanicHandler) run.Func {
+	return func(ctx context.Context) (err error) {
+		if !PanicRecoveryFromContext(ctx) {
+			return fn(ctx)
+		}
+		defer func() {
+			if value := recover(); value != nil {
+				err = joinPanic(err, handler(value))
+			}
+		}()
+		return fn(ctx)
+	}
+}
+
+// RecoverPanic recovers a panic of the func deferring it and joins it as PanicError to the error errp points to.
+// A func returning an error and then panicking in its own cleanup returns both, the error first.
+// Defer it before the cleanups with a named result, so it runs last:
+//
+//	func(ctx context.Context) (err error) {
+//		defer service.RecoverPanic(ctx, &err)
+//		defer cleanup()
+//		...
+//	}
+//
+// If the recovery is disabled in the context, the panic propagates, see ContextWithPanicRecovery.
+func RecoverPanic(ctx context.Context, errp *error) {
+	if !PanicRecoveryFromContext(ctx) {
+		return
+	}
+	if value := recover(); value != nil {
+		*errp = joinPanic(*errp, NewPanicError(value))
+	}
+}
+
+// joinPanic keeps err and appends the error of the panic, which might be nil if the handler swallowed it.
+func joinPanic(err error, panicErr error) error {
+	if err == nil {
+		return panicErr
+	}
+	if panicErr == nil {
+		return err
+	}
+	return errors.Join(err, panicErr)
+}
+
+type panicRecoveryContextKey struct{}
+
+// ContextWithPanicRecovery returns a context that lets CatchPanic and therefore Run and the service
+// recover panics only if enabled. Disabled panics crash with the original stack, e.g. for a debugger.
+func ContextWithPanicRecovery(ctx context.Context, enabled bool) context.Context {
+	return context.WithValue(ctx, panicRecoveryContextKey{}, enabled)
+}
+
+// PanicRecoveryFromContext reports whether panics are recovered, which is the default.
+func PanicRecoveryFromContext(ctx context.Context) bool {
+	enabled, ok := ctx.Value(panicRecoveryContextKey{}).(bool)
+	return !ok || enabled
+}
+
+// contextWithPanicRecovery disables the recovery in the context if the options do.
+func contextWithPanicRecovery(ctx context.Context, options Options) context.Context {
+	if options.PanicRecovery {
+		return ctx
+	}
+	return ContextWithPanicRecovery(ctx, false)
+}
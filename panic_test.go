@@ -0,0 +1,88 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package service_test
+
+import (
+	"context"
+	stderrors "errors"
+
+	libsentry "github.com/bborbe/sentry"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/bborbe/service"
+	"github.com/bborbe/service/mocks"
+)
+
+var _ = Describe("ContextWithPanicRecovery", func() {
+	var ctx context.Context
+	BeforeEach(func() {
+		ctx = context.Background()
+	})
+	It("recovers by default", func() {
+		Expect(service.PanicRecoveryFromContext(ctx)).To(BeTrue())
+		err := service.CatchPanic(func(ctx context.Context) error {
+			panic("banana")
+		})(ctx)
+		var panicErr *service.PanicError
+		Expect(stderrors.As(err, &panicErr)).To(BeTrue())
+	})
+	It("lets the panic of CatchPanic propagate if disabled", func() {
+		ctx = service.ContextWithPanicRecovery(ctx, false)
+		Expect(service.PanicRecoveryFromContext(ctx)).To(BeFalse())
+		var recovered interface{}
+		func() {
+			defer func() {
+				recovered = recover()
+			}()
+			_ = service.CatchPanic(func(ctx context.Context) error {
+				panic("banana")
+			})(ctx)
+		}()
+		Expect(recovered).To(Equal("banana"))
+	})
+	It("lets the panic of the application propagate if disabled", func() {
+		sentryClient := &mocks.SentryClient{}
+		app := &mocks.ServiceApplication{}
+		app.RunStub = func(ctx context.Context, sentryClient libsentry.Client) error {
+			panic("banana")
+		}
+		Expect(func() {
+			_ = service.NewService(sentryClient, app).Run(service.ContextWithPanicRecovery(ctx, false))
+		}).To(PanicWith("banana"))
+		Expect(sentryClient.CaptureExceptionCallCount()).To(Equal(0))
+	})
+	It("passes the error of the func if disabled", func() {
+		err := service.CatchPanic(func(ctx context.Context) error {
+			return stderrors.New("banana")
+		})(service.ContextWithPanicRecovery(ctx, false))
+		Expect(err).To(MatchError("banana"))
+	})
+	It("lets the panic of RecoverPanic propagate if disabled", func() {
+		ctx = service.ContextWithPanicRecovery(ctx, false)
+		Expect(func() {
+			_ = func() (err error) {
+				defer service.RecoverPanic(ctx, &err)
+				panic("banana")
+			}()
+		}).To(PanicWith("banana"))
+	})
+})
+
+var _ = Describe("WithPanicRecovery", func() {
+	It("is enabled by default", func() {
+		Expect(service.NewOptions().PanicRecovery).To(BeTrue())
+	})
+	It("disables the recovery", func() {
+		Expect(service.NewOptions(service.WithPanicRecovery(false)).PanicRecovery).To(BeFalse())
+	})
+	It("lets MainBasic crash with the panic", func() {
+		Expect(func() {
+			service.MainBasic(context.Background(), func(ctx context.Context) error {
+				panic("banana")
+			}, service.WithoutTimezoneOverride(), service.WithPanicRecovery(false))
+		}).To(PanicWith("banana"))
+	})
+})
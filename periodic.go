@@ -0,0 +1,32 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/bborbe/errors"
+	"github.com/bborbe/run"
+)
+
+// Periodic runs fn immediately and then every interval until ctx is canceled, which returns nil.
+// The first error of fn is returned. The ticks come from the Clock of the context.
+func Periodic(interval time.Duration, fn run.Func) run.Func {
+	return func(ctx context.Context) error {
+		ticker := ClockFromContext(ctx).NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			if err := fn(ctx); err != nil {
+				return errors.Wrapf(ctx, err, "periodic func failed")
+			}
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-ticker.C():
+			}
+		}
+	}
+}
@@ -0,0 +1,88 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package service_test
+
+import (
+	"context"
+	stderrors "errors"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/bborbe/service"
+	"github.com/bborbe/service/servicetest"
+)
+
+var _ = Describe("Periodic", func() {
+	var ctx context.Context
+	var cancel context.CancelFunc
+	var clock servicetest.FakeClock
+	var calls chan struct{}
+	var fnErr error
+	var done chan error
+	BeforeEach(func() {
+		clock = servicetest.NewFakeClock(time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC))
+		ctx, cancel = context.WithCancel(service.ContextWithClock(context.Background(), clock))
+		calls = make(chan struct{}, 10)
+		fnErr = nil
+		done = make(chan error, 1)
+	})
+	AfterEach(func() {
+		cancel()
+	})
+	start := func() {
+		go func() {
+			done <- service.Periodic(time.Minute, func(ctx context.Context) error {
+				calls <- struct{}{}
+				return fnErr
+			})(ctx)
+		}()
+	}
+	It("runs immediately and on every tick", func() {
+		start()
+		Eventually(calls).Should(Receive())
+		clock.BlockUntil(1)
+		Consistently(calls).ShouldNot(Receive())
+		clock.Add(time.Minute)
+		Eventually(calls).Should(Receive())
+		cancel()
+		Eventually(done).Should(Receive(BeNil()))
+	})
+	It("returns the error of the func", func() {
+		fnErr = stderrors.New("banana")
+		start()
+		Eventually(done).Should(Receive(MatchError(ContainSubstring("banana"))))
+	})
+})
+
+var _ = Describe("ClockFromContext", func() {
+	It("defaults to the real clock", func() {
+		Expect(service.ClockFromContext(context.Background()).Now()).To(BeTemporally("~", time.Now(), time.Second))
+	})
+	It("returns the clock of the context", func() {
+		clock := servicetest.NewFakeClock(time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC))
+		Expect(service.ClockFromContext(service.ContextWithClock(context.Background(), clock))).To(Equal(clock))
+	})
+	It("drives RetryWithBackoff", func() {
+		clock := servicetest.NewFakeClock(time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC))
+		ctx := service.ContextWithClock(context.Background(), clock)
+		attempts := make(chan struct{}, 10)
+		done := make(chan error, 1)
+		go func() {
+			done <- service.RetryWithBackoff(func(ctx context.Context) error {
+				attempts <- struct{}{}
+				if len(attempts) < 2 {
+					return stderrors.New("banana")
+				}
+				return nil
+			}, time.Hour, time.Hour, 2)(ctx)
+		}()
+		clock.BlockUntil(1)
+		clock.Add(time.Hour)
+		Eventually(done).Should(Receive(BeNil()))
+		Expect(attempts).To(HaveLen(2))
+	})
+})
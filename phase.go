@@ -0,0 +1,58 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package service
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Phase of the service lifecycle.
+// The value is exported as gauge service_phase.
+type Phase int
+
+const (
+	// PhaseStarting while arguments are parsed and Sentry is set up.
+	PhaseStarting Phase = 0
+	// PhaseRunning while the application runs.
+	PhaseRunning Phase = 1
+	// PhaseDraining after the shutdown began until the application returned.
+	PhaseDraining Phase = 2
+	// PhaseStopped after the application returned.
+	PhaseStopped Phase = 3
+)
+
+func (p Phase) String() string {
+	switch p {
+	case PhaseStarting:
+		return "starting"
+	case PhaseRunning:
+		return "running"
+	case PhaseDraining:
+		return "draining"
+	case PhaseStopped:
+		return "stopped"
+	default:
+		return "unknown"
+	}
+}
+
+func newPhaseGauge(registerer prometheus.Registerer) *phaseGauge {
+	return &phaseGauge{
+		gauge: register(registerer, prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: metricsNamespace,
+			Name:      "phase",
+			Help:      "Current lifecycle phase: starting=0, running=1, draining=2, stopped=3.",
+		})),
+	}
+}
+
+type phaseGauge struct {
+	gauge prometheus.Gauge
+}
+
+// Set the current phase.
+func (p *phaseGauge) Set(phase Phase) {
+	p.gauge.Set(float64(phase))
+}
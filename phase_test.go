@@ -0,0 +1,39 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package service_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/bborbe/service"
+)
+
+var _ = Describe("Phase", func() {
+	DescribeTable("String",
+		func(phase service.Phase, expected string) {
+			Expect(phase.String()).To(Equal(expected))
+		},
+		Entry("starting", service.PhaseStarting, "starting"),
+		Entry("running", service.PhaseRunning, "running"),
+		Entry("draining", service.PhaseDraining, "draining"),
+		Entry("stopped", service.PhaseStopped, "stopped"),
+		Entry("unknown", service.Phase(42), "unknown"),
+	)
+	It("exports the phase as gauge", func() {
+		registry := prometheus.NewRegistry()
+		gauge := service.NewPhaseGauge(registry)
+		gauge.Set(service.PhaseDraining)
+		Expect(metricValue(registry, "service_phase")).To(Equal(2.0))
+		gauge.Set(service.PhaseStopped)
+		Expect(metricValue(registry, "service_phase")).To(Equal(3.0))
+	})
+	It("works without registerer", func() {
+		Expect(func() {
+			service.NewPhaseGauge(nil).Set(service.PhaseRunning)
+		}).NotTo(Panic())
+	})
+})
@@ -0,0 +1,141 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package service
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"net/http"
+	"os"
+	"runtime/pprof"
+	"runtime/trace"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bborbe/run"
+)
+
+// PprofPath is the path prefix of the endpoints of NewPprofServer.
+const PprofPath = "/debug/pprof/"
+
+// DefaultPprofSeconds is the duration of a CPU profile or trace without seconds parameter.
+const DefaultPprofSeconds = 30
+
+// NewPprofServer returns a func serving the pprof endpoints on listen until ctx is canceled.
+// The endpoints are only served by this server. The package does not import net/http/pprof,
+// which registers them on http.DefaultServeMux and exposes them on the port of the service.
+//
+//	go tool pprof http://localhost:6060/debug/pprof/heap
+func NewPprofServer(listen string) run.Func {
+	return NewHTTPServer(listen, PprofHandler())
+}
+
+// PprofHandler returns a handler serving the index, cmdline, profile, trace and the named runtime profiles below PprofPath.
+func PprofHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc(PprofPath, pprofIndex)
+	mux.HandleFunc(PprofPath+"cmdline", pprofCmdline)
+	mux.HandleFunc(PprofPath+"profile", pprofProfile)
+	mux.HandleFunc(PprofPath+"trace", pprofTrace)
+	return mux
+}
+
+func pprofIndex(resp http.ResponseWriter, req *http.Request) {
+	if name := strings.TrimPrefix(req.URL.Path, PprofPath); name != "" {
+		pprofNamed(resp, req, name)
+		return
+	}
+	resp.Header().Set("Content-Type", "text/html; charset=utf-8")
+	fmt.Fprintf(resp, "<html><body><ul>\n")
+	for _, profile := range pprof.Profiles() {
+		name := html.EscapeString(profile.Name())
+		fmt.Fprintf(resp, "<li><a href=\"%s?debug=1\">%s</a> (%d)</li>\n", name, name, profile.Count())
+	}
+	fmt.Fprintf(resp, "<li><a href=\"profile\">profile</a></li>\n<li><a href=\"trace\">trace</a></li>\n")
+	fmt.Fprintf(resp, "</ul></body></html>\n")
+}
+
+func pprofNamed(resp http.ResponseWriter, req *http.Request, name string) {
+	profile := pprof.Lookup(name)
+	if profile == nil {
+		http.Error(resp, "unknown profile", http.StatusNotFound)
+		return
+	}
+	debug, _ := strconv.Atoi(req.FormValue("debug"))
+	if debug != 0 {
+		resp.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	} else {
+		pprofAttachment(resp, name)
+	}
+	_ = profile.WriteTo(resp, debug)
+}
+
+func pprofCmdline(resp http.ResponseWriter, req *http.Request) {
+	resp.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	fmt.Fprint(resp, strings.Join(os.Args, "\x00"))
+}
+
+func pprofProfile(resp http.ResponseWriter, req *http.Request) {
+	pprofAttachment(resp, "profile")
+	if err := pprof.StartCPUProfile(resp); err != nil {
+		resp.Header().Del("Content-Disposition")
+		http.Error(resp, fmt.Sprintf("start cpu profile failed: %v", err), http.StatusInternalServerError)
+		return
+	}
+	defer pprof.StopCPUProfile()
+	pprofSleep(req)
+}
+
+func pprofTrace(resp http.ResponseWriter, req *http.Request) {
+	pprofAttachment(resp, "trace")
+	if err := trace.Start(resp); err != nil {
+		resp.Header().Del("Content-Disposition")
+		http.Error(resp, fmt.Sprintf("start trace failed: %v", err), http.StatusInternalServerError)
+		return
+	}
+	defer trace.Stop()
+	pprofSleep(req)
+}
+
+func pprofAttachment(resp http.ResponseWriter, name string) {
+	resp.Header().Set("Content-Type", "application/octet-stream")
+	resp.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", name))
+}
+
+// pprofSleep waits the seconds of the request or until the client disconnects.
+func pprofSleep(req *http.Request) {
+	seconds, err := strconv.ParseFloat(req.FormValue("seconds"), 64)
+	if err != nil || seconds <= 0 {
+		seconds = DefaultPprofSeconds
+	}
+	timer := time.NewTimer(time.Duration(seconds * float64(time.Second)))
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+	case <-req.Context().Done():
+	}
+}
+
+// runWithPprof runs fn and alongside the pprof server, which is stopped once fn returns.
+// The pprof server is a debug aid: its errors, e.g. a port in use, are logged and never cancel or fail fn.
+func runWithPprof(fn run.Func, listen string) run.Func {
+	return func(ctx context.Context) error {
+		pprofCtx, cancel := context.WithCancel(ctx)
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			if err := NewPprofServer(listen)(pprofCtx); err != nil && !isCanceledBy(pprofCtx, err) {
+				LoggerFromContext(ctx).Warningf("pprof server on %s failed: %v", listen, err)
+			}
+		}()
+		defer func() {
+			cancel()
+			<-done
+		}()
+		return fn(ctx)
+	}
+}
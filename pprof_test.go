@@ -0,0 +1,122 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package service_test
+
+import (
+	"context"
+	stderrors "errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/bborbe/service"
+)
+
+var _ = Describe("PprofHandler", func() {
+	get := func(path string) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		service.PprofHandler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, path, nil))
+		return recorder
+	}
+	It("lists the profiles", func() {
+		recorder := get("/debug/pprof/")
+		Expect(recorder.Code).To(Equal(http.StatusOK))
+		Expect(recorder.Body.String()).To(ContainSubstring("goroutine"))
+		Expect(recorder.Body.String()).To(ContainSubstring("heap"))
+	})
+	It("serves a named profile as text", func() {
+		recorder := get("/debug/pprof/goroutine?debug=1")
+		Expect(recorder.Code).To(Equal(http.StatusOK))
+		Expect(recorder.Body.String()).To(ContainSubstring("goroutine profile"))
+	})
+	It("serves a named profile as attachment", func() {
+		recorder := get("/debug/pprof/heap")
+		Expect(recorder.Code).To(Equal(http.StatusOK))
+		Expect(recorder.Header().Get("Content-Disposition")).To(Equal(`attachment; filename="heap"`))
+		Expect(recorder.Body.Len()).To(BeNumerically(">", 0))
+	})
+	It("responds 404 for an unknown profile", func() {
+		Expect(get("/debug/pprof/banana").Code).To(Equal(http.StatusNotFound))
+	})
+	It("serves nothing outside the pprof path", func() {
+		Expect(get("/").Code).To(Equal(http.StatusNotFound))
+	})
+	It("does not register on the default serve mux", func() {
+		_, pattern := http.DefaultServeMux.Handler(httptest.NewRequest(http.MethodGet, "/debug/pprof/", nil))
+		Expect(pattern).To(BeEmpty())
+	})
+})
+
+var _ = Describe("RunWithPprof", func() {
+	var addr string
+	BeforeEach(func() {
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		Expect(err).To(BeNil())
+		addr = listener.Addr().String()
+		Expect(listener.Close()).To(BeNil())
+	})
+	It("serves pprof while the func runs and stops with it", func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		stop := make(chan struct{})
+		errCh := make(chan error, 1)
+		go func() {
+			errCh <- service.RunWithPprof(func(ctx context.Context) error {
+				select {
+				case <-stop:
+					return nil
+				case <-ctx.Done():
+					return ctx.Err()
+				}
+			}, addr)(ctx)
+		}()
+		Eventually(func() int {
+			resp, err := http.Get("http://" + addr + "/debug/pprof/cmdline")
+			if err != nil {
+				return 0
+			}
+			defer resp.Body.Close()
+			_, _ = io.Copy(io.Discard, resp.Body)
+			return resp.StatusCode
+		}).Should(Equal(http.StatusOK))
+		close(stop)
+		Eventually(errCh).Should(Receive(BeNil()))
+		_, err := http.Get("http://" + addr + "/debug/pprof/cmdline")
+		Expect(err).NotTo(BeNil())
+	})
+	It("returns the error of the func", func() {
+		banana := stderrors.New("banana")
+		err := service.RunWithPprof(func(ctx context.Context) error {
+			return banana
+		}, addr)(context.Background())
+		Expect(stderrors.Is(err, banana)).To(BeTrue())
+	})
+	It("keeps the func running if the pprof port is in use", func() {
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		Expect(err).To(BeNil())
+		defer listener.Close()
+		logger := &recordingLogger{}
+		ctx, cancel := context.WithTimeout(service.ContextWithLogger(context.Background(), logger), 5*time.Second)
+		defer cancel()
+		var completed bool
+		err = service.RunWithPprof(func(ctx context.Context) error {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(200 * time.Millisecond):
+				completed = true
+				return nil
+			}
+		}, listener.Addr().String())(ctx)
+		Expect(err).To(BeNil())
+		Expect(completed).To(BeTrue())
+		Expect(logger.Lines()).To(ContainElement(ContainSubstring("pprof server on " + listener.Addr().String() + " failed")))
+	})
+})
@@ -0,0 +1,128 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package service
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/bborbe/errors"
+)
+
+// HealthCheck returns an error if the checked dependency is not healthy.
+type HealthCheck func(ctx context.Context) error
+
+type ReadinessCriteriaOptions struct {
+	// GracePeriod after start the service is not ready yet.
+	GracePeriod time.Duration
+	// HealthChecks that all must pass.
+	HealthChecks []HealthCheck
+	// Predicate is an additional custom criterion. Nil is always true.
+	Predicate func(ctx context.Context) bool
+	// Clock the grace period is measured with. Nil uses the Clock of Main set by WithClock.
+	Clock Clock
+}
+
+// ReadinessCriteria combines all readiness signals of the service.
+// The service is ready if all criteria are true. They are evaluated in the following order
+// and the evaluation stops at the first failing criterion:
+//  1. the service has started
+//  2. the service is not draining
+//  3. the grace period after start has elapsed
+//  4. all health checks pass, in the given order
+//  5. the custom predicate returns true
+type ReadinessCriteria interface {
+	http.Handler
+	// Ready returns nil if all criteria are fulfilled, otherwise the reason why not.
+	Ready(ctx context.Context) error
+	// Started marks the service as started at the given time.
+	Started(now time.Time)
+	// Draining marks the service as shutting down.
+	Draining()
+}
+
+func NewReadinessCriteria(options ReadinessCriteriaOptions) ReadinessCriteria {
+	clock := options.Clock
+	if clock == nil {
+		clock = NewClock()
+	}
+	return &readinessCriteria{
+		options: options,
+		clock:   clock,
+	}
+}
+
+// clockSetter is implemented by ReadinessCriteria without own Clock, Main sets its Clock.
+type clockSetter interface {
+	setClock(clock Clock)
+}
+
+type readinessCriteria struct {
+	options ReadinessCriteriaOptions
+
+	mux      sync.Mutex
+	clock    Clock
+	started  time.Time
+	draining bool
+}
+
+func (r *readinessCriteria) setClock(clock Clock) {
+	if r.options.Clock != nil {
+		return
+	}
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	r.clock = clock
+}
+
+func (r *readinessCriteria) Started(now time.Time) {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	r.started = now
+}
+
+func (r *readinessCriteria) Draining() {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	r.draining = true
+}
+
+func (r *readinessCriteria) Ready(ctx context.Context) error {
+	r.mux.Lock()
+	started := r.started
+	draining := r.draining
+	clock := r.clock
+	r.mux.Unlock()
+
+	if started.IsZero() {
+		return errors.New(ctx, "not started")
+	}
+	if draining {
+		return errors.New(ctx, "draining")
+	}
+	if elapsed := clock.Now().Sub(started); elapsed < r.options.GracePeriod {
+		return errors.Errorf(ctx, "grace period not elapsed, %v remaining", r.options.GracePeriod-elapsed)
+	}
+	for i, healthCheck := range r.options.HealthChecks {
+		if err := healthCheck(ctx); err != nil {
+			return errors.Wrapf(ctx, err, "health check %d failed", i)
+		}
+	}
+	if r.options.Predicate != nil && !r.options.Predicate(ctx) {
+		return errors.New(ctx, "predicate is false")
+	}
+	return nil
+}
+
+func (r *readinessCriteria) ServeHTTP(resp http.ResponseWriter, req *http.Request) {
+	if err := r.Ready(req.Context()); err != nil {
+		http.Error(resp, err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+	resp.WriteHeader(http.StatusOK)
+	_, _ = resp.Write([]byte("OK"))
+}
@@ -0,0 +1,152 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package service_test
+
+import (
+	"context"
+	stderrors "errors"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/bborbe/service"
+	"github.com/bborbe/service/servicetest"
+)
+
+var _ = Describe("ReadinessCriteria", func() {
+	var ctx context.Context
+	var options service.ReadinessCriteriaOptions
+	var criteria service.ReadinessCriteria
+	var healthCheckCalled bool
+	var predicateCalled bool
+	BeforeEach(func() {
+		ctx = context.Background()
+		healthCheckCalled = false
+		predicateCalled = false
+		options = service.ReadinessCriteriaOptions{
+			GracePeriod: time.Minute,
+			HealthChecks: []service.HealthCheck{
+				func(ctx context.Context) error {
+					healthCheckCalled = true
+					return nil
+				},
+			},
+			Predicate: func(ctx context.Context) bool {
+				predicateCalled = true
+				return true
+			},
+		}
+	})
+	JustBeforeEach(func() {
+		criteria = service.NewReadinessCriteria(options)
+		criteria.Started(time.Now().Add(-time.Hour))
+	})
+	It("is ready if all criteria are fulfilled", func() {
+		Expect(criteria.Ready(ctx)).To(BeNil())
+		Expect(healthCheckCalled).To(BeTrue())
+		Expect(predicateCalled).To(BeTrue())
+	})
+	It("is not ready before start", func() {
+		Expect(service.NewReadinessCriteria(options).Ready(ctx)).NotTo(BeNil())
+	})
+	It("is not ready while draining", func() {
+		criteria.Draining()
+		err := criteria.Ready(ctx)
+		Expect(err).NotTo(BeNil())
+		Expect(err.Error()).To(ContainSubstring("draining"))
+		Expect(healthCheckCalled).To(BeFalse())
+	})
+	It("is not ready during the grace period", func() {
+		criteria.Started(time.Now())
+		err := criteria.Ready(ctx)
+		Expect(err).NotTo(BeNil())
+		Expect(err.Error()).To(ContainSubstring("grace period"))
+		Expect(healthCheckCalled).To(BeFalse())
+	})
+	Context("failing health check", func() {
+		BeforeEach(func() {
+			options.HealthChecks = append(options.HealthChecks, func(ctx context.Context) error {
+				return stderrors.New("database down")
+			})
+		})
+		It("is not ready", func() {
+			err := criteria.Ready(ctx)
+			Expect(err).NotTo(BeNil())
+			Expect(err.Error()).To(ContainSubstring("database down"))
+			Expect(predicateCalled).To(BeFalse())
+		})
+	})
+	Context("false predicate", func() {
+		BeforeEach(func() {
+			options.Predicate = func(ctx context.Context) bool {
+				return false
+			}
+		})
+		It("is not ready", func() {
+			err := criteria.Ready(ctx)
+			Expect(err).NotTo(BeNil())
+			Expect(err.Error()).To(ContainSubstring("predicate"))
+		})
+	})
+	Context("ServeHTTP", func() {
+		var recorder *httptest.ResponseRecorder
+		BeforeEach(func() {
+			recorder = httptest.NewRecorder()
+		})
+		It("returns 200 if ready", func() {
+			criteria.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+			Expect(recorder.Code).To(Equal(http.StatusOK))
+		})
+		It("returns 503 if not ready", func() {
+			criteria.Draining()
+			criteria.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+			Expect(recorder.Code).To(Equal(http.StatusServiceUnavailable))
+		})
+	})
+})
+
+var _ = Describe("ReadinessCriteria with Clock", func() {
+	var clock servicetest.FakeClock
+	var options service.ReadinessCriteriaOptions
+	BeforeEach(func() {
+		clock = servicetest.NewFakeClock(time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC))
+		options = service.ReadinessCriteriaOptions{
+			GracePeriod: time.Minute,
+		}
+	})
+	It("measures the grace period with the clock of the options", func() {
+		options.Clock = clock
+		criteria := service.NewReadinessCriteria(options)
+		criteria.Started(clock.Now())
+		Expect(criteria.Ready(context.Background())).NotTo(BeNil())
+		clock.Add(time.Minute)
+		Expect(criteria.Ready(context.Background())).To(BeNil())
+	})
+	It("measures the grace period with the clock of Main", func() {
+		criteria := service.NewReadinessCriteria(options)
+		var readyOnStart error
+		var readyAfterGracePeriod error
+		app := &mainApplication{
+			RunFunc: func(ctx context.Context) error {
+				readyOnStart = criteria.Ready(context.Background())
+				clock.Add(time.Minute)
+				readyAfterGracePeriod = criteria.Ready(context.Background())
+				return nil
+			},
+		}
+		Expect(service.Main(context.Background(), app, &app.SentryDSN, nil,
+			service.WithoutTimezoneOverride(),
+			service.WithRegisterer(nil),
+			service.WithSentryTransport(servicetest.NewSentryTransport()),
+			service.WithClock(clock),
+			service.WithReadinessCriteria(criteria),
+		)).To(Equal(service.ExitCodeSuccess))
+		Expect(readyOnStart).To(MatchError(ContainSubstring("grace period")))
+		Expect(readyAfterGracePeriod).To(BeNil())
+	})
+})
@@ -0,0 +1,58 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package service
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// newDelayedReadiness returns a HealthState that holds the given state not ready until the delay elapsed after Start.
+// A readiness set in the meantime is applied to the given state once the delay elapsed, so its own Handler
+// reports the delay as well.
+func newDelayedReadiness(healthState HealthState, delay time.Duration) *delayedReadiness {
+	wanted := healthState.Ready()
+	healthState.SetReady(false)
+	return &delayedReadiness{
+		HealthState: healthState,
+		delay:       delay,
+		wanted:      wanted,
+	}
+}
+
+type delayedReadiness struct {
+	HealthState
+	delay time.Duration
+
+	mux     sync.Mutex
+	wanted  bool
+	elapsed bool
+}
+
+// Start begins the delay, a canceled ctx stops it and the state stays not ready.
+func (d *delayedReadiness) Start(ctx context.Context) {
+	timer := ClockFromContext(ctx).NewTimer(d.delay)
+	go func() {
+		defer timer.Stop()
+		select {
+		case <-ctx.Done():
+		case <-timer.C():
+			d.mux.Lock()
+			defer d.mux.Unlock()
+			d.elapsed = true
+			d.HealthState.SetReady(d.wanted)
+		}
+	}()
+}
+
+func (d *delayedReadiness) SetReady(ready bool) {
+	d.mux.Lock()
+	defer d.mux.Unlock()
+	d.wanted = ready
+	if !ready || d.elapsed {
+		d.HealthState.SetReady(ready)
+	}
+}
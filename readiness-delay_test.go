@@ -0,0 +1,132 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package service_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/bborbe/service"
+	"github.com/bborbe/service/servicetest"
+)
+
+var _ = Describe("DelayedReadiness", func() {
+	var ctx context.Context
+	var cancel context.CancelFunc
+	var clock servicetest.FakeClock
+	var healthState service.HealthState
+	var delayed service.HealthState
+	BeforeEach(func() {
+		clock = servicetest.NewFakeClock(time.Unix(0, 0))
+		ctx, cancel = context.WithCancel(service.ContextWithClock(context.Background(), clock))
+		healthState = service.NewHealthState()
+		healthState.SetLive(true)
+		healthState.SetReady(true)
+		readiness := service.NewDelayedReadiness(healthState, time.Minute)
+		readiness.Start(ctx)
+		delayed = readiness
+	})
+	AfterEach(func() {
+		cancel()
+	})
+	status := func() int {
+		recorder := httptest.NewRecorder()
+		delayed.Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/readiness", nil))
+		return recorder.Code
+	}
+	It("is not ready during the delay", func() {
+		clock.BlockUntil(1)
+		clock.Add(59 * time.Second)
+		Expect(delayed.Ready()).To(BeFalse())
+		Expect(status()).To(Equal(http.StatusServiceUnavailable))
+		Expect(delayed.Live()).To(BeTrue())
+	})
+	It("follows the health state after the delay", func() {
+		clock.BlockUntil(1)
+		clock.Add(time.Minute)
+		Eventually(delayed.Ready).Should(BeTrue())
+		Expect(status()).To(Equal(http.StatusOK))
+		delayed.SetReady(false)
+		Expect(delayed.Ready()).To(BeFalse())
+		Expect(healthState.Ready()).To(BeFalse())
+	})
+	It("holds the given health state not ready during the delay", func() {
+		clock.BlockUntil(1)
+		delayed.SetReady(true)
+		Expect(healthState.Ready()).To(BeFalse())
+		recorder := httptest.NewRecorder()
+		healthState.Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/readiness", nil))
+		Expect(recorder.Code).To(Equal(http.StatusServiceUnavailable))
+		clock.Add(time.Minute)
+		Eventually(healthState.Ready).Should(BeTrue())
+	})
+	It("stays not ready after the delay if set not ready during it", func() {
+		clock.BlockUntil(1)
+		delayed.SetReady(false)
+		clock.Add(time.Minute)
+		Consistently(healthState.Ready, 50*time.Millisecond).Should(BeFalse())
+	})
+	It("stays not ready if canceled during the delay", func() {
+		clock.BlockUntil(1)
+		cancel()
+		Consistently(delayed.Ready, 50*time.Millisecond).Should(BeFalse())
+	})
+})
+
+var _ = Describe("WithReadinessDelay", func() {
+	It("keeps Main not ready during the delay and exits cleanly", func() {
+		healthState := service.NewHealthState()
+		app := &mainApplication{
+			RunFunc: func(ctx context.Context) error {
+				return service.Run(ctx, func(ctx context.Context) error {
+					service.HealthStateFromContext(ctx).SetReady(true)
+					Expect(service.HealthStateFromContext(ctx).Ready()).To(BeFalse())
+					Expect(healthState.Ready()).To(BeFalse())
+					recorder := httptest.NewRecorder()
+					healthState.Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/readiness", nil))
+					Expect(recorder.Code).To(Equal(http.StatusServiceUnavailable))
+					return nil
+				})
+			},
+		}
+		start := time.Now()
+		Expect(service.Main(context.Background(), app, &app.SentryDSN, nil,
+			service.WithoutTimezoneOverride(),
+			service.WithRegisterer(nil),
+			service.WithSentryTransport(servicetest.NewSentryTransport()),
+			service.WithHealthState(healthState),
+			service.WithReadinessDelay(time.Hour),
+		)).To(Equal(service.ExitCodeSuccess))
+		Expect(healthState.Ready()).To(BeFalse())
+		Expect(time.Since(start)).To(BeNumerically("<", time.Minute))
+	})
+	It("does not count the delay into the startup timeout", func() {
+		app := &mainApplication{
+			RunFunc: func(ctx context.Context) error {
+				return service.Run(ctx, func(ctx context.Context) error {
+					service.HealthStateFromContext(ctx).SetReady(true)
+					select {
+					case <-ctx.Done():
+						return ctx.Err()
+					case <-time.After(200 * time.Millisecond):
+						return nil
+					}
+				})
+			},
+		}
+		Expect(service.Main(context.Background(), app, &app.SentryDSN, nil,
+			service.WithoutTimezoneOverride(),
+			service.WithRegisterer(nil),
+			service.WithSentryTransport(servicetest.NewSentryTransport()),
+			service.WithStartupTimeout(50*time.Millisecond),
+			service.WithReadinessDelay(time.Hour),
+		)).To(Equal(service.ExitCodeSuccess))
+	})
+})
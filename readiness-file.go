@@ -0,0 +1,57 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package service
+
+import (
+	"context"
+	"os"
+
+	"github.com/bborbe/errors"
+	"github.com/bborbe/run"
+)
+
+// createReadinessFile signals readiness to file based probes.
+func createReadinessFile(ctx context.Context, path string) error {
+	if err := os.WriteFile(path, []byte("ready\n"), 0600); err != nil {
+		return errors.Wrapf(ctx, err, "create readiness file %s failed", path)
+	}
+	if LoggerFromContext(ctx).V(2) {
+		LoggerFromContext(ctx).Infof("readiness file %s created", path)
+	}
+	return nil
+}
+
+// removeReadinessFile signals not ready to file based probes, a missing file is ignored.
+func removeReadinessFile(ctx context.Context, path string) error {
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return errors.Wrapf(ctx, err, "remove readiness file %s failed", path)
+	}
+	return nil
+}
+
+// runWithReadinessFile removes a stale readiness file before fn starts,
+// as soon as ctx is canceled and on every exit of fn including panics.
+func runWithReadinessFile(fn run.Func, path string) run.Func {
+	return func(ctx context.Context) error {
+		remove := func() {
+			if err := removeReadinessFile(context.WithoutCancel(ctx), path); err != nil {
+				LoggerFromContext(ctx).Warningf("%v", err)
+			}
+		}
+		remove()
+		stop := context.AfterFunc(ctx, remove)
+		defer stop()
+		defer remove()
+		return fn(ctx)
+	}
+}
+
+// runWithStarted calls started before fn starts.
+func runWithStarted(fn run.Func, started func()) run.Func {
+	return func(ctx context.Context) error {
+		started()
+		return fn(ctx)
+	}
+}
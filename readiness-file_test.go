@@ -0,0 +1,104 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package service_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/bborbe/service"
+	"github.com/bborbe/service/servicetest"
+)
+
+var _ = Describe("ReadinessFile", func() {
+	var ctx context.Context
+	var cancel context.CancelFunc
+	var path string
+	BeforeEach(func() {
+		ctx, cancel = context.WithTimeout(context.Background(), time.Second)
+		path = filepath.Join(GinkgoT().TempDir(), "ready")
+	})
+	AfterEach(func() {
+		cancel()
+	})
+	It("creates the file on ready and removes it on shutdown", func() {
+		existsOnReady := make(chan bool, 1)
+		errCh := make(chan error, 1)
+		go func() {
+			errCh <- service.RunWithReadinessFile(func(ctx context.Context) error {
+				Expect(service.CreateReadinessFile(ctx, path)).To(Succeed())
+				_, err := os.Stat(path)
+				existsOnReady <- err == nil
+				<-ctx.Done()
+				return nil
+			}, path)(ctx)
+		}()
+		Eventually(existsOnReady).Should(Receive(BeTrue()))
+		cancel()
+		Eventually(errCh).Should(Receive(BeNil()))
+		Expect(path).NotTo(BeAnExistingFile())
+	})
+	It("removes a stale file before start", func() {
+		Expect(os.WriteFile(path, nil, 0600)).To(Succeed())
+		err := service.RunWithReadinessFile(func(ctx context.Context) error {
+			Expect(path).NotTo(BeAnExistingFile())
+			return nil
+		}, path)(ctx)
+		Expect(err).To(BeNil())
+	})
+	It("removes the file on panic", func() {
+		Expect(func() {
+			_ = service.RunWithReadinessFile(func(ctx context.Context) error {
+				Expect(service.CreateReadinessFile(ctx, path)).To(Succeed())
+				panic("banana")
+			}, path)(ctx)
+		}).To(PanicWith("banana"))
+		Expect(path).NotTo(BeAnExistingFile())
+	})
+})
+
+var _ = Describe("Main WithReadinessFile", func() {
+	var path string
+	var fns []service.OptionsFn
+	BeforeEach(func() {
+		path = filepath.Join(GinkgoT().TempDir(), "ready")
+		fns = []service.OptionsFn{
+			service.WithoutTimezoneOverride(),
+			service.WithRegisterer(nil),
+			service.WithSentryTransport(servicetest.NewSentryTransport()),
+			service.WithReadinessFile(path),
+		}
+	})
+	It("keeps the file while the application runs", func() {
+		var exists bool
+		app := &mainApplication{
+			RunFunc: func(ctx context.Context) error {
+				_, err := os.Stat(path)
+				exists = err == nil
+				return nil
+			},
+		}
+		Expect(service.Main(context.Background(), app, &app.SentryDSN, nil, fns...)).To(Equal(service.ExitCodeSuccess))
+		Expect(exists).To(BeTrue())
+		Expect(path).NotTo(BeAnExistingFile())
+	})
+	It("replaces a stale file", func() {
+		Expect(os.WriteFile(path, []byte("stale\n"), 0600)).To(Succeed())
+		var content []byte
+		app := &mainApplication{
+			RunFunc: func(ctx context.Context) error {
+				content, _ = os.ReadFile(path)
+				return nil
+			},
+		}
+		Expect(service.Main(context.Background(), app, &app.SentryDSN, nil, fns...)).To(Equal(service.ExitCodeSuccess))
+		Expect(string(content)).To(Equal("ready\n"))
+	})
+})
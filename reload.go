@@ -0,0 +1,63 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package service
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/bborbe/run"
+	libsentry "github.com/bborbe/sentry"
+	"github.com/getsentry/sentry-go"
+)
+
+// reloadSignal triggers the ReloadHandler of Main.
+var reloadSignal os.Signal = syscall.SIGHUP
+
+// ReloadHandler reloads configuration like log levels or feature flags while the service keeps running.
+// It is called once per signal and should be idempotent and fast, signals during a reload are coalesced.
+type ReloadHandler func(ctx context.Context) error
+
+// reloadOnSignal registers the given signal and calls the handler on each received signal until ctx is done.
+func reloadOnSignal(ctx context.Context, sig os.Signal, handler ReloadHandler, sentryClient libsentry.Client, logger Logger) {
+	signalCh := make(chan os.Signal, 1)
+	signal.Notify(signalCh, sig)
+	go func() {
+		defer signal.Stop(signalCh)
+		reloadOnSignalCh(ctx, signalCh, handler, sentryClient, logger)
+	}()
+}
+
+// reloadOnSignalCh logs and captures errors and panics of the handler, they never stop the service.
+func reloadOnSignalCh(ctx context.Context, signalCh <-chan os.Signal, handler ReloadHandler, sentryClient libsentry.Client, logger Logger) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case sig := <-signalCh:
+			logger.Infof("got signal %s => reload", sig)
+			if err := reload(ctx, handler); err != nil {
+				logger.Warningf("reload failed: %v", err)
+				sentryClient.CaptureException(
+					err,
+					&sentry.EventHint{
+						Context:           ctx,
+						OriginalException: err,
+					},
+					sentry.NewScope(),
+				)
+			}
+		}
+	}
+}
+
+// reload calls the handler with a fresh context canceled after it returns or on shutdown.
+func reload(ctx context.Context, handler ReloadHandler) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	return CatchPanic(run.Func(handler))(ctx)
+}
@@ -0,0 +1,119 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package service_test
+
+import (
+	"context"
+	stderrors "errors"
+	"os"
+	"sync/atomic"
+	"syscall"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/bborbe/service"
+	"github.com/bborbe/service/mocks"
+	"github.com/bborbe/service/servicetest"
+)
+
+var _ = Describe("ReloadOnSignalCh", func() {
+	var ctx context.Context
+	var cancel context.CancelFunc
+	var sentryClient *mocks.SentryClient
+	var logger *recordingLogger
+	var signalCh chan os.Signal
+	var done chan struct{}
+	var calls atomic.Int32
+	var handler service.ReloadHandler
+	BeforeEach(func() {
+		ctx, cancel = context.WithCancel(context.Background())
+		sentryClient = &mocks.SentryClient{}
+		logger = &recordingLogger{}
+		signalCh = make(chan os.Signal)
+		done = make(chan struct{})
+		calls.Store(0)
+		handler = func(ctx context.Context) error {
+			calls.Add(1)
+			return nil
+		}
+	})
+	JustBeforeEach(func() {
+		go func() {
+			defer close(done)
+			service.ReloadOnSignalCh(ctx, signalCh, handler, sentryClient, logger)
+		}()
+	})
+	AfterEach(func() {
+		cancel()
+		Eventually(done).Should(BeClosed())
+	})
+	It("calls the handler for every signal", func() {
+		signalCh <- syscall.SIGHUP
+		signalCh <- syscall.SIGHUP
+		cancel()
+		Eventually(done).Should(BeClosed())
+		Expect(calls.Load()).To(Equal(int32(2)))
+		Expect(sentryClient.CaptureExceptionCallCount()).To(Equal(0))
+	})
+	Context("failing handler", func() {
+		BeforeEach(func() {
+			handler = func(ctx context.Context) error {
+				calls.Add(1)
+				return stderrors.New("banana")
+			}
+		})
+		It("logs and captures the error and keeps listening", func() {
+			signalCh <- syscall.SIGHUP
+			signalCh <- syscall.SIGHUP
+			cancel()
+			Eventually(done).Should(BeClosed())
+			Expect(calls.Load()).To(Equal(int32(2)))
+			Expect(logger.Lines()).To(ContainElement("W reload failed: banana"))
+			Expect(sentryClient.CaptureExceptionCallCount()).To(Equal(2))
+		})
+	})
+	Context("panicking handler", func() {
+		BeforeEach(func() {
+			handler = func(ctx context.Context) error {
+				panic("banana")
+			}
+		})
+		It("captures the panic and keeps listening", func() {
+			signalCh <- syscall.SIGHUP
+			signalCh <- syscall.SIGHUP
+			cancel()
+			Eventually(done).Should(BeClosed())
+			Expect(sentryClient.CaptureExceptionCallCount()).To(Equal(2))
+			err, _, _ := sentryClient.CaptureExceptionArgsForCall(0)
+			var panicErr *service.PanicError
+			Expect(stderrors.As(err, &panicErr)).To(BeTrue())
+		})
+	})
+})
+
+var _ = Describe("WithReloadHandler", func() {
+	It("lets Main call the handler on SIGHUP", func() {
+		reloaded := make(chan struct{})
+		app := &mainApplication{
+			RunFunc: func(ctx context.Context) error {
+				Eventually(reloaded).Should(BeClosed())
+				return nil
+			},
+		}
+		Expect(service.Main(context.Background(), app, &app.SentryDSN, nil,
+			service.WithoutTimezoneOverride(),
+			service.WithRegisterer(nil),
+			service.WithSentryTransport(servicetest.NewSentryTransport()),
+			service.WithReloadHandler(func(ctx context.Context) error {
+				close(reloaded)
+				return nil
+			}),
+			service.WithOnStart(func(ctx context.Context) error {
+				return syscall.Kill(os.Getpid(), syscall.SIGHUP)
+			}),
+		)).To(Equal(service.ExitCodeSuccess))
+	})
+})
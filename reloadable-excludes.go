@@ -0,0 +1,39 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package service
+
+import (
+	libsentry "github.com/bborbe/sentry"
+	"github.com/getsentry/sentry-go"
+)
+
+// ExcludeErrorsProvider returns the current exclusion rules, e.g. loaded from a file or set by an admin endpoint.
+// It is called for every captured exception and must be safe for concurrent use.
+type ExcludeErrorsProvider func() libsentry.ExcludeErrors
+
+// newExcludingSentryClient returns a client that drops exceptions excluded by the current rules of the provider.
+func newExcludingSentryClient(client libsentry.Client, provider ExcludeErrorsProvider, logger Logger) libsentry.Client {
+	return &excludingSentryClient{
+		Client:   client,
+		provider: provider,
+		logger:   logger,
+	}
+}
+
+type excludingSentryClient struct {
+	libsentry.Client
+	provider ExcludeErrorsProvider
+	logger   Logger
+}
+
+func (e *excludingSentryClient) CaptureException(err error, hint *sentry.EventHint, scope sentry.EventModifier) *sentry.EventID {
+	if e.provider().IsExcluded(err) {
+		if e.logger.V(4) {
+			e.logger.Infof("error excluded by reloadable rules => skip capture exception: %v", err)
+		}
+		return nil
+	}
+	return e.Client.CaptureException(err, hint, scope)
+}
@@ -0,0 +1,61 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package service_test
+
+import (
+	stderrors "errors"
+	"sync"
+
+	libsentry "github.com/bborbe/sentry"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/bborbe/service"
+	"github.com/bborbe/service/mocks"
+)
+
+var _ = Describe("ExcludingSentryClient", func() {
+	var sentryClient *mocks.SentryClient
+	var client libsentry.Client
+	var mux sync.Mutex
+	var excludes libsentry.ExcludeErrors
+	var noisyErr error
+	BeforeEach(func() {
+		sentryClient = &mocks.SentryClient{}
+		noisyErr = stderrors.New("noisy")
+		excludes = nil
+		client = service.NewExcludingSentryClient(sentryClient, func() libsentry.ExcludeErrors {
+			mux.Lock()
+			defer mux.Unlock()
+			return excludes
+		}, service.NewGlogLogger())
+	})
+	It("evaluates the rules of the provider on every capture", func() {
+		client.CaptureException(noisyErr, nil, nil)
+		Expect(sentryClient.CaptureExceptionCallCount()).To(Equal(1))
+
+		mux.Lock()
+		excludes = libsentry.ExcludeErrors{
+			func(err error) bool {
+				return stderrors.Is(err, noisyErr)
+			},
+		}
+		mux.Unlock()
+		Expect(client.CaptureException(noisyErr, nil, nil)).To(BeNil())
+		Expect(sentryClient.CaptureExceptionCallCount()).To(Equal(1))
+		client.CaptureException(stderrors.New("other"), nil, nil)
+		Expect(sentryClient.CaptureExceptionCallCount()).To(Equal(2))
+
+		mux.Lock()
+		excludes = nil
+		mux.Unlock()
+		client.CaptureException(noisyErr, nil, nil)
+		Expect(sentryClient.CaptureExceptionCallCount()).To(Equal(3))
+	})
+	It("passes messages through", func() {
+		client.CaptureMessage("banana", nil, nil)
+		Expect(sentryClient.CaptureMessageCallCount()).To(Equal(1))
+	})
+})
@@ -0,0 +1,52 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package service
+
+import (
+	"context"
+	stderrors "errors"
+	"time"
+
+	"github.com/bborbe/errors"
+	"github.com/bborbe/run"
+)
+
+// RestartOptions defines how often and how fast Restart re-invokes a failed func.
+type RestartOptions struct {
+	// MaxAttempts including the first invocation. Values below 1 invoke the func once.
+	MaxAttempts int
+	// Backoff before the first restart.
+	Backoff time.Duration
+	// Factor the backoff is multiplied with on each further restart.
+	Factor float64
+	// MaxBackoff caps the backoff. Zero means no cap.
+	MaxBackoff time.Duration
+}
+
+// Restart re-invokes fn if it returns an error that is not a context error,
+// until it returns nil, ctx is canceled or the attempts are exhausted.
+// The last error is returned if the attempts are exhausted.
+func Restart(fn run.Func, opts RestartOptions) run.Func {
+	restart := backoff{
+		Initial: opts.Backoff,
+		Factor:  opts.Factor,
+		Max:     opts.MaxBackoff,
+		Stop: func(ctx context.Context, err error, attempt int) error {
+			if ctx.Err() != nil || stderrors.Is(err, context.Canceled) || stderrors.Is(err, context.DeadlineExceeded) {
+				return err
+			}
+			if attempt >= opts.MaxAttempts {
+				return errors.Wrapf(ctx, err, "restart failed after %d attempts", attempt)
+			}
+			return nil
+		},
+		Retrying: func(ctx context.Context, err error, attempt int, delay time.Duration) {
+			LoggerFromContext(ctx).Warningf("func failed => restart attempt %d/%d in %v: %v", attempt+1, opts.MaxAttempts, delay, err)
+		},
+	}
+	return func(ctx context.Context) error {
+		return restart.Run(ctx, fn)
+	}
+}
@@ -0,0 +1,87 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package service_test
+
+import (
+	"context"
+	stderrors "errors"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/bborbe/service"
+)
+
+var _ = Describe("Restart", func() {
+	var ctx context.Context
+	var cancel context.CancelFunc
+	var calls int
+	var results []error
+	var opts service.RestartOptions
+	var err error
+	BeforeEach(func() {
+		ctx, cancel = context.WithTimeout(context.Background(), time.Second)
+		calls = 0
+		results = nil
+		opts = service.RestartOptions{
+			MaxAttempts: 3,
+			Backoff:     time.Millisecond,
+			Factor:      2,
+		}
+	})
+	AfterEach(func() {
+		cancel()
+	})
+	JustBeforeEach(func() {
+		err = service.Restart(func(ctx context.Context) error {
+			defer func() { calls++ }()
+			if calls < len(results) {
+				return results[calls]
+			}
+			return results[len(results)-1]
+		}, opts)(ctx)
+	})
+	Context("transient error", func() {
+		BeforeEach(func() {
+			results = []error{stderrors.New("transient"), nil}
+		})
+		It("restarts until success", func() {
+			Expect(err).To(BeNil())
+			Expect(calls).To(Equal(2))
+		})
+	})
+	Context("attempts exhausted", func() {
+		var lastErr error
+		BeforeEach(func() {
+			lastErr = stderrors.New("last")
+			results = []error{stderrors.New("first"), stderrors.New("second"), lastErr}
+		})
+		It("returns the last error", func() {
+			Expect(stderrors.Is(err, lastErr)).To(BeTrue())
+			Expect(calls).To(Equal(3))
+		})
+	})
+	Context("context error", func() {
+		BeforeEach(func() {
+			results = []error{context.Canceled}
+		})
+		It("does not restart", func() {
+			Expect(stderrors.Is(err, context.Canceled)).To(BeTrue())
+			Expect(calls).To(Equal(1))
+		})
+	})
+	Context("context canceled during backoff", func() {
+		BeforeEach(func() {
+			opts.Backoff = time.Hour
+			results = []error{stderrors.New("banana")}
+			time.AfterFunc(10*time.Millisecond, cancel)
+		})
+		It("stops restarting", func() {
+			Expect(err).NotTo(BeNil())
+			Expect(calls).To(Equal(1))
+		})
+	})
+})
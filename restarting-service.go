@@ -0,0 +1,66 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/bborbe/errors"
+)
+
+// RestartPolicy defines when and how often a failed application is restarted.
+type RestartPolicy struct {
+	// MaxRestarts of the application. Zero never restarts.
+	MaxRestarts int
+	// Backoff before the first restart.
+	Backoff time.Duration
+	// Factor the backoff is multiplied with on each further restart.
+	Factor float64
+	// MaxBackoff caps the backoff. Zero means no cap.
+	MaxBackoff time.Duration
+	// IsRestartable reports whether the application is restarted after the given error.
+	// Nil restarts after every error.
+	IsRestartable func(err error) bool
+}
+
+// NewRestartingService restarts the given service if it fails with a restartable error
+// until the restarts of the policy are exhausted or ctx is canceled.
+func NewRestartingService(service Service, policy RestartPolicy) Service {
+	return &restartingService{
+		service: service,
+		policy:  policy,
+	}
+}
+
+type restartingService struct {
+	service Service
+	policy  RestartPolicy
+}
+
+func (r *restartingService) Run(ctx context.Context) error {
+	restart := backoff{
+		Initial: r.policy.Backoff,
+		Factor:  r.policy.Factor,
+		Max:     r.policy.MaxBackoff,
+		Stop: func(ctx context.Context, err error, attempt int) error {
+			restarts := attempt - 1
+			if ctx.Err() != nil {
+				return err
+			}
+			if r.policy.IsRestartable != nil && !r.policy.IsRestartable(err) {
+				return errors.Wrapf(ctx, err, "error is not restartable")
+			}
+			if restarts >= r.policy.MaxRestarts {
+				return errors.Wrapf(ctx, err, "restarts exhausted after %d restarts", restarts)
+			}
+			return nil
+		},
+		Retrying: func(ctx context.Context, err error, attempt int, delay time.Duration) {
+			LoggerFromContext(ctx).Warningf("application failed => restart %d/%d in %v: %v", attempt, r.policy.MaxRestarts, delay, err)
+		},
+	}
+	return restart.Run(ctx, r.service.Run)
+}
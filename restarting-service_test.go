@@ -0,0 +1,90 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package service_test
+
+import (
+	"context"
+	stderrors "errors"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/bborbe/service"
+	"github.com/bborbe/service/mocks"
+)
+
+var _ = Describe("RestartingService", func() {
+	var ctx context.Context
+	var cancel context.CancelFunc
+	var innerService *mocks.Service
+	var policy service.RestartPolicy
+	var err error
+	BeforeEach(func() {
+		ctx, cancel = context.WithTimeout(context.Background(), time.Second)
+		innerService = &mocks.Service{}
+		policy = service.RestartPolicy{
+			MaxRestarts: 2,
+			Backoff:     time.Millisecond,
+			Factor:      2,
+		}
+	})
+	AfterEach(func() {
+		cancel()
+	})
+	JustBeforeEach(func() {
+		err = service.NewRestartingService(innerService, policy).Run(ctx)
+	})
+	Context("restart then succeed", func() {
+		BeforeEach(func() {
+			innerService.RunReturnsOnCall(0, stderrors.New("banana"))
+			innerService.RunReturnsOnCall(1, nil)
+		})
+		It("returns no error", func() {
+			Expect(err).To(BeNil())
+		})
+		It("restarts once", func() {
+			Expect(innerService.RunCallCount()).To(Equal(2))
+		})
+	})
+	Context("restarts exhausted", func() {
+		BeforeEach(func() {
+			innerService.RunReturns(stderrors.New("banana"))
+		})
+		It("returns the error", func() {
+			Expect(err).NotTo(BeNil())
+			Expect(err.Error()).To(ContainSubstring("restarts exhausted"))
+			Expect(err.Error()).To(ContainSubstring("banana"))
+		})
+		It("runs max restarts plus the first run", func() {
+			Expect(innerService.RunCallCount()).To(Equal(3))
+		})
+	})
+	Context("not restartable error", func() {
+		BeforeEach(func() {
+			policy.IsRestartable = func(err error) bool {
+				return false
+			}
+			innerService.RunReturns(stderrors.New("banana"))
+		})
+		It("does not restart", func() {
+			Expect(err).NotTo(BeNil())
+			Expect(innerService.RunCallCount()).To(Equal(1))
+		})
+	})
+	Context("context canceled", func() {
+		BeforeEach(func() {
+			policy.Backoff = time.Hour
+			innerService.RunStub = func(ctx context.Context) error {
+				cancel()
+				return stderrors.New("banana")
+			}
+		})
+		It("stops restarting", func() {
+			Expect(err).NotTo(BeNil())
+			Expect(innerService.RunCallCount()).To(Equal(1))
+		})
+	})
+})
@@ -0,0 +1,42 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package service
+
+import (
+	"context"
+	stderrors "errors"
+	"time"
+
+	"github.com/bborbe/errors"
+	"github.com/bborbe/run"
+)
+
+// RetryWithBackoff retries fn until it succeeds or ctx is canceled, e.g. to wait for a database at startup.
+// The delay starts at initial, grows by factor up to max and is jittered to a random value
+// between half and the full delay. The wait is interrupted as soon as ctx is canceled and
+// the last error is returned wrapped with the number of attempts and joined with the error of ctx.
+func RetryWithBackoff(fn run.Func, initial time.Duration, max time.Duration, factor float64) run.Func {
+	retry := backoff{
+		Initial: initial,
+		Factor:  factor,
+		Max:     max,
+		Jitter:  true,
+		Stop: func(ctx context.Context, err error, attempt int) error {
+			if ctx.Err() != nil {
+				// joined with the context error, so Run treats the retry stopped by a shutdown as canceled
+				return errors.Wrapf(ctx, stderrors.Join(err, ctx.Err()), "retry failed after %d attempts", attempt)
+			}
+			return nil
+		},
+		Retrying: func(ctx context.Context, err error, attempt int, delay time.Duration) {
+			if logger := LoggerFromContext(ctx); logger.V(2) {
+				logger.Infof("attempt %d failed => retry in %v: %v", attempt, delay, err)
+			}
+		},
+	}
+	return func(ctx context.Context) error {
+		return retry.Run(ctx, fn)
+	}
+}
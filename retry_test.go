@@ -0,0 +1,77 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package service_test
+
+import (
+	"context"
+	stderrors "errors"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/bborbe/service"
+)
+
+var _ = Describe("RetryWithBackoff", func() {
+	var ctx context.Context
+	var cancel context.CancelFunc
+	var calls int
+	BeforeEach(func() {
+		ctx, cancel = context.WithTimeout(context.Background(), time.Second)
+		calls = 0
+	})
+	AfterEach(func() {
+		cancel()
+	})
+	It("retries until success", func() {
+		err := service.RetryWithBackoff(func(ctx context.Context) error {
+			calls++
+			if calls < 3 {
+				return stderrors.New("unreachable")
+			}
+			return nil
+		}, time.Millisecond, 10*time.Millisecond, 2)(ctx)
+		Expect(err).To(BeNil())
+		Expect(calls).To(Equal(3))
+	})
+	It("returns the last error with the number of attempts after cancel", func() {
+		lastErr := stderrors.New("unreachable")
+		time.AfterFunc(50*time.Millisecond, cancel)
+		err := service.RetryWithBackoff(func(ctx context.Context) error {
+			calls++
+			return lastErr
+		}, time.Millisecond, 5*time.Millisecond, 2)(ctx)
+		Expect(stderrors.Is(err, lastErr)).To(BeTrue())
+		Expect(calls).To(BeNumerically(">", 1))
+		Expect(err.Error()).To(ContainSubstring("retry failed after"))
+	})
+	It("interrupts the backoff on cancel", func() {
+		time.AfterFunc(10*time.Millisecond, cancel)
+		start := time.Now()
+		err := service.RetryWithBackoff(func(ctx context.Context) error {
+			calls++
+			return stderrors.New("unreachable")
+		}, time.Hour, time.Hour, 2)(ctx)
+		Expect(err.Error()).To(ContainSubstring("retry failed after 1 attempts"))
+		Expect(time.Since(start)).To(BeNumerically("<", 500*time.Millisecond))
+	})
+	It("returns an error matching the cancel of ctx", func() {
+		time.AfterFunc(10*time.Millisecond, cancel)
+		err := service.RetryWithBackoff(func(ctx context.Context) error {
+			return stderrors.New("unreachable")
+		}, time.Hour, time.Hour, 2)(ctx)
+		Expect(stderrors.Is(err, context.Canceled)).To(BeTrue())
+	})
+	It("is filtered as canceled by Run on shutdown during the backoff", func() {
+		time.AfterFunc(10*time.Millisecond, cancel)
+		err := service.Run(ctx, service.RetryWithBackoff(func(ctx context.Context) error {
+			calls++
+			return stderrors.New("unreachable")
+		}, time.Hour, time.Hour, 2))
+		Expect(err).To(BeNil())
+		Expect(calls).To(Equal(1))
+	})
+})
@@ -0,0 +1,27 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/bborbe/run"
+)
+
+// RunAll runs all given funcs concurrently and waits until every func completed.
+// A finished func does not cancel the others. All errors are joined in the order they occurred.
+func RunAll(ctx context.Context, funcs ...run.Func) error {
+	if len(funcs) == 0 {
+		return nil
+	}
+	var errs []error
+	for err := range run.Run(ctx, decorateFuncs(funcs)...) {
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,85 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package service_test
+
+import (
+	"context"
+	stderrors "errors"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/bborbe/service"
+)
+
+var _ = Describe("RunAll", func() {
+	var ctx context.Context
+	var cancel context.CancelFunc
+	BeforeEach(func() {
+		ctx, cancel = context.WithTimeout(context.Background(), time.Second)
+	})
+	AfterEach(func() {
+		cancel()
+	})
+	It("returns nil without funcs", func() {
+		Expect(service.RunAll(ctx)).To(BeNil())
+	})
+	It("lets every func run to completion", func() {
+		var completed bool
+		err := service.RunAll(
+			ctx,
+			func(ctx context.Context) error {
+				return nil
+			},
+			func(ctx context.Context) error {
+				select {
+				case <-ctx.Done():
+					return ctx.Err()
+				case <-time.After(20 * time.Millisecond):
+					completed = true
+					return nil
+				}
+			},
+		)
+		Expect(err).To(BeNil())
+		Expect(completed).To(BeTrue())
+	})
+	It("returns every failure", func() {
+		first := stderrors.New("first")
+		second := stderrors.New("second")
+		err := service.RunAll(
+			ctx,
+			func(ctx context.Context) error {
+				return first
+			},
+			func(ctx context.Context) error {
+				return second
+			},
+			func(ctx context.Context) error {
+				panic("third")
+			},
+			func(ctx context.Context) error {
+				return nil
+			},
+		)
+		Expect(err).NotTo(BeNil())
+		Expect(stderrors.Is(err, first)).To(BeTrue())
+		Expect(stderrors.Is(err, second)).To(BeTrue())
+		var panicErr *service.PanicError
+		Expect(stderrors.As(err, &panicErr)).To(BeTrue())
+	})
+	It("filters the cancellation of the context", func() {
+		cancel()
+		err := service.RunAll(
+			ctx,
+			func(ctx context.Context) error {
+				<-ctx.Done()
+				return ctx.Err()
+			},
+		)
+		Expect(err).To(BeNil())
+	})
+})
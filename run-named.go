@@ -0,0 +1,82 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package service
+
+import (
+	"context"
+	"runtime/debug"
+
+	"github.com/bborbe/errors"
+	"github.com/bborbe/run"
+)
+
+// FuncNameKey is the key of the func name in the data of errors returned by RunNamed.
+// The Sentry client reports it as tag.
+const FuncNameKey = "func"
+
+// ContextDecorator derives the context a single func of RunNamed runs with, e.g. to add a logger.
+type ContextDecorator func(ctx context.Context) context.Context
+
+// NamedFunc is a func with a name that identifies it in logs, errors and Sentry tags.
+type NamedFunc struct {
+	Name string
+	Func run.Func
+	// Decorators are applied in order to the context of the func after its name was added.
+	Decorators []ContextDecorator
+}
+
+type funcNameContextKey struct{}
+
+// FuncNameFromContext returns the name of the func RunNamed runs with ctx or an empty string.
+func FuncNameFromContext(ctx context.Context) string {
+	name, _ := ctx.Value(funcNameContextKey{}).(string)
+	return name
+}
+
+// RunNamed works like Run, but errors and panics mention the name of the failing func.
+// Each func gets its name in the context, see FuncNameFromContext, and its own Decorators applied.
+func RunNamed(ctx context.Context, funcs ...NamedFunc) error {
+	names := make([]string, len(funcs))
+	for i, fn := range funcs {
+		names[i] = fn.Name
+	}
+	return runFuncs(ctx, true, DefaultExcludeErrors(), names, namedFuncs(funcs))
+}
+
+func namedFuncs(funcs []NamedFunc) []run.Func {
+	result := make([]run.Func, len(funcs))
+	for i, fn := range funcs {
+		result[i] = fn.run
+	}
+	return result
+}
+
+func (n NamedFunc) run(ctx context.Context) error {
+	ctx = errors.AddToContext(ctx, FuncNameKey, n.Name)
+	ctx = context.WithValue(ctx, funcNameContextKey{}, n.Name)
+	for _, decorate := range n.Decorators {
+		ctx = decorate(ctx)
+	}
+	logger := LoggerFromContext(ctx)
+	if logger.V(3) {
+		logger.Infof("func %s started", n.Name)
+	}
+	if err := CatchPanicWithHandler(filterCanceled(n.Func), n.newPanicError)(ctx); err != nil {
+		return errors.Wrapf(ctx, err, "func %s failed", n.Name)
+	}
+	if logger.V(3) {
+		logger.Infof("func %s finished", n.Name)
+	}
+	return nil
+}
+
+// newPanicError attributes the panic to the func.
+func (n NamedFunc) newPanicError(recovered interface{}) error {
+	return &PanicError{
+		Value:    recovered,
+		Stack:    debug.Stack(),
+		FuncName: n.Name,
+	}
+}
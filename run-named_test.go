@@ -0,0 +1,121 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package service_test
+
+import (
+	"context"
+	stderrors "errors"
+	"time"
+
+	"github.com/bborbe/errors"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/bborbe/service"
+)
+
+var _ = Describe("RunNamed", func() {
+	var ctx context.Context
+	var cancel context.CancelFunc
+	var realErr error
+	BeforeEach(func() {
+		ctx, cancel = context.WithTimeout(context.Background(), time.Second)
+		realErr = stderrors.New("banana")
+	})
+	AfterEach(func() {
+		cancel()
+	})
+	waitForCancel := service.NamedFunc{
+		Name: "http",
+		Func: func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		},
+	}
+	It("mentions the name of the failing func", func() {
+		err := service.RunNamed(ctx, waitForCancel, service.NamedFunc{
+			Name: "consumer",
+			Func: func(ctx context.Context) error {
+				return realErr
+			},
+		})
+		Expect(err).NotTo(BeNil())
+		Expect(err.Error()).To(ContainSubstring("func consumer failed"))
+		Expect(err.Error()).NotTo(ContainSubstring("http"))
+		Expect(stderrors.Is(err, realErr)).To(BeTrue())
+	})
+	It("adds the name as error data for Sentry tags", func() {
+		err := service.RunNamed(ctx, waitForCancel, service.NamedFunc{
+			Name: "consumer",
+			Func: func(ctx context.Context) error {
+				return realErr
+			},
+		})
+		var dataErr errors.DataError
+		Expect(stderrors.As(err, &dataErr)).To(BeTrue())
+		Expect(dataErr.Data()).To(HaveKeyWithValue(service.FuncNameKey, "consumer"))
+	})
+	It("mentions the name of a panicking func", func() {
+		err := service.RunNamed(ctx, waitForCancel, service.NamedFunc{
+			Name: "cron",
+			Func: func(ctx context.Context) error {
+				panic("banana")
+			},
+		})
+		Expect(err.Error()).To(ContainSubstring("func cron failed"))
+		Expect(err.Error()).To(ContainSubstring("catch panic in func cron: banana"))
+		var panicErr *service.PanicError
+		Expect(stderrors.As(err, &panicErr)).To(BeTrue())
+		Expect(panicErr.FuncName).To(Equal("cron"))
+	})
+	It("returns nil if all funcs succeed", func() {
+		err := service.RunNamed(ctx, waitForCancel, service.NamedFunc{
+			Name: "init",
+			Func: func(ctx context.Context) error {
+				return nil
+			},
+		})
+		Expect(err).To(BeNil())
+	})
+	It("passes each func its own name in the context", func() {
+		names := make(chan string, 2)
+		record := func(ctx context.Context) error {
+			names <- service.FuncNameFromContext(ctx)
+			return nil
+		}
+		Expect(service.RunNamed(ctx,
+			service.NamedFunc{Name: "http", Func: record},
+			service.NamedFunc{Name: "consumer", Func: record},
+		)).To(BeNil())
+		close(names)
+		var received []string
+		for name := range names {
+			received = append(received, name)
+		}
+		Expect(received).To(ConsistOf("http", "consumer"))
+	})
+	It("applies the decorators of each func", func() {
+		type key struct{}
+		values := make(chan string, 2)
+		Expect(service.RunNamed(ctx,
+			service.NamedFunc{
+				Name: "consumer",
+				Func: func(ctx context.Context) error {
+					values <- ctx.Value(key{}).(string)
+					return nil
+				},
+				Decorators: []service.ContextDecorator{
+					func(ctx context.Context) context.Context {
+						return context.WithValue(ctx, key{}, "logger-"+service.FuncNameFromContext(ctx))
+					},
+				},
+			},
+		)).To(BeNil())
+		Expect(values).To(Receive(Equal("logger-consumer")))
+	})
+	It("returns an empty name outside of RunNamed", func() {
+		Expect(service.FuncNameFromContext(ctx)).To(BeEmpty())
+	})
+})
@@ -0,0 +1,105 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package service
+
+import (
+	"context"
+	"errors"
+	"sort"
+
+	"github.com/bborbe/run"
+)
+
+// DefaultShutdownPriority of funcs that do not declare one.
+const DefaultShutdownPriority = 0
+
+// ShutdownPrioritizer declares the shutdown priority of a func, higher priorities are drained first.
+type ShutdownPrioritizer interface {
+	ShutdownPriority() int
+}
+
+// PrioritizedFunc is a func that declares its own shutdown priority.
+type PrioritizedFunc struct {
+	Priority int
+	Func     run.Func
+}
+
+// ShutdownPriority of the func.
+func (p PrioritizedFunc) ShutdownPriority() int {
+	return p.Priority
+}
+
+// Run the func.
+func (p PrioritizedFunc) Run(ctx context.Context) error {
+	return p.Func(ctx)
+}
+
+// RunPrioritized starts all funcs concurrently like Run. As soon as the first func finishes or ctx is canceled,
+// the funcs are canceled in the order of their shutdown priority, highest first. Each priority is drained
+// completely before the next lower one is canceled. A runnable that does not implement ShutdownPrioritizer
+// gets DefaultShutdownPriority.
+func RunPrioritized(ctx context.Context, runnables ...run.Runnable) error {
+	if len(runnables) == 0 {
+		return nil
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	type entry struct {
+		priority int
+		cancel   context.CancelFunc
+		done     chan struct{}
+	}
+	errCh := make(chan error, len(runnables))
+	entries := make([]entry, len(runnables))
+	for i, runnable := range runnables {
+		priority := DefaultShutdownPriority
+		if prioritizer, ok := runnable.(ShutdownPrioritizer); ok {
+			priority = prioritizer.ShutdownPriority()
+		}
+		// the func is only canceled by the ordered drain, not by ctx directly
+		fnCtx, fnCancel := context.WithCancel(context.WithoutCancel(ctx))
+		done := make(chan struct{})
+		entries[i] = entry{
+			priority: priority,
+			cancel:   fnCancel,
+			done:     done,
+		}
+		fn := decorateFunc(runnable.Run)
+		go func() {
+			defer close(done)
+			defer cancel()
+			errCh <- fn(fnCtx)
+		}()
+	}
+
+	<-ctx.Done()
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].priority > entries[j].priority
+	})
+	for start := 0; start < len(entries); {
+		end := start
+		for end < len(entries) && entries[end].priority == entries[start].priority {
+			entries[end].cancel()
+			end++
+		}
+		if logger := LoggerFromContext(ctx); logger.V(2) {
+			logger.Infof("drain %d funcs with shutdown priority %d", end-start, entries[start].priority)
+		}
+		for _, e := range entries[start:end] {
+			<-e.done
+		}
+		start = end
+	}
+	close(errCh)
+
+	var errs []error
+	for err := range errCh {
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
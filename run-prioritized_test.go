@@ -0,0 +1,77 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package service_test
+
+import (
+	"context"
+	stderrors "errors"
+	"sync"
+	"time"
+
+	"github.com/bborbe/run"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/bborbe/service"
+)
+
+var _ = Describe("RunPrioritized", func() {
+	var ctx context.Context
+	var cancel context.CancelFunc
+	var mux sync.Mutex
+	var drained []string
+	BeforeEach(func() {
+		ctx, cancel = context.WithTimeout(context.Background(), time.Second)
+		drained = nil
+	})
+	AfterEach(func() {
+		cancel()
+	})
+	drain := func(name string) run.Func {
+		return func(ctx context.Context) error {
+			<-ctx.Done()
+			// give lower priorities the chance to return too early
+			time.Sleep(5 * time.Millisecond)
+			mux.Lock()
+			defer mux.Unlock()
+			drained = append(drained, name)
+			return nil
+		}
+	}
+	It("drains in the order of the declared priorities", func() {
+		time.AfterFunc(10*time.Millisecond, cancel)
+		err := service.RunPrioritized(
+			ctx,
+			service.PrioritizedFunc{Priority: 10, Func: drain("database")},
+			service.PrioritizedFunc{Priority: 100, Func: drain("http")},
+			service.PrioritizedFunc{Priority: 50, Func: drain("consumer")},
+		)
+		Expect(err).To(BeNil())
+		Expect(drained).To(Equal([]string{"http", "consumer", "database"}))
+	})
+	It("uses the default priority for runnables without priority", func() {
+		time.AfterFunc(10*time.Millisecond, cancel)
+		err := service.RunPrioritized(
+			ctx,
+			service.PrioritizedFunc{Priority: service.DefaultShutdownPriority - 1, Func: drain("database")},
+			drain("default"),
+			service.PrioritizedFunc{Priority: service.DefaultShutdownPriority + 1, Func: drain("http")},
+		)
+		Expect(err).To(BeNil())
+		Expect(drained).To(Equal([]string{"http", "default", "database"}))
+	})
+	It("begins the drain when the first func finishes", func() {
+		realErr := stderrors.New("banana")
+		err := service.RunPrioritized(
+			ctx,
+			service.PrioritizedFunc{Priority: 1, Func: drain("database")},
+			service.PrioritizedFunc{Priority: 2, Func: func(ctx context.Context) error {
+				return realErr
+			}},
+		)
+		Expect(stderrors.Is(err, realErr)).To(BeTrue())
+		Expect(drained).To(Equal([]string{"database"}))
+	})
+})
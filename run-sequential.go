@@ -0,0 +1,26 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package service
+
+import (
+	"context"
+
+	"github.com/bborbe/run"
+)
+
+// RunSequential runs the given funcs one after another, e.g. migrations before the HTTP server.
+// It stops at the first error or as soon as ctx is canceled without starting the remaining funcs.
+// Each func is decorated like in Run.
+func RunSequential(ctx context.Context, funcs ...run.Func) error {
+	for _, fn := range decorateFuncs(funcs) {
+		if ctx.Err() != nil {
+			return nil
+		}
+		if err := fn(ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package service_test
+
+import (
+	"context"
+	stderrors "errors"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/bborbe/service"
+)
+
+var _ = Describe("RunSequential", func() {
+	var ctx context.Context
+	var cancel context.CancelFunc
+	var steps []string
+	step := func(name string, err error) func(ctx context.Context) error {
+		return func(ctx context.Context) error {
+			steps = append(steps, name)
+			return err
+		}
+	}
+	BeforeEach(func() {
+		ctx, cancel = context.WithTimeout(context.Background(), time.Second)
+		steps = nil
+	})
+	AfterEach(func() {
+		cancel()
+	})
+	It("runs all steps in order", func() {
+		err := service.RunSequential(ctx, step("first", nil), step("second", nil), step("third", nil))
+		Expect(err).To(BeNil())
+		Expect(steps).To(Equal([]string{"first", "second", "third"}))
+	})
+	It("does not run step 3 if step 2 fails", func() {
+		err := service.RunSequential(ctx, step("first", nil), step("second", stderrors.New("banana")), step("third", nil))
+		Expect(err).NotTo(BeNil())
+		Expect(steps).To(Equal([]string{"first", "second"}))
+	})
+	It("recovers a panic of a step", func() {
+		err := service.RunSequential(
+			ctx,
+			func(ctx context.Context) error {
+				panic("banana")
+			},
+			step("second", nil),
+		)
+		var panicErr *service.PanicError
+		Expect(stderrors.As(err, &panicErr)).To(BeTrue())
+		Expect(steps).To(BeEmpty())
+	})
+	It("does not start further steps after cancel", func() {
+		err := service.RunSequential(
+			ctx,
+			func(ctx context.Context) error {
+				cancel()
+				return ctx.Err()
+			},
+			step("second", nil),
+		)
+		Expect(err).To(BeNil())
+		Expect(steps).To(BeEmpty())
+	})
+})
@@ -0,0 +1,71 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package service
+
+import (
+	"context"
+	"sync"
+
+	"github.com/bborbe/run"
+	"github.com/bborbe/sentry"
+)
+
+// RunSummary reports how the funcs of RunWithSummary completed.
+type RunSummary struct {
+	// Total number of funcs.
+	Total int
+	// Succeeded funcs returned nil.
+	Succeeded int
+	// Errored funcs returned an error or panicked.
+	Errored int
+	// Cancelled funcs returned because their context was canceled.
+	Cancelled int
+}
+
+// RunWithSummary works like Run and additionally reports how the funcs completed.
+// Errors of DefaultExcludeErrors returned after the cancel are counted as Cancelled.
+// If ctx is already canceled no func is started and the summary only counts the Total.
+func RunWithSummary(ctx context.Context, funcs ...run.Func) (RunSummary, error) {
+	excludeErrors := DefaultExcludeErrors()
+	recorder := &runSummaryRecorder{
+		summary: RunSummary{
+			Total: len(funcs),
+		},
+	}
+	recorded := make([]run.Func, len(funcs))
+	for i, fn := range funcs {
+		recorded[i] = recorder.record(excludeErrors, CatchPanic(fn))
+	}
+	err := runFuncs(ctx, true, excludeErrors, funcNames(funcs), recorded)
+	return recorder.Summary(), err
+}
+
+type runSummaryRecorder struct {
+	mux     sync.Mutex
+	summary RunSummary
+}
+
+func (r *runSummaryRecorder) record(excludeErrors sentry.ExcludeErrors, fn run.Func) run.Func {
+	return func(ctx context.Context) error {
+		err := fn(ctx)
+		r.mux.Lock()
+		defer r.mux.Unlock()
+		switch {
+		case err == nil:
+			r.summary.Succeeded++
+		case isCanceledBy(ctx, err), ctx.Err() != nil && excludeErrors.IsExcluded(err):
+			r.summary.Cancelled++
+		default:
+			r.summary.Errored++
+		}
+		return err
+	}
+}
+
+func (r *runSummaryRecorder) Summary() RunSummary {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	return r.summary
+}
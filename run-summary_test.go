@@ -0,0 +1,148 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package service_test
+
+import (
+	"context"
+	stderrors "errors"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/bborbe/service"
+)
+
+var _ = Describe("RunWithSummary", func() {
+	var ctx context.Context
+	var cancel context.CancelFunc
+	var summary service.RunSummary
+	var err error
+	BeforeEach(func() {
+		ctx, cancel = context.WithTimeout(context.Background(), time.Second)
+	})
+	AfterEach(func() {
+		cancel()
+	})
+	Context("mixed outcomes", func() {
+		BeforeEach(func() {
+			release := make(chan struct{})
+			summary, err = service.RunWithSummary(
+				ctx,
+				func(ctx context.Context) error {
+					<-release
+					return stderrors.New("banana")
+				},
+				func(ctx context.Context) error {
+					close(release)
+					return nil
+				},
+				func(ctx context.Context) error {
+					<-ctx.Done()
+					return ctx.Err()
+				},
+				func(ctx context.Context) error {
+					<-ctx.Done()
+					panic("cleanup failed")
+				},
+			)
+		})
+		It("returns the errors", func() {
+			Expect(err).NotTo(BeNil())
+		})
+		It("counts all funcs", func() {
+			Expect(summary.Total).To(Equal(4))
+		})
+		It("counts each outcome", func() {
+			Expect(summary.Succeeded).To(Equal(1))
+			Expect(summary.Errored).To(Equal(2))
+			Expect(summary.Cancelled).To(Equal(1))
+		})
+	})
+	Context("all succeed", func() {
+		BeforeEach(func() {
+			summary, err = service.RunWithSummary(
+				ctx,
+				func(ctx context.Context) error {
+					return nil
+				},
+			)
+		})
+		It("returns no error", func() {
+			Expect(err).To(BeNil())
+		})
+		It("counts the success", func() {
+			Expect(summary).To(Equal(service.RunSummary{Total: 1, Succeeded: 1}))
+		})
+	})
+	Context("context canceled before start", func() {
+		var called bool
+		BeforeEach(func() {
+			called = false
+			cancel()
+			summary, err = service.RunWithSummary(
+				ctx,
+				func(ctx context.Context) error {
+					called = true
+					return nil
+				},
+			)
+		})
+		It("returns no error", func() {
+			Expect(err).To(BeNil())
+		})
+		It("starts no func", func() {
+			Expect(called).To(BeFalse())
+		})
+		It("counts only the total", func() {
+			Expect(summary).To(Equal(service.RunSummary{Total: 1}))
+		})
+	})
+	Context("excluded error after cancel", func() {
+		BeforeEach(func() {
+			summary, err = service.RunWithSummary(
+				ctx,
+				func(ctx context.Context) error {
+					return nil
+				},
+				func(ctx context.Context) error {
+					<-ctx.Done()
+					return context.DeadlineExceeded
+				},
+			)
+		})
+		It("returns no error", func() {
+			Expect(err).To(BeNil())
+		})
+		It("counts the cancel", func() {
+			Expect(summary).To(Equal(service.RunSummary{Total: 2, Succeeded: 1, Cancelled: 1}))
+		})
+	})
+	It("sets the HealthState of the context", func() {
+		healthState := service.NewHealthState()
+		healthState.SetReady(true)
+		_, err = service.RunWithSummary(
+			service.ContextWithHealthState(ctx, healthState),
+			func(ctx context.Context) error {
+				return nil
+			},
+		)
+		Expect(err).To(BeNil())
+		Expect(healthState.Live()).To(BeTrue())
+		Expect(healthState.Ready()).To(BeFalse())
+	})
+	It("counts panics with func metrics", func() {
+		registry := prometheus.NewRegistry()
+		summary, _ = service.RunWithSummary(
+			service.ContextWithFuncMetrics(ctx, registry),
+			func(ctx context.Context) error {
+				panic("banana")
+			},
+		)
+		Expect(summary.Errored).To(Equal(1))
+		Expect(metricLabels(registry, "service_function_panics_total")).To(ConsistOf(map[string]string{"name": "func 0"}))
+	})
+})
@@ -0,0 +1,32 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package service
+
+import (
+	"context"
+	stderrors "errors"
+	"time"
+)
+
+// ErrRunTimeout is the cause of the context of the application once the RunTimeout of Main exceeded.
+var ErrRunTimeout = stderrors.New("run timeout exceeded")
+
+// contextWithRunTimeout cancels ctx with context.DeadlineExceeded and the cause ErrRunTimeout after timeout.
+// Zero disables the timeout.
+func contextWithRunTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeoutCause(ctx, timeout, ErrRunTimeout)
+}
+
+// isRunTimeout reports whether the application stopped because of the RunTimeout
+// and returned no error other than the deadline.
+func isRunTimeout(ctx context.Context, err error) bool {
+	if !stderrors.Is(context.Cause(ctx), ErrRunTimeout) {
+		return false
+	}
+	return err == nil || stderrors.Is(err, context.DeadlineExceeded) || stderrors.Is(err, ErrRunTimeout)
+}
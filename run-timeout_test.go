@@ -0,0 +1,73 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package service_test
+
+import (
+	"context"
+	stderrors "errors"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/bborbe/service"
+	"github.com/bborbe/service/servicetest"
+)
+
+var _ = Describe("WithRunTimeout", func() {
+	var app *mainApplication
+	var transport servicetest.SentryTransport
+	var fns []service.OptionsFn
+	BeforeEach(func() {
+		transport = servicetest.NewSentryTransport()
+		app = &mainApplication{
+			RunFunc: func(ctx context.Context) error {
+				<-ctx.Done()
+				Expect(stderrors.Is(context.Cause(ctx), service.ErrRunTimeout)).To(BeTrue())
+				return ctx.Err()
+			},
+		}
+		fns = []service.OptionsFn{
+			service.WithoutTimezoneOverride(),
+			service.WithRegisterer(nil),
+			service.WithSentryTransport(transport),
+			service.WithRunTimeout(50 * time.Millisecond),
+		}
+	})
+	main := func() int {
+		return service.Main(context.Background(), app, &app.SentryDSN, nil, fns...)
+	}
+	It("cancels the application with deadline exceeded and exits with success", func() {
+		Expect(main()).To(Equal(service.ExitCodeSuccess))
+		Expect(transport.Events()).To(BeEmpty())
+	})
+	It("exits with the configured exit code", func() {
+		fns = append(fns, service.WithRunTimeoutExitCode(124))
+		Expect(main()).To(Equal(124))
+	})
+	It("exits with the configured exit code if the application returns nil", func() {
+		app.RunFunc = func(ctx context.Context) error {
+			<-ctx.Done()
+			return nil
+		}
+		fns = append(fns, service.WithRunTimeoutExitCode(124))
+		Expect(main()).To(Equal(124))
+	})
+	It("returns the failure of an application failing after the timeout", func() {
+		app.RunFunc = func(ctx context.Context) error {
+			<-ctx.Done()
+			return stderrors.New("banana")
+		}
+		fns = append(fns, service.WithRunTimeoutExitCode(124))
+		Expect(main()).To(Equal(service.ExitCodeFailure))
+	})
+	It("does not limit an application finishing in time", func() {
+		app.RunFunc = func(ctx context.Context) error {
+			return nil
+		}
+		fns = append(fns, service.WithRunTimeoutExitCode(124))
+		Expect(main()).To(Equal(service.ExitCodeSuccess))
+	})
+})
@@ -0,0 +1,71 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/bborbe/run"
+	"github.com/getsentry/sentry-go"
+)
+
+// RunUntilError runs all given funcs concurrently like Run, but a func returning nil
+// does not stop the others. Only the first error or the cancellation of ctx cancels the group.
+// It waits until all funcs have returned.
+//
+// Like Run it starts no func if ctx is already canceled, filters DefaultExcludeErrors after the cancel,
+// counts errors and panics for ContextWithFuncMetrics and updates the HealthState of the context,
+// which turns not ready once the group is canceled. Funcs returning nil are not reported
+// to the UnexpectedCompletionHandler, completing is expected here.
+//
+// Funcs wrapped with Optional are isolated from the group:
+//
+//	         | nil             | error                    | panic
+//	required | group continues | group canceled, returned | group canceled, returned as PanicError
+//	optional | group continues | captured, group continues | captured, group continues
+func RunUntilError(ctx context.Context, funcs ...run.Func) error {
+	return runFuncs(ctx, false, DefaultExcludeErrors(), funcNames(funcs), funcs)
+}
+
+// Optional marks fn as optional for RunUntilError. Its errors and panics are logged,
+// captured with the Sentry client of the context and swallowed, so they never cancel the other funcs.
+func Optional(fn run.Func) run.Func {
+	fn = CatchPanic(fn)
+	return func(ctx context.Context) error {
+		if err := fn(ctx); err != nil {
+			var panicErr *PanicError
+			if errors.As(err, &panicErr) {
+				LoggerFromContext(ctx).Warningf("optional func panicked: %v\n%s", panicErr.Value, panicErr.Stack)
+			} else {
+				LoggerFromContext(ctx).Warningf("optional func failed: %v", err)
+			}
+			captureOptionalFailure(ctx, err)
+		}
+		return nil
+	}
+}
+
+// captureOptionalFailure captures err with the Sentry client of the context, see ContextWithSentryClient.
+// Errors of canceling the context are a shutdown and not captured.
+func captureOptionalFailure(ctx context.Context, err error) {
+	sentryClient := SentryClientFromContext(ctx)
+	if sentryClient == nil || isCanceledBy(ctx, err) {
+		return
+	}
+	scope := sentry.NewScope()
+	var panicErr *PanicError
+	if errors.As(err, &panicErr) {
+		scope.SetExtra("stack", string(panicErr.Stack))
+	}
+	sentryClient.CaptureException(
+		err,
+		&sentry.EventHint{
+			Context:           ctx,
+			OriginalException: err,
+		},
+		scope,
+	)
+}
@@ -0,0 +1,298 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package service_test
+
+import (
+	"context"
+	stderrors "errors"
+	"time"
+
+	"github.com/bborbe/run"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/bborbe/service"
+	"github.com/bborbe/service/mocks"
+	"github.com/bborbe/service/servicetest"
+)
+
+var _ = Describe("RunUntilError", func() {
+	var ctx context.Context
+	var cancel context.CancelFunc
+	var err error
+	BeforeEach(func() {
+		ctx, cancel = context.WithTimeout(context.Background(), time.Second)
+	})
+	AfterEach(func() {
+		cancel()
+	})
+	Context("func completes without error", func() {
+		var otherDone bool
+		BeforeEach(func() {
+			otherDone = false
+			err = service.RunUntilError(
+				ctx,
+				func(ctx context.Context) error {
+					return nil
+				},
+				func(ctx context.Context) error {
+					select {
+					case <-ctx.Done():
+						return ctx.Err()
+					case <-time.After(50 * time.Millisecond):
+						otherDone = true
+						return nil
+					}
+				},
+			)
+		})
+		It("returns no error", func() {
+			Expect(err).To(BeNil())
+		})
+		It("lets the other func continue", func() {
+			Expect(otherDone).To(BeTrue())
+		})
+	})
+	Context("func returns error", func() {
+		var otherCanceled bool
+		BeforeEach(func() {
+			otherCanceled = false
+			err = service.RunUntilError(
+				ctx,
+				func(ctx context.Context) error {
+					return stderrors.New("banana")
+				},
+				func(ctx context.Context) error {
+					<-ctx.Done()
+					otherCanceled = true
+					return ctx.Err()
+				},
+			)
+		})
+		It("returns the error", func() {
+			Expect(err).NotTo(BeNil())
+			Expect(err.Error()).To(ContainSubstring("banana"))
+		})
+		It("cancels the other func", func() {
+			Expect(otherCanceled).To(BeTrue())
+		})
+	})
+	Context("context canceled", func() {
+		BeforeEach(func() {
+			cancel()
+			err = service.RunUntilError(
+				ctx,
+				func(ctx context.Context) error {
+					<-ctx.Done()
+					return ctx.Err()
+				},
+			)
+		})
+		It("returns no error", func() {
+			Expect(err).To(BeNil())
+		})
+	})
+	Context("without funcs", func() {
+		BeforeEach(func() {
+			err = service.RunUntilError(ctx, []run.Func{}...)
+		})
+		It("returns no error", func() {
+			Expect(err).To(BeNil())
+		})
+	})
+	Context("context canceled before start", func() {
+		var called bool
+		BeforeEach(func() {
+			called = false
+			cancel()
+			err = service.RunUntilError(
+				ctx,
+				func(ctx context.Context) error {
+					called = true
+					return stderrors.New("banana")
+				},
+			)
+		})
+		It("returns no error", func() {
+			Expect(err).To(BeNil())
+		})
+		It("starts no func", func() {
+			Expect(called).To(BeFalse())
+		})
+	})
+	Context("excluded error after cancel", func() {
+		BeforeEach(func() {
+			err = service.RunUntilError(
+				ctx,
+				func(ctx context.Context) error {
+					return stderrors.New("banana")
+				},
+				func(ctx context.Context) error {
+					<-ctx.Done()
+					return context.DeadlineExceeded
+				},
+			)
+		})
+		It("returns only the error", func() {
+			Expect(err).NotTo(BeNil())
+			Expect(err.Error()).To(ContainSubstring("banana"))
+			Expect(err.Error()).NotTo(ContainSubstring("deadline"))
+		})
+	})
+	Context("with HealthState", func() {
+		var healthState service.HealthState
+		var readyAfterSuccess bool
+		BeforeEach(func() {
+			healthState = service.NewHealthState()
+			healthState.SetReady(true)
+			err = service.RunUntilError(
+				service.ContextWithHealthState(ctx, healthState),
+				func(ctx context.Context) error {
+					return nil
+				},
+				func(ctx context.Context) error {
+					time.Sleep(20 * time.Millisecond)
+					readyAfterSuccess = healthState.Ready()
+					return stderrors.New("banana")
+				},
+			)
+		})
+		It("returns the error", func() {
+			Expect(err).NotTo(BeNil())
+		})
+		It("sets live", func() {
+			Expect(healthState.Live()).To(BeTrue())
+		})
+		It("stays ready after a func returned nil", func() {
+			Expect(readyAfterSuccess).To(BeTrue())
+		})
+		It("sets not ready after the error", func() {
+			Expect(healthState.Ready()).To(BeFalse())
+		})
+	})
+	It("counts errors with func metrics", func() {
+		registry := prometheus.NewRegistry()
+		_ = service.RunUntilError(
+			service.ContextWithFuncMetrics(ctx, registry),
+			func(ctx context.Context) error {
+				return stderrors.New("banana")
+			},
+		)
+		Expect(metricLabels(registry, "service_function_errors_total")).To(ConsistOf(map[string]string{"name": "func 0"}))
+	})
+	DescribeTable("required and optional outcomes",
+		func(optional bool, outcome string, expectCanceled bool, expectErr bool) {
+			fn := func(ctx context.Context) error {
+				switch outcome {
+				case "error":
+					return stderrors.New("banana")
+				case "panic":
+					panic("banana")
+				default:
+					return nil
+				}
+			}
+			if optional {
+				fn = service.Optional(fn)
+			}
+			var otherCanceled bool
+			err := service.RunUntilError(
+				ctx,
+				fn,
+				func(ctx context.Context) error {
+					select {
+					case <-ctx.Done():
+						otherCanceled = true
+						return ctx.Err()
+					case <-time.After(50 * time.Millisecond):
+						return nil
+					}
+				},
+			)
+			Expect(otherCanceled).To(Equal(expectCanceled))
+			if expectErr {
+				Expect(err).NotTo(BeNil())
+			} else {
+				Expect(err).To(BeNil())
+			}
+		},
+		Entry("required nil", false, "nil", false, false),
+		Entry("required error", false, "error", true, true),
+		Entry("required panic", false, "panic", true, true),
+		Entry("optional nil", true, "nil", false, false),
+		Entry("optional error", true, "error", false, false),
+		Entry("optional panic", true, "panic", false, false),
+	)
+})
+
+var _ = Describe("Optional", func() {
+	var ctx context.Context
+	var sentryClient *mocks.SentryClient
+	BeforeEach(func() {
+		sentryClient = &mocks.SentryClient{}
+		ctx = service.ContextWithSentryClient(context.Background(), sentryClient)
+	})
+	It("captures a panic as PanicError", func() {
+		err := service.Optional(func(ctx context.Context) error {
+			panic("banana")
+		})(ctx)
+		Expect(err).To(BeNil())
+		Expect(sentryClient.CaptureExceptionCallCount()).To(Equal(1))
+		captured, hint, _ := sentryClient.CaptureExceptionArgsForCall(0)
+		var panicErr *service.PanicError
+		Expect(stderrors.As(captured, &panicErr)).To(BeTrue())
+		Expect(panicErr.Value).To(Equal("banana"))
+		Expect(hint.Context).To(Equal(ctx))
+	})
+	It("captures an error", func() {
+		banana := stderrors.New("banana")
+		err := service.Optional(func(ctx context.Context) error {
+			return banana
+		})(ctx)
+		Expect(err).To(BeNil())
+		Expect(sentryClient.CaptureExceptionCallCount()).To(Equal(1))
+		captured, _, _ := sentryClient.CaptureExceptionArgsForCall(0)
+		Expect(captured).To(Equal(banana))
+	})
+	It("captures nothing on success", func() {
+		Expect(service.Optional(func(ctx context.Context) error {
+			return nil
+		})(ctx)).To(BeNil())
+		Expect(sentryClient.CaptureExceptionCallCount()).To(Equal(0))
+	})
+	It("captures nothing if canceled", func() {
+		ctx, cancel := context.WithCancel(ctx)
+		cancel()
+		Expect(service.Optional(func(ctx context.Context) error {
+			return ctx.Err()
+		})(ctx)).To(BeNil())
+		Expect(sentryClient.CaptureExceptionCallCount()).To(Equal(0))
+	})
+	It("only logs without Sentry client", func() {
+		Expect(service.SentryClientFromContext(context.Background())).To(BeNil())
+		Expect(service.Optional(func(ctx context.Context) error {
+			panic("banana")
+		})(context.Background())).To(BeNil())
+	})
+	It("captures with the Sentry client of Main", func() {
+		transport := servicetest.NewSentryTransport()
+		app := &mainApplication{
+			RunFunc: func(ctx context.Context) error {
+				return service.RunUntilError(ctx, service.Optional(func(ctx context.Context) error {
+					panic("banana")
+				}))
+			},
+		}
+		Expect(service.Main(context.Background(), app, &app.SentryDSN, nil,
+			service.WithoutTimezoneOverride(),
+			service.WithRegisterer(nil),
+			service.WithSentryTransport(transport),
+		)).To(Equal(service.ExitCodeSuccess))
+		Expect(transport.Events()).To(HaveLen(1))
+		Expect(transport.Events()[0].Exception).NotTo(BeEmpty())
+		Expect(transport.Events()[0].Exception[0].Value).To(ContainSubstring("catch panic: banana"))
+	})
+})
@@ -0,0 +1,60 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package service
+
+import (
+	"context"
+
+	"github.com/bborbe/run"
+)
+
+// RunWithConcurrencyLimit works like Run, but at most limit funcs execute at the same time.
+// The funcs start in the given order whenever a slot is free. Like in Run the first func that returns
+// cancels all others, funcs still queued at that moment return nil without starting.
+// A limit of zero or less runs all funcs at once.
+func RunWithConcurrencyLimit(ctx context.Context, limit int, funcs ...run.Func) error {
+	if limit <= 0 || limit >= len(funcs) {
+		return Run(ctx, funcs...)
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	slots := make(chan struct{}, limit)
+	starts := make([]chan struct{}, len(funcs))
+	for i := range starts {
+		starts[i] = make(chan struct{})
+	}
+	go func() {
+		for _, start := range starts {
+			select {
+			case <-ctx.Done():
+				return
+			case slots <- struct{}{}:
+				close(start)
+			}
+		}
+	}()
+
+	limited := make([]run.Func, len(funcs))
+	for i, fn := range funcs {
+		limited[i] = func(ctx context.Context) error {
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-starts[i]:
+			}
+			defer func() {
+				<-slots
+			}()
+			if ctx.Err() != nil {
+				return nil
+			}
+			// cancel before the slot is freed, so no queued func starts after the first finished
+			defer cancel()
+			return fn(ctx)
+		}
+	}
+	return Run(ctx, limited...)
+}
@@ -0,0 +1,108 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package service_test
+
+import (
+	"context"
+	stderrors "errors"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/bborbe/run"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/bborbe/service"
+)
+
+var _ = Describe("RunWithConcurrencyLimit", func() {
+	var ctx context.Context
+	var cancel context.CancelFunc
+	var active atomic.Int32
+	var peak atomic.Int32
+	var mux sync.Mutex
+	var started []int
+	BeforeEach(func() {
+		ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+		active.Store(0)
+		peak.Store(0)
+		started = nil
+	})
+	AfterEach(func() {
+		cancel()
+	})
+	track := func(i int, fn run.Func) run.Func {
+		return func(ctx context.Context) error {
+			mux.Lock()
+			started = append(started, i)
+			mux.Unlock()
+			current := active.Add(1)
+			defer active.Add(-1)
+			for {
+				max := peak.Load()
+				if current <= max || peak.CompareAndSwap(max, current) {
+					break
+				}
+			}
+			return fn(ctx)
+		}
+	}
+	startedFuncs := func() []int {
+		mux.Lock()
+		defer mux.Unlock()
+		return append([]int{}, started...)
+	}
+	It("never runs more funcs than the limit at once", func() {
+		funcs := make([]run.Func, 10)
+		for i := range funcs {
+			funcs[i] = track(i, func(ctx context.Context) error {
+				<-ctx.Done()
+				return nil
+			})
+		}
+		errCh := make(chan error, 1)
+		go func() {
+			errCh <- service.RunWithConcurrencyLimit(ctx, 3, funcs...)
+		}()
+		Eventually(startedFuncs).Should(HaveLen(3))
+		Consistently(startedFuncs, 50*time.Millisecond).Should(ConsistOf(0, 1, 2))
+		Expect(peak.Load()).To(Equal(int32(3)))
+		cancel()
+		Eventually(errCh).Should(Receive(BeNil()))
+		Expect(startedFuncs()).To(ConsistOf(0, 1, 2))
+	})
+	It("does not start queued funcs after the first finished", func() {
+		funcs := []run.Func{
+			track(0, func(ctx context.Context) error {
+				return stderrors.New("banana")
+			}),
+			track(1, func(ctx context.Context) error {
+				<-ctx.Done()
+				return nil
+			}),
+			track(2, func(ctx context.Context) error {
+				return nil
+			}),
+		}
+		err := service.RunWithConcurrencyLimit(ctx, 1, funcs...)
+		Expect(err).To(MatchError(ContainSubstring("banana")))
+		Expect(startedFuncs()).To(Equal([]int{0}))
+	})
+	It("runs all funcs at once without limit", func() {
+		var wg sync.WaitGroup
+		wg.Add(3)
+		funcs := make([]run.Func, 3)
+		for i := range funcs {
+			funcs[i] = track(i, func(ctx context.Context) error {
+				wg.Done()
+				wg.Wait()
+				return nil
+			})
+		}
+		Expect(service.RunWithConcurrencyLimit(ctx, 0, funcs...)).To(BeNil())
+		Expect(peak.Load()).To(Equal(int32(3)))
+	})
+})
@@ -0,0 +1,30 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package service
+
+import (
+	"context"
+
+	"github.com/bborbe/run"
+)
+
+// RunWithPanicHandler works like Run, but a recovered panic becomes the error returned by the handler.
+// That error is logged and returned like any other error of the funcs.
+// Without handler it behaves like Run and a panic becomes a PanicError.
+func RunWithPanicHandler(ctx context.Context, handler PanicHandler, funcs ...run.Func) error {
+	if handler == nil {
+		return Run(ctx, funcs...)
+	}
+	decorated := make([]run.Func, len(funcs))
+	for i, fn := range funcs {
+		decorated[i] = logErrors(
+			CatchPanicWithHandler(
+				filterCanceled(fn),
+				handler,
+			),
+		)
+	}
+	return run.CancelOnFirstFinishWait(ctx, decorated...)
+}
@@ -0,0 +1,65 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package service_test
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/bborbe/service"
+)
+
+var _ = Describe("RunWithPanicHandler", func() {
+	var ctx context.Context
+	var cancel context.CancelFunc
+	var panicking func(ctx context.Context) error
+	BeforeEach(func() {
+		ctx, cancel = context.WithTimeout(context.Background(), time.Second)
+		panicking = func(ctx context.Context) error {
+			panic("banana")
+		}
+	})
+	AfterEach(func() {
+		cancel()
+	})
+	It("returns the error of the handler", func() {
+		handlerErr := stderrors.New("handled")
+		var recovered interface{}
+		err := service.RunWithPanicHandler(ctx, func(value interface{}) error {
+			recovered = value
+			return fmt.Errorf("panic %v: %w", value, handlerErr)
+		}, panicking)
+		Expect(stderrors.Is(err, handlerErr)).To(BeTrue())
+		Expect(err.Error()).To(ContainSubstring("panic banana"))
+		Expect(recovered).To(Equal("banana"))
+		var panicErr *service.PanicError
+		Expect(stderrors.As(err, &panicErr)).To(BeFalse())
+	})
+	It("cancels the other funcs like a returned error", func() {
+		err := service.RunWithPanicHandler(ctx, service.NewPanicError, panicking, func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		})
+		var panicErr *service.PanicError
+		Expect(stderrors.As(err, &panicErr)).To(BeTrue())
+		Expect(string(panicErr.Stack)).To(ContainSubstring("run-with-panic-handler_test.go"))
+	})
+	It("swallows the panic if the handler returns nil", func() {
+		Expect(service.RunWithPanicHandler(ctx, func(value interface{}) error {
+			return nil
+		}, panicking)).To(BeNil())
+	})
+	It("returns a PanicError without handler", func() {
+		err := service.RunWithPanicHandler(ctx, nil, panicking)
+		var panicErr *service.PanicError
+		Expect(stderrors.As(err, &panicErr)).To(BeTrue())
+		Expect(panicErr.Value).To(Equal("banana"))
+	})
+})
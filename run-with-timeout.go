@@ -0,0 +1,85 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package service
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/bborbe/run"
+)
+
+// ShutdownTimeoutError is returned by RunWithTimeout if the remaining funcs
+// did not return within the timeout after the shutdown began.
+type ShutdownTimeoutError struct {
+	// Timeout that was exceeded.
+	Timeout time.Duration
+	// Cause is the error that began the shutdown. It is nil if a func returned without error.
+	Cause error
+}
+
+func (s *ShutdownTimeoutError) Error() string {
+	if s.Cause == nil {
+		return fmt.Sprintf("shutdown timeout %v exceeded", s.Timeout)
+	}
+	return fmt.Sprintf("shutdown timeout %v exceeded: %v", s.Timeout, s.Cause)
+}
+
+// Unwrap returns the cause, so it is still reported to Sentry and matched by errors.Is.
+func (s *ShutdownTimeoutError) Unwrap() error {
+	return s.Cause
+}
+
+// RunWithTimeout works like Run, but waits at most timeout for the remaining funcs
+// after the first one finished or ctx was canceled. If they do not return in time
+// a ShutdownTimeoutError is returned and the remaining funcs are left behind.
+func RunWithTimeout(ctx context.Context, timeout time.Duration, funcs ...run.Func) error {
+	var once sync.Once
+	var cause error
+	shutdownCh := make(chan struct{})
+	beginShutdown := func(err error) {
+		once.Do(func() {
+			cause = err
+			close(shutdownCh)
+		})
+	}
+
+	wrapped := make([]run.Func, len(funcs))
+	for i, fn := range funcs {
+		fn := CatchPanic(fn)
+		wrapped[i] = func(ctx context.Context) error {
+			err := fn(ctx)
+			beginShutdown(err)
+			return err
+		}
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- Run(ctx, wrapped...)
+	}()
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-shutdownCh:
+	case <-ctx.Done():
+		beginShutdown(context.Cause(ctx))
+	}
+
+	timer := ClockFromContext(ctx).NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case err := <-errCh:
+		return err
+	case <-timer.C():
+		return &ShutdownTimeoutError{
+			Timeout: timeout,
+			Cause:   cause,
+		}
+	}
+}
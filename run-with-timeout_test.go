@@ -0,0 +1,116 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package service_test
+
+import (
+	"context"
+	stderrors "errors"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/bborbe/service"
+)
+
+var _ = Describe("RunWithTimeout", func() {
+	var ctx context.Context
+	var cancel context.CancelFunc
+	var release chan struct{}
+	var realErr error
+	var err error
+	BeforeEach(func() {
+		ctx, cancel = context.WithTimeout(context.Background(), time.Second)
+		release = make(chan struct{})
+		realErr = stderrors.New("banana")
+	})
+	AfterEach(func() {
+		close(release)
+		cancel()
+	})
+	Context("func ignores cancellation", func() {
+		BeforeEach(func() {
+			// leaked funcs must not read the variable reassigned by the next spec
+			released := release
+			err = service.RunWithTimeout(
+				ctx,
+				10*time.Millisecond,
+				func(ctx context.Context) error {
+					return realErr
+				},
+				func(ctx context.Context) error {
+					<-released
+					return nil
+				},
+			)
+		})
+		It("returns a ShutdownTimeoutError", func() {
+			var shutdownTimeoutError *service.ShutdownTimeoutError
+			Expect(stderrors.As(err, &shutdownTimeoutError)).To(BeTrue())
+			Expect(shutdownTimeoutError.Timeout).To(Equal(10 * time.Millisecond))
+		})
+		It("wraps the cause", func() {
+			Expect(stderrors.Is(err, realErr)).To(BeTrue())
+		})
+	})
+	Context("funcs return in time", func() {
+		BeforeEach(func() {
+			err = service.RunWithTimeout(
+				ctx,
+				time.Second,
+				func(ctx context.Context) error {
+					return realErr
+				},
+				func(ctx context.Context) error {
+					<-ctx.Done()
+					return ctx.Err()
+				},
+			)
+		})
+		It("returns the error", func() {
+			Expect(stderrors.Is(err, realErr)).To(BeTrue())
+			var shutdownTimeoutError *service.ShutdownTimeoutError
+			Expect(stderrors.As(err, &shutdownTimeoutError)).To(BeFalse())
+		})
+	})
+	Context("parent context canceled", func() {
+		BeforeEach(func() {
+			// leaked funcs must not read the variable reassigned by the next spec
+			released := release
+			go func() {
+				time.Sleep(10 * time.Millisecond)
+				cancel()
+			}()
+			err = service.RunWithTimeout(
+				ctx,
+				10*time.Millisecond,
+				func(ctx context.Context) error {
+					<-released
+					return nil
+				},
+			)
+		})
+		It("returns a ShutdownTimeoutError with the context cause", func() {
+			var shutdownTimeoutError *service.ShutdownTimeoutError
+			Expect(stderrors.As(err, &shutdownTimeoutError)).To(BeTrue())
+			Expect(stderrors.Is(err, context.Canceled)).To(BeTrue())
+		})
+	})
+	Context("parent context canceled before", func() {
+		BeforeEach(func() {
+			cancel()
+			err = service.RunWithTimeout(
+				ctx,
+				10*time.Millisecond,
+				func(ctx context.Context) error {
+					return realErr
+				},
+			)
+		})
+		It("returns nil without running the funcs", func() {
+			Expect(err).To(BeNil())
+		})
+	})
+})
@@ -7,22 +7,63 @@ package service
 import (
 	"context"
 	"errors"
+	"fmt"
+	"reflect"
 
 	"github.com/bborbe/run"
+	"github.com/bborbe/sentry"
 )
 
+// Run all given funcs concurrently and cancel the remaining ones as soon as the first one finishes.
+// All errors are joined in the order they occurred, so the first meaningful error comes first
+// and errors of later failing funcs, like a panic during cleanup, are not lost.
+// A HealthState of the context is set live once the funcs start and not ready as soon as the shutdown begins.
+// If ctx is already canceled no func is started and nil is returned, like after a clean shutdown.
+// A func returning nil while its context is still alive is reported to the UnexpectedCompletionHandler of the context.
+// Errors and panics are counted if the context carries metrics, see ContextWithFuncMetrics.
+// Errors of DefaultExcludeErrors returned after the context of the func was canceled are filtered.
 func Run(ctx context.Context, funcs ...run.Func) error {
-	for i, fn := range funcs {
-		funcs[i] = run.LogErrors(
-			run.CatchPanic(
-				FilterErrors(
-					fn,
-					context.Canceled,
-				),
-			),
-		)
+	return runFuncs(ctx, true, DefaultExcludeErrors(), funcNames(funcs), funcs)
+}
+
+// RunWithOptions works like Run, but filters the ExcludeErrors of the options instead of DefaultExcludeErrors,
+// so Run drops the same errors during the shutdown that are excluded from Sentry.
+func RunWithOptions(ctx context.Context, options Options, funcs ...run.Func) error {
+	return runFuncs(ctx, true, options.ExcludeErrors, funcNames(funcs), funcs)
+}
+
+func funcNames(funcs []run.Func) []string {
+	names := make([]string, len(funcs))
+	for i := range funcs {
+		names[i] = fmt.Sprintf("func %d", i)
 	}
-	return run.CancelOnFirstFinishWait(ctx, funcs...)
+	return names
+}
+
+// runFuncs implements Run and RunUntilError, the names identify the funcs for the UnexpectedCompletionHandler and metrics.
+// With cancelOnSuccess a func returning nil cancels the others like for Run, otherwise only an error does.
+func runFuncs(ctx context.Context, cancelOnSuccess bool, excludeErrors sentry.ExcludeErrors, names []string, funcs []run.Func) error {
+	if ctx.Err() != nil {
+		if logger := LoggerFromContext(ctx); logger.V(2) {
+			logger.Infof("context already canceled => skip %d funcs", len(funcs))
+		}
+		return nil
+	}
+	funcs = filterExcludedFuncs(excludeErrors, funcs)
+	if metrics := funcMetricsFromContext(ctx); metrics != nil {
+		funcs = metrics.countFailures(names, funcs)
+	}
+	// without cancelOnSuccess a func returning nil is expected and keeps the group running
+	if handler := UnexpectedCompletionHandlerFromContext(ctx); handler != nil && cancelOnSuccess {
+		funcs = reportUnexpectedCompletions(handler, names, funcs)
+	}
+	if healthState := HealthStateFromContext(ctx); healthState != nil {
+		return runWithHealthState(ctx, healthState, cancelOnSuccess, funcs)
+	}
+	if !cancelOnSuccess {
+		return run.CancelOnFirstErrorWait(ctx, decorateFuncs(funcs)...)
+	}
+	return run.CancelOnFirstFinishWait(ctx, decorateFuncs(funcs)...)
 }
 
 // FilterErrors for the given func
@@ -39,3 +80,120 @@ func FilterErrors(fn run.Func, filteredErrors ...error) run.Func {
 		return nil
 	}
 }
+
+// FilterErrorsFunc returns nil instead of an error of fn for which the predicate returns true.
+// A sentry.ExcludeError or the IsExcluded method of sentry.ExcludeErrors can be shared as predicate.
+func FilterErrorsFunc(fn run.Func, predicate func(err error) bool) run.Func {
+	return func(ctx context.Context) error {
+		if err := fn(ctx); err != nil {
+			if predicate(err) {
+				return nil
+			}
+			return err
+		}
+		return nil
+	}
+}
+
+// FilterErrorsByType returns nil instead of an error of fn matching the type of one of the targets with errors.As.
+// Each target is a pointer like for errors.As, e.g. new(*net.OpError), or a nil pointer of the error type,
+// e.g. (*net.OpError)(nil). It panics for other targets. FilterErrors matches values instead.
+func FilterErrorsByType(fn run.Func, targets ...any) run.Func {
+	targetTypes := make([]reflect.Type, len(targets))
+	for i, target := range targets {
+		targetTypes[i] = filterTargetType(target)
+	}
+	return func(ctx context.Context) error {
+		if err := fn(ctx); err != nil {
+			for _, targetType := range targetTypes {
+				// a new target for each call, fn might run concurrently
+				if errors.As(err, reflect.New(targetType).Interface()) {
+					return nil
+				}
+			}
+			return err
+		}
+		return nil
+	}
+}
+
+// filterTargetType returns the type errors.As matches for target.
+func filterTargetType(target any) reflect.Type {
+	if target == nil {
+		panic("service: target must be a non-nil pointer")
+	}
+	targetType := reflect.TypeOf(target)
+	if targetType.Kind() != reflect.Ptr {
+		panic("service: target must be a non-nil pointer")
+	}
+	if elemType := targetType.Elem(); elemType.Kind() == reflect.Interface || elemType.Implements(errorType) {
+		return elemType
+	}
+	if targetType.Implements(errorType) {
+		// a nil typed pointer like (*net.OpError)(nil)
+		return targetType
+	}
+	panic("service: *target must be interface or implement error")
+}
+
+var errorType = reflect.TypeOf((*error)(nil)).Elem()
+
+func decorateFuncs(funcs []run.Func) []run.Func {
+	result := make([]run.Func, len(funcs))
+	for i, fn := range funcs {
+		result[i] = decorateFunc(fn)
+	}
+	return result
+}
+
+func decorateFunc(fn run.Func) run.Func {
+	return logErrors(
+		CatchPanic(
+			filterCanceled(fn),
+		),
+	)
+}
+
+func filterExcludedFuncs(excludeErrors sentry.ExcludeErrors, funcs []run.Func) []run.Func {
+	result := make([]run.Func, len(funcs))
+	for i, fn := range funcs {
+		result[i] = filterExcluded(excludeErrors, fn)
+	}
+	return result
+}
+
+// filterExcluded returns nil if fn returns an excluded error after the context passed to it was canceled.
+// While ctx is alive the error is returned, the func stopped working even if Sentry ignores the error.
+func filterExcluded(excludeErrors sentry.ExcludeErrors, fn run.Func) run.Func {
+	return func(ctx context.Context) error {
+		if err := fn(ctx); err != nil {
+			if ctx.Err() != nil && excludeErrors.IsExcluded(err) {
+				return nil
+			}
+			return err
+		}
+		return nil
+	}
+}
+
+// filterCanceled returns nil if fn returns context.Canceled because the context passed to it was canceled.
+// A context.Canceled of an unrelated inner context while ctx is still alive is a bug and returned.
+func filterCanceled(fn run.Func) run.Func {
+	return func(ctx context.Context) error {
+		if err := fn(ctx); err != nil {
+			if isCanceledBy(ctx, err) {
+				return nil
+			}
+			return err
+		}
+		return nil
+	}
+}
+
+// isCanceledBy reports whether err is the result of canceling ctx.
+func isCanceledBy(ctx context.Context, err error) bool {
+	if !errors.Is(ctx.Err(), context.Canceled) {
+		return false
+	}
+	return errors.Is(err, context.Canceled) || errors.Is(err, context.Cause(ctx))
+}
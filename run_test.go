@@ -0,0 +1,308 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package service_test
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"net"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/bborbe/service"
+)
+
+var _ = Describe("Run", func() {
+	var ctx context.Context
+	var cancel context.CancelFunc
+	var err error
+	BeforeEach(func() {
+		ctx, cancel = context.WithTimeout(context.Background(), time.Second)
+	})
+	AfterEach(func() {
+		cancel()
+	})
+	Context("error followed by panic", func() {
+		var realErr error
+		BeforeEach(func() {
+			realErr = stderrors.New("real error")
+			err = service.Run(
+				ctx,
+				func(ctx context.Context) error {
+					return realErr
+				},
+				func(ctx context.Context) error {
+					<-ctx.Done()
+					panic("cleanup failed")
+				},
+			)
+		})
+		It("returns the first error", func() {
+			Expect(err).NotTo(BeNil())
+			Expect(stderrors.Is(err, realErr)).To(BeTrue())
+		})
+		It("returns the first error first", func() {
+			joined, ok := err.(interface{ Unwrap() []error })
+			Expect(ok).To(BeTrue())
+			Expect(joined.Unwrap()).To(HaveLen(2))
+			Expect(joined.Unwrap()[0]).To(Equal(realErr))
+		})
+		It("attaches the panic", func() {
+			var panicError *service.PanicError
+			Expect(stderrors.As(err, &panicError)).To(BeTrue())
+			Expect(panicError.Value).To(Equal("cleanup failed"))
+			Expect(panicError.Stack).NotTo(BeEmpty())
+		})
+	})
+	Context("error followed by panic in the cleanup of the same func", func() {
+		var realErr error
+		BeforeEach(func() {
+			realErr = stderrors.New("real error")
+			err = service.Run(
+				ctx,
+				func(ctx context.Context) (err error) {
+					defer service.RecoverPanic(ctx, &err)
+					defer func() {
+						panic("cleanup failed")
+					}()
+					return realErr
+				},
+			)
+		})
+		It("returns the error of the func", func() {
+			Expect(err).NotTo(BeNil())
+			Expect(stderrors.Is(err, realErr)).To(BeTrue())
+		})
+		It("attaches the panic", func() {
+			var panicError *service.PanicError
+			Expect(stderrors.As(err, &panicError)).To(BeTrue())
+			Expect(panicError.Value).To(Equal("cleanup failed"))
+			Expect(panicError.Stack).NotTo(BeEmpty())
+		})
+	})
+	Context("panic with error", func() {
+		var panicErr error
+		BeforeEach(func() {
+			panicErr = stderrors.New("banana")
+			err = service.Run(
+				ctx,
+				func(ctx context.Context) error {
+					panic(panicErr)
+				},
+			)
+		})
+		It("keeps the panic error matchable", func() {
+			Expect(stderrors.Is(err, panicErr)).To(BeTrue())
+		})
+	})
+	Context("canceled", func() {
+		BeforeEach(func() {
+			err = service.Run(
+				ctx,
+				func(ctx context.Context) error {
+					return nil
+				},
+				func(ctx context.Context) error {
+					<-ctx.Done()
+					return ctx.Err()
+				},
+			)
+		})
+		It("returns no error", func() {
+			Expect(err).To(BeNil())
+		})
+	})
+	Context("canceled by unrelated context", func() {
+		BeforeEach(func() {
+			err = service.Run(
+				ctx,
+				func(ctx context.Context) error {
+					innerCtx, innerCancel := context.WithCancel(context.Background())
+					innerCancel()
+					return innerCtx.Err()
+				},
+			)
+		})
+		It("returns the error", func() {
+			Expect(err).NotTo(BeNil())
+			Expect(stderrors.Is(err, context.Canceled)).To(BeTrue())
+		})
+	})
+	Context("canceled with cause", func() {
+		var cause error
+		BeforeEach(func() {
+			cause = stderrors.New("shutdown")
+			causeCtx, causeCancel := context.WithCancelCause(ctx)
+			causeCancel(cause)
+			err = service.Run(
+				causeCtx,
+				func(ctx context.Context) error {
+					return context.Cause(ctx)
+				},
+			)
+		})
+		It("returns no error", func() {
+			Expect(err).To(BeNil())
+		})
+	})
+	Context("context canceled before", func() {
+		var started bool
+		BeforeEach(func() {
+			started = false
+			cancel()
+			err = service.Run(
+				ctx,
+				func(ctx context.Context) error {
+					started = true
+					return stderrors.New("banana")
+				},
+			)
+		})
+		It("returns no error", func() {
+			Expect(err).To(BeNil())
+		})
+		It("does not start the funcs", func() {
+			Expect(started).To(BeFalse())
+		})
+	})
+})
+
+type typedError struct {
+	msg string
+}
+
+func (t *typedError) Error() string {
+	return t.msg
+}
+
+var _ = Describe("FilterErrorsByType", func() {
+	var ctx context.Context
+	var err error
+	BeforeEach(func() {
+		ctx = context.Background()
+		err = fmt.Errorf("wrapped: %w", &typedError{msg: "banana"})
+	})
+	fail := func(ctx context.Context) error {
+		return err
+	}
+	It("filters an error matching a pointer target", func() {
+		Expect(service.FilterErrorsByType(fail, new(*typedError))(ctx)).To(BeNil())
+	})
+	It("filters an error matching a nil typed pointer", func() {
+		Expect(service.FilterErrorsByType(fail, (*typedError)(nil))(ctx)).To(BeNil())
+	})
+	It("filters an error matching an interface target", func() {
+		Expect(service.FilterErrorsByType(fail, new(interface{ Timeout() bool }), new(error))(ctx)).To(BeNil())
+	})
+	It("returns an error matching no target", func() {
+		Expect(service.FilterErrorsByType(fail, new(*net.OpError))(ctx)).To(Equal(err))
+	})
+	It("returns nil on success", func() {
+		Expect(service.FilterErrorsByType(func(ctx context.Context) error {
+			return nil
+		}, new(*typedError))(ctx)).To(BeNil())
+	})
+	It("panics for an invalid target", func() {
+		Expect(func() {
+			service.FilterErrorsByType(fail, typedError{})
+		}).To(Panic())
+		Expect(func() {
+			service.FilterErrorsByType(fail, new(string))
+		}).To(Panic())
+		Expect(func() {
+			service.FilterErrorsByType(fail, nil)
+		}).To(Panic())
+	})
+})
+
+type statusError struct {
+	status int
+}
+
+func (s statusError) Error() string {
+	return fmt.Sprintf("status %d", s.status)
+}
+
+var _ = Describe("FilterErrorsFunc", func() {
+	var ctx context.Context
+	var unavailable func(err error) bool
+	BeforeEach(func() {
+		ctx = context.Background()
+		unavailable = func(err error) bool {
+			var statusErr statusError
+			return stderrors.As(err, &statusErr) && statusErr.status == 503
+		}
+	})
+	failWith := func(err error) func(ctx context.Context) error {
+		return func(ctx context.Context) error {
+			return err
+		}
+	}
+	It("filters an error matching the predicate", func() {
+		Expect(service.FilterErrorsFunc(failWith(fmt.Errorf("get failed: %w", statusError{status: 503})), unavailable)(ctx)).To(BeNil())
+	})
+	It("returns an error not matching the predicate", func() {
+		err := statusError{status: 500}
+		Expect(service.FilterErrorsFunc(failWith(err), unavailable)(ctx)).To(Equal(err))
+	})
+	It("returns nil on success without calling the predicate", func() {
+		Expect(service.FilterErrorsFunc(failWith(nil), func(err error) bool {
+			Fail("predicate called")
+			return false
+		})(ctx)).To(BeNil())
+	})
+	It("shares the exclude errors of the options", func() {
+		options := service.NewOptions()
+		Expect(service.FilterErrorsFunc(failWith(context.DeadlineExceeded), options.ExcludeErrors.IsExcluded)(ctx)).To(BeNil())
+		Expect(service.FilterErrorsFunc(failWith(context.DeadlineExceeded), options.ExcludeErrors[1])(ctx)).To(BeNil())
+	})
+})
+
+var _ = Describe("RunWithOptions", func() {
+	var ctx context.Context
+	var cancel context.CancelFunc
+	BeforeEach(func() {
+		ctx, cancel = context.WithCancel(context.Background())
+	})
+	AfterEach(func() {
+		cancel()
+	})
+	deadlineOnShutdown := func(ctx context.Context) error {
+		<-ctx.Done()
+		return fmt.Errorf("flush failed: %w", context.DeadlineExceeded)
+	}
+	stop := func(ctx context.Context) error {
+		cancel()
+		return nil
+	}
+	It("filters a deadline exceeded during the shutdown like Run", func() {
+		Expect(service.Run(ctx, deadlineOnShutdown, stop)).To(BeNil())
+	})
+	It("filters the exclude errors of the options during the shutdown", func() {
+		banana := stderrors.New("banana")
+		options := service.NewOptions(func(options *service.Options) {
+			options.ExcludeErrors = append(options.ExcludeErrors, func(err error) bool {
+				return stderrors.Is(err, banana)
+			})
+		})
+		Expect(service.RunWithOptions(ctx, options, func(ctx context.Context) error {
+			<-ctx.Done()
+			return banana
+		}, stop)).To(BeNil())
+	})
+	It("returns an excluded error while the context is alive", func() {
+		err := service.RunWithOptions(ctx, service.NewOptions(), func(ctx context.Context) error {
+			return context.DeadlineExceeded
+		})
+		Expect(stderrors.Is(err, context.DeadlineExceeded)).To(BeTrue())
+	})
+	It("returns errors that are not excluded", func() {
+		err := service.RunWithOptions(ctx, service.Options{}, deadlineOnShutdown, stop)
+		Expect(stderrors.Is(err, context.DeadlineExceeded)).To(BeTrue())
+	})
+})
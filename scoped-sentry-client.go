@@ -0,0 +1,76 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package service
+
+import (
+	libsentry "github.com/bborbe/sentry"
+	"github.com/getsentry/sentry-go"
+)
+
+//counterfeiter:generate -o mocks/service-scoped-sentry-client.go --fake-name ServiceScopedSentryClient . ScopedSentryClient
+
+// ScopedSentryClient is a Sentry client with a scope applied to every captured event.
+// Main passes one to the application, which can type assert it to add context before an error occurs:
+//
+//	if scoped, ok := sentryClient.(service.ScopedSentryClient); ok {
+//		scoped.AddBreadcrumb(&sentry.Breadcrumb{Message: "order received"})
+//	}
+type ScopedSentryClient interface {
+	libsentry.Client
+	// AddBreadcrumb adds the breadcrumb to all following events, at most DefaultBreadcrumbLimit are kept.
+	AddBreadcrumb(breadcrumb *sentry.Breadcrumb)
+	// ConfigureScope lets f change the scope, e.g. to set the user, for all following events.
+	ConfigureScope(f func(scope *sentry.Scope))
+}
+
+// NewScopedSentryClient returns a ScopedSentryClient with an empty scope. The scope of each capture
+// is applied after it, so its values win.
+func NewScopedSentryClient(client libsentry.Client) ScopedSentryClient {
+	return &scopedSentryClient{
+		Client: client,
+		scope:  sentry.NewScope(),
+	}
+}
+
+type scopedSentryClient struct {
+	libsentry.Client
+	scope *sentry.Scope
+}
+
+func (s *scopedSentryClient) AddBreadcrumb(breadcrumb *sentry.Breadcrumb) {
+	s.scope.AddBreadcrumb(breadcrumb, DefaultBreadcrumbLimit)
+}
+
+func (s *scopedSentryClient) ConfigureScope(f func(scope *sentry.Scope)) {
+	f(s.scope)
+}
+
+func (s *scopedSentryClient) CaptureMessage(message string, hint *sentry.EventHint, scope sentry.EventModifier) *sentry.EventID {
+	return s.Client.CaptureMessage(message, hint, s.eventModifier(scope))
+}
+
+func (s *scopedSentryClient) CaptureException(err error, hint *sentry.EventHint, scope sentry.EventModifier) *sentry.EventID {
+	return s.Client.CaptureException(err, hint, s.eventModifier(scope))
+}
+
+func (s *scopedSentryClient) eventModifier(scope sentry.EventModifier) sentry.EventModifier {
+	if scope == nil {
+		return s.scope
+	}
+	return eventModifiers{s.scope, scope}
+}
+
+// eventModifiers applies all modifiers in order until one drops the event.
+type eventModifiers []sentry.EventModifier
+
+func (e eventModifiers) ApplyToEvent(event *sentry.Event, hint *sentry.EventHint, client *sentry.Client) *sentry.Event {
+	for _, modifier := range e {
+		event = modifier.ApplyToEvent(event, hint, client)
+		if event == nil {
+			return nil
+		}
+	}
+	return event
+}
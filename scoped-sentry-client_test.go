@@ -0,0 +1,81 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package service_test
+
+import (
+	"context"
+	stderrors "errors"
+
+	libsentry "github.com/bborbe/sentry"
+	"github.com/getsentry/sentry-go"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/bborbe/service"
+	"github.com/bborbe/service/mocks"
+	"github.com/bborbe/service/servicetest"
+)
+
+var _ = Describe("ScopedSentryClient", func() {
+	var sentryClient *mocks.SentryClient
+	var client service.ScopedSentryClient
+	var applied func() *sentry.Event
+	BeforeEach(func() {
+		sentryClient = &mocks.SentryClient{}
+		client = service.NewScopedSentryClient(sentryClient)
+		applied = func() *sentry.Event {
+			_, _, modifier := sentryClient.CaptureExceptionArgsForCall(0)
+			return modifier.ApplyToEvent(sentry.NewEvent(), nil, nil)
+		}
+	})
+	It("adds breadcrumbs to captured exceptions", func() {
+		client.AddBreadcrumb(&sentry.Breadcrumb{Message: "order received"})
+		client.CaptureException(stderrors.New("banana"), &sentry.EventHint{}, nil)
+		Expect(applied().Breadcrumbs).To(HaveLen(1))
+		Expect(applied().Breadcrumbs[0].Message).To(Equal("order received"))
+	})
+	It("adds the configured scope to captured exceptions", func() {
+		client.ConfigureScope(func(scope *sentry.Scope) {
+			scope.SetUser(sentry.User{ID: "42"})
+			scope.SetTag("region", "eu")
+		})
+		scope := sentry.NewScope()
+		scope.SetTag("region", "us")
+		client.CaptureException(stderrors.New("banana"), &sentry.EventHint{}, scope)
+		event := applied()
+		Expect(event.User.ID).To(Equal("42"))
+		Expect(event.Tags).To(HaveKeyWithValue("region", "us"))
+	})
+	It("adds the configured scope to captured messages", func() {
+		client.ConfigureScope(func(scope *sentry.Scope) {
+			scope.SetTag("region", "eu")
+		})
+		client.CaptureMessage("banana", &sentry.EventHint{}, sentry.NewScope())
+		_, _, modifier := sentryClient.CaptureMessageArgsForCall(0)
+		Expect(modifier.ApplyToEvent(sentry.NewEvent(), nil, nil).Tags).To(HaveKeyWithValue("region", "eu"))
+	})
+})
+
+type scopedApplication struct {
+	SentryDSN string
+	Scoped    bool `json:"-"`
+}
+
+func (s *scopedApplication) Run(ctx context.Context, sentryClient libsentry.Client) error {
+	_, s.Scoped = sentryClient.(service.ScopedSentryClient)
+	return nil
+}
+
+var _ = Describe("Main ScopedSentryClient", func() {
+	It("passes a ScopedSentryClient to the application", func() {
+		app := &scopedApplication{}
+		Expect(service.Main(context.Background(), app, &app.SentryDSN, nil,
+			service.WithoutTimezoneOverride(),
+			service.WithRegisterer(nil),
+			service.WithSentryTransport(servicetest.NewSentryTransport()),
+		)).To(Equal(service.ExitCodeSuccess))
+		Expect(app.Scoped).To(BeTrue())
+	})
+})
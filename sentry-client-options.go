@@ -0,0 +1,50 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package service
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/bborbe/errors"
+	"github.com/getsentry/sentry-go"
+)
+
+// BeforeSend can modify a Sentry event before it is sent, returning nil drops it.
+// The Sentry client of Main tolerates dropped events, a client of libsentry.NewClient panics for them.
+type BeforeSend func(event *sentry.Event, hint *sentry.EventHint) *sentry.Event
+
+// SentryTransport delivers the events of the Sentry client.
+type SentryTransport = sentry.Transport
+
+// NewSentryClientOptions builds the options Main uses to create the Sentry client.
+func NewSentryClientOptions(
+	ctx context.Context,
+	sentryDSN string,
+	httpTransport http.RoundTripper,
+	options Options,
+) (*sentry.ClientOptions, error) {
+	if options.TracesSampleRate < 0 || options.TracesSampleRate > 1 {
+		return nil, errors.Errorf(ctx, "tracesSampleRate %v is not in range [0.0,1.0]", options.TracesSampleRate)
+	}
+	clientOptions := &sentry.ClientOptions{
+		Dsn:              sentryDSN,
+		TracesSampleRate: options.TracesSampleRate,
+		HTTPTransport:    httpTransport,
+	}
+	if options.SentryEnvironment != "" {
+		clientOptions.Environment = options.SentryEnvironment
+	}
+	if options.SentryRelease != "" {
+		clientOptions.Release = options.SentryRelease
+	}
+	if options.BeforeSend != nil {
+		clientOptions.BeforeSend = options.BeforeSend
+	}
+	if options.SentryTransport != nil {
+		clientOptions.Transport = options.SentryTransport
+	}
+	return clientOptions, nil
+}
@@ -0,0 +1,168 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package service_test
+
+import (
+	"context"
+	stderrors "errors"
+	"net/http"
+
+	libsentry "github.com/bborbe/sentry"
+	"github.com/getsentry/sentry-go"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/bborbe/service"
+	"github.com/bborbe/service/servicetest"
+)
+
+type beforeSendApplication struct {
+	SentryDSN string
+	Message   string `json:"-"`
+	Exception error  `json:"-"`
+	Captured  bool   `json:"-"`
+}
+
+func (b *beforeSendApplication) Run(ctx context.Context, sentryClient libsentry.Client) error {
+	if b.Message != "" {
+		sentryClient.CaptureMessage(b.Message, nil, nil)
+	}
+	if b.Exception != nil {
+		sentryClient.CaptureException(b.Exception, nil, nil)
+	}
+	b.Captured = true
+	return nil
+}
+
+var _ = Describe("NewSentryClientOptions", func() {
+	var ctx context.Context
+	var fns []service.OptionsFn
+	var clientOptions *sentry.ClientOptions
+	var err error
+	BeforeEach(func() {
+		ctx = context.Background()
+		fns = nil
+	})
+	JustBeforeEach(func() {
+		clientOptions, err = service.NewSentryClientOptions(ctx, "https://key@sentry.example.com/1", http.DefaultTransport, service.NewOptions(fns...))
+	})
+	It("sets dsn and transport", func() {
+		Expect(err).To(BeNil())
+		Expect(clientOptions.Dsn).To(Equal("https://key@sentry.example.com/1"))
+		Expect(clientOptions.HTTPTransport).To(Equal(http.DefaultTransport))
+	})
+	It("uses traces sample rate 1.0 by default", func() {
+		Expect(err).To(BeNil())
+		Expect(clientOptions.TracesSampleRate).To(Equal(1.0))
+	})
+	Context("WithTracesSampleRate", func() {
+		BeforeEach(func() {
+			fns = append(fns, service.WithTracesSampleRate(0.25))
+		})
+		It("sets the traces sample rate", func() {
+			Expect(err).To(BeNil())
+			Expect(clientOptions.TracesSampleRate).To(Equal(0.25))
+		})
+	})
+	It("leaves the environment unset by default", func() {
+		Expect(err).To(BeNil())
+		Expect(clientOptions.Environment).To(Equal(""))
+	})
+	Context("WithSentryEnvironment", func() {
+		BeforeEach(func() {
+			fns = append(fns, service.WithSentryEnvironment("staging"))
+		})
+		It("sets the environment", func() {
+			Expect(err).To(BeNil())
+			Expect(clientOptions.Environment).To(Equal("staging"))
+		})
+	})
+	Context("WithSentryRelease", func() {
+		BeforeEach(func() {
+			fns = append(fns, service.WithSentryRelease("v1.2.3"))
+		})
+		It("sets the release", func() {
+			Expect(err).To(BeNil())
+			Expect(clientOptions.Release).To(Equal("v1.2.3"))
+		})
+	})
+	Context("WithSentryReleaseFromBuild", func() {
+		BeforeEach(func() {
+			service.BuildVersion = "v4.5.6"
+			fns = append(fns, service.WithSentryReleaseFromBuild())
+		})
+		AfterEach(func() {
+			service.BuildVersion = ""
+		})
+		It("sets the release to the injected build version", func() {
+			Expect(err).To(BeNil())
+			Expect(clientOptions.Release).To(Equal("v4.5.6"))
+		})
+	})
+	It("leaves before send unset by default", func() {
+		Expect(err).To(BeNil())
+		Expect(clientOptions.BeforeSend).To(BeNil())
+	})
+	Context("WithBeforeSend", func() {
+		var transport servicetest.SentryTransport
+		var app *beforeSendApplication
+		var exitCode int
+		BeforeEach(func() {
+			transport = servicetest.NewSentryTransport()
+			app = &beforeSendApplication{
+				SentryDSN: "https://key@sentry.example.com/1",
+			}
+		})
+		JustBeforeEach(func() {
+			exitCode = service.MainWithArgs(ctx, app, &app.SentryDSN, nil, nil,
+				service.WithoutTimezoneOverride(),
+				service.WithRegisterer(nil),
+				service.WithSentryTransport(transport),
+				service.WithBeforeSend(func(event *sentry.Event, hint *sentry.EventHint) *sentry.Event {
+					if event.Message == "drop" || len(event.Exception) > 0 && event.Exception[0].Value == "drop" {
+						return nil
+					}
+					event.Message = "[redacted]"
+					return event
+				}),
+			)
+		})
+		Context("message", func() {
+			BeforeEach(func() {
+				app.Message = "password=secret"
+			})
+			It("scrubs the event", func() {
+				Expect(exitCode).To(Equal(service.ExitCodeSuccess))
+				Expect(transport.Events()).To(ContainElement(HaveField("Message", "[redacted]")))
+			})
+		})
+		Context("dropped message", func() {
+			BeforeEach(func() {
+				app.Message = "drop"
+			})
+			It("exits normally", func() {
+				Expect(exitCode).To(Equal(service.ExitCodeSuccess))
+				Expect(transport.Events()).NotTo(ContainElement(HaveField("Message", "drop")))
+			})
+		})
+		Context("dropped exception", func() {
+			BeforeEach(func() {
+				app.Exception = stderrors.New("drop")
+			})
+			It("exits normally", func() {
+				Expect(exitCode).To(Equal(service.ExitCodeSuccess))
+				Expect(app.Captured).To(BeTrue())
+			})
+		})
+	})
+	DescribeTable("rejects traces sample rate out of range",
+		func(rate float64) {
+			_, err := service.NewSentryClientOptions(ctx, "", nil, service.NewOptions(service.WithTracesSampleRate(rate)))
+			Expect(err).NotTo(BeNil())
+		},
+		Entry("negative", -0.1),
+		Entry("above one", 1.1),
+	)
+})
@@ -0,0 +1,142 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/bborbe/errors"
+	libsentry "github.com/bborbe/sentry"
+	"github.com/getsentry/sentry-go"
+)
+
+// newSentryClient creates a Sentry client like libsentry.NewClient, but tolerates events dropped
+// by BeforeSend, an event processor or sampling. sentry-go returns no event ID for them,
+// which the client of libsentry dereferences.
+func newSentryClient(
+	ctx context.Context,
+	clientOptions sentry.ClientOptions,
+	logger Logger,
+	excludeErrors ...libsentry.ExcludeError,
+) (libsentry.Client, error) {
+	client, err := sentry.NewClient(clientOptions)
+	if err != nil {
+		return nil, errors.Wrapf(ctx, err, "create sentry client failed")
+	}
+	client.AddEventProcessor(addHintTags)
+	return &sentryClient{
+		client:        client,
+		excludeErrors: excludeErrors,
+		logger:        logger,
+	}, nil
+}
+
+// addHintTags tags the event with the data of the context, the error and the hint like libsentry.NewClient.
+func addHintTags(event *sentry.Event, hint *sentry.EventHint) *sentry.Event {
+	if hint == nil {
+		return event
+	}
+	if event.Tags == nil {
+		event.Tags = map[string]string{}
+	}
+	if hint.Context != nil {
+		for k, v := range errors.DataFromContext(hint.Context) {
+			event.Tags[k] = v
+		}
+	}
+	if hint.OriginalException != nil {
+		for k, v := range errors.DataFromError(hint.OriginalException) {
+			event.Tags[k] = v
+		}
+	}
+	switch data := hint.Data.(type) {
+	case map[string]interface{}:
+		for k, v := range data {
+			if v == nil {
+				continue
+			}
+			event.Tags[k] = fmt.Sprintf("%v", v)
+		}
+	case map[string]string:
+		for k, v := range data {
+			event.Tags[k] = v
+		}
+	}
+	return event
+}
+
+type sentryClient struct {
+	client        *sentry.Client
+	excludeErrors libsentry.ExcludeErrors
+	logger        Logger
+}
+
+func (s *sentryClient) CaptureMessage(message string, hint *sentry.EventHint, scope sentry.EventModifier) *sentry.EventID {
+	eventID := s.client.CaptureMessage(message, hint, scope)
+	if eventID == nil {
+		if s.logger.V(2) {
+			s.logger.Infof("sentry message dropped: %s", message)
+		}
+		return nil
+	}
+	if s.logger.V(2) {
+		s.logger.Infof("capture sentry message with id %s: %s", *eventID, message)
+	}
+	return eventID
+}
+
+func (s *sentryClient) CaptureException(err error, hint *sentry.EventHint, scope sentry.EventModifier) *sentry.EventID {
+	if s.excludeErrors.IsExcluded(err) {
+		if s.logger.V(4) {
+			s.logger.Infof("capture error %v is excluded => skip", err)
+		}
+		return nil
+	}
+	if scope == nil {
+		scope = sentry.NewScope()
+	}
+	if hint == nil {
+		hint = &sentry.EventHint{}
+	}
+	if hint.OriginalException == nil {
+		hint.OriginalException = err
+	}
+	eventID := s.client.CaptureException(err, hint, scope)
+	if eventID == nil {
+		if s.logger.V(2) {
+			s.logger.Infof("sentry exception dropped: %v", err)
+		}
+		return nil
+	}
+	if s.logger.V(2) {
+		s.logger.Infof("capture sentry exception with id %s: %v", *eventID, err)
+	}
+	return eventID
+}
+
+func (s *sentryClient) Flush(timeout time.Duration) bool {
+	return s.client.Flush(timeout)
+}
+
+func (s *sentryClient) Close() error {
+	s.client.Flush(2 * time.Second)
+	return nil
+}
+
+type sentryClientContextKey struct{}
+
+// ContextWithSentryClient returns a context that lets Optional capture failures with the given client.
+// Main, MainBasic and MainCmd set the client they pass to the application.
+func ContextWithSentryClient(ctx context.Context, sentryClient libsentry.Client) context.Context {
+	return context.WithValue(ctx, sentryClientContextKey{}, sentryClient)
+}
+
+// SentryClientFromContext returns the Sentry client of the context or nil.
+func SentryClientFromContext(ctx context.Context) libsentry.Client {
+	sentryClient, _ := ctx.Value(sentryClientContextKey{}).(libsentry.Client)
+	return sentryClient
+}
@@ -0,0 +1,46 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package service
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/bborbe/errors"
+)
+
+// DefaultSentryConnectivityTimeout bounds the connectivity check of WithSentryConnectivityCheck.
+const DefaultSentryConnectivityTimeout = 5 * time.Second
+
+// checkSentryConnectivity sends a request to the host of the DSN. Any HTTP response counts as reachable,
+// so no event is created in Sentry. An empty DSN disables Sentry and is not checked.
+func checkSentryConnectivity(ctx context.Context, sentryDSN string, transport http.RoundTripper, timeout time.Duration) error {
+	if sentryDSN == "" {
+		return nil
+	}
+	dsn, err := url.Parse(sentryDSN)
+	if err != nil {
+		return errors.Wrapf(ctx, err, "parse sentry dsn failed")
+	}
+	if dsn.Scheme == "" || dsn.Host == "" {
+		return errors.Errorf(ctx, "sentry dsn has no scheme or host")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	target := &url.URL{Scheme: dsn.Scheme, Host: dsn.Host, Path: "/"}
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, target.String(), nil)
+	if err != nil {
+		return errors.Wrapf(ctx, err, "build request failed")
+	}
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		return errors.Wrapf(ctx, err, "reach sentry host %s failed", dsn.Host)
+	}
+	_ = resp.Body.Close()
+	return nil
+}
@@ -0,0 +1,58 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package service_test
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/bborbe/service"
+)
+
+var _ = Describe("CheckSentryConnectivity", func() {
+	var ctx context.Context
+	var unreachableDSN string
+	BeforeEach(func() {
+		ctx = context.Background()
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		Expect(err).To(BeNil())
+		unreachableDSN = "http://key@" + listener.Addr().String() + "/1"
+		Expect(listener.Close()).To(BeNil())
+	})
+	It("succeeds if the host responds", func() {
+		server := httptest.NewServer(http.NotFoundHandler())
+		defer server.Close()
+		dsn := strings.Replace(server.URL, "http://", "http://key@", 1) + "/1"
+		Expect(service.CheckSentryConnectivity(ctx, dsn, http.DefaultTransport, time.Second)).To(Succeed())
+	})
+	It("fails if the host is unreachable", func() {
+		Expect(service.CheckSentryConnectivity(ctx, unreachableDSN, http.DefaultTransport, time.Second)).NotTo(Succeed())
+	})
+	It("skips an empty dsn", func() {
+		Expect(service.CheckSentryConnectivity(ctx, "", http.DefaultTransport, time.Second)).To(Succeed())
+	})
+	It("fails the startup of Main with an unreachable dsn", func() {
+		started := false
+		app := &mainApplication{
+			RunFunc: func(ctx context.Context) error {
+				started = true
+				return nil
+			},
+		}
+		Expect(service.Main(ctx, app, &unreachableDSN, nil,
+			service.WithoutTimezoneOverride(),
+			service.WithRegisterer(nil),
+			service.WithSentryConnectivityCheck(),
+		)).To(Equal(service.ExitCodeSentrySetup))
+		Expect(started).To(BeFalse())
+	})
+})
@@ -0,0 +1,129 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package service
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	libsentry "github.com/bborbe/sentry"
+	"github.com/getsentry/sentry-go"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+//counterfeiter:generate -o mocks/sentry-client.go --fake-name SentryClient github.com/bborbe/sentry.Client
+
+// DefaultSentryFailureThreshold is the number of consecutive rejected Sentry requests
+// after which capturing is disabled.
+const DefaultSentryFailureThreshold = 10
+
+func newSentryFailureDetector(
+	roundTripper http.RoundTripper,
+	threshold int,
+	registerer prometheus.Registerer,
+	logger Logger,
+) *sentryFailureDetector {
+	return &sentryFailureDetector{
+		roundTripper: roundTripper,
+		threshold:    threshold,
+		logger:       logger,
+		disabledGauge: register(registerer, prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: metricsNamespace,
+			Subsystem: "sentry",
+			Name:      "disabled",
+			Help:      "1 if capturing to Sentry got disabled after persistent failures.",
+		})),
+	}
+}
+
+// sentryFailureDetector counts consecutive requests rejected by Sentry with a 4xx status,
+// which happens if the DSN is valid but the project does not exist anymore.
+// Rate limiting (429) is not counted, Sentry recovers from it on its own.
+// After threshold consecutive failures it disables capturing for the rest of the process.
+type sentryFailureDetector struct {
+	roundTripper  http.RoundTripper
+	threshold     int
+	disabledGauge prometheus.Gauge
+	logger        Logger
+
+	mux      sync.Mutex
+	failures int
+	disabled bool
+}
+
+func (s *sentryFailureDetector) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := s.roundTripper.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	s.record(resp.StatusCode)
+	return resp, nil
+}
+
+// Disabled reports whether capturing got disabled.
+func (s *sentryFailureDetector) Disabled() bool {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	return s.disabled
+}
+
+func (s *sentryFailureDetector) record(statusCode int) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	if statusCode < 400 || statusCode >= 500 || statusCode == http.StatusTooManyRequests {
+		s.failures = 0
+		return
+	}
+	s.failures++
+	if s.disabled || s.threshold <= 0 || s.failures < s.threshold {
+		return
+	}
+	s.disabled = true
+	s.disabledGauge.Set(1)
+	s.logger.Warningf("sentry rejected %d requests in a row (last status %d) => disable capturing", s.failures, statusCode)
+}
+
+// newDegradingSentryClient returns a client that drops all captures once the detector disabled capturing.
+func newDegradingSentryClient(client libsentry.Client, detector *sentryFailureDetector, logger Logger) libsentry.Client {
+	return &degradingSentryClient{
+		Client:   client,
+		detector: detector,
+		logger:   logger,
+	}
+}
+
+type degradingSentryClient struct {
+	libsentry.Client
+	detector *sentryFailureDetector
+	logger   Logger
+}
+
+func (d *degradingSentryClient) CaptureMessage(message string, hint *sentry.EventHint, scope sentry.EventModifier) *sentry.EventID {
+	if d.detector.Disabled() {
+		if d.logger.V(4) {
+			d.logger.Infof("sentry disabled => skip capture message: %s", message)
+		}
+		return nil
+	}
+	return d.Client.CaptureMessage(message, hint, scope)
+}
+
+func (d *degradingSentryClient) CaptureException(err error, hint *sentry.EventHint, scope sentry.EventModifier) *sentry.EventID {
+	if d.detector.Disabled() {
+		if d.logger.V(4) {
+			d.logger.Infof("sentry disabled => skip capture exception: %v", err)
+		}
+		return nil
+	}
+	return d.Client.CaptureException(err, hint, scope)
+}
+
+func (d *degradingSentryClient) Flush(timeout time.Duration) bool {
+	if d.detector.Disabled() {
+		return true
+	}
+	return d.Client.Flush(timeout)
+}
@@ -0,0 +1,89 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package service_test
+
+import (
+	stderrors "errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/bborbe/service"
+	"github.com/bborbe/service/mocks"
+)
+
+var _ = Describe("SentryFailureDetector", func() {
+	var statusCode int
+	var registry *prometheus.Registry
+	var roundTripper http.RoundTripper
+	var detector interface {
+		http.RoundTripper
+		Disabled() bool
+	}
+	BeforeEach(func() {
+		statusCode = http.StatusForbidden
+		registry = prometheus.NewRegistry()
+		roundTripper = roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			return &http.Response{
+				StatusCode: statusCode,
+				Body:       io.NopCloser(strings.NewReader("")),
+			}, nil
+		})
+		detector = service.NewSentryFailureDetector(roundTripper, 3, registry, service.NewGlogLogger())
+	})
+	send := func(rt http.RoundTripper, times int) {
+		for i := 0; i < times; i++ {
+			resp, err := rt.RoundTrip(httptest.NewRequest(http.MethodPost, "https://sentry.example.com/api/1/envelope/", nil))
+			Expect(err).To(BeNil())
+			Expect(resp.Body.Close()).To(BeNil())
+		}
+	}
+	It("stays enabled below the threshold", func() {
+		send(detector, 2)
+		Expect(detector.Disabled()).To(BeFalse())
+	})
+	It("disables capturing after persistent failures", func() {
+		send(detector, 3)
+		Expect(detector.Disabled()).To(BeTrue())
+		Expect(metricValue(registry, "service_sentry_disabled")).To(Equal(1.0))
+	})
+	It("resets the failures on success", func() {
+		send(detector, 2)
+		statusCode = http.StatusOK
+		send(detector, 1)
+		statusCode = http.StatusForbidden
+		send(detector, 2)
+		Expect(detector.Disabled()).To(BeFalse())
+	})
+	It("does not count rate limiting", func() {
+		statusCode = http.StatusTooManyRequests
+		send(detector, 5)
+		Expect(detector.Disabled()).To(BeFalse())
+	})
+	It("never disables with threshold zero", func() {
+		detector = service.NewSentryFailureDetector(roundTripper, 0, nil, service.NewGlogLogger())
+		send(detector, 20)
+		Expect(detector.Disabled()).To(BeFalse())
+	})
+	It("drops captures after capturing got disabled", func() {
+		sentryClient := &mocks.SentryClient{}
+		detector := service.NewSentryFailureDetector(roundTripper, 3, nil, service.NewGlogLogger())
+		client := service.NewDegradingSentryClient(sentryClient, detector, service.NewGlogLogger())
+
+		client.CaptureException(stderrors.New("banana"), nil, nil)
+		Expect(sentryClient.CaptureExceptionCallCount()).To(Equal(1))
+
+		send(detector, 3)
+		client.CaptureException(stderrors.New("banana"), nil, nil)
+		client.CaptureMessage("banana", nil, nil)
+		Expect(sentryClient.CaptureExceptionCallCount()).To(Equal(1))
+		Expect(sentryClient.CaptureMessageCallCount()).To(Equal(0))
+	})
+})
@@ -0,0 +1,38 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package service
+
+import (
+	"context"
+	"time"
+
+	libsentry "github.com/bborbe/sentry"
+)
+
+// startPeriodicFlush flushes the Sentry client every interval until the returned stop is called.
+// Stop waits until a running flush returned.
+func startPeriodicFlush(ctx context.Context, sentryClient libsentry.Client, interval time.Duration) func() {
+	ctx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		ticker := ClockFromContext(ctx).NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C():
+				if !sentryClient.Flush(sentryFlushTimeout) {
+					LoggerFromContext(ctx).Warningf("periodic flush sentry failed")
+				}
+			}
+		}
+	}()
+	return func() {
+		cancel()
+		<-done
+	}
+}
@@ -0,0 +1,41 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package service_test
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/bborbe/service"
+	"github.com/bborbe/service/mocks"
+)
+
+var _ = Describe("StartPeriodicFlush", func() {
+	var ctx context.Context
+	var cancel context.CancelFunc
+	var sentryClient *mocks.SentryClient
+	var stop func()
+	BeforeEach(func() {
+		ctx, cancel = context.WithCancel(context.Background())
+		sentryClient = &mocks.SentryClient{}
+		sentryClient.FlushReturns(true)
+		stop = service.StartPeriodicFlush(ctx, sentryClient, time.Millisecond)
+	})
+	AfterEach(func() {
+		stop()
+		cancel()
+	})
+	It("flushes periodically", func() {
+		Eventually(sentryClient.FlushCallCount).Should(BeNumerically(">=", 2))
+	})
+	It("stops flushing after stop", func() {
+		stop()
+		count := sentryClient.FlushCallCount()
+		Consistently(sentryClient.FlushCallCount, 20*time.Millisecond).Should(Equal(count))
+	})
+})
@@ -6,11 +6,11 @@ package service
 
 import (
 	"context"
+	stderrors "errors"
 
 	"github.com/bborbe/errors"
 	libsentry "github.com/bborbe/sentry"
 	"github.com/getsentry/sentry-go"
-	"github.com/golang/glog"
 )
 
 //counterfeiter:generate -o mocks/service.go --fake-name Service . Service
@@ -18,33 +18,162 @@ type Service interface {
 	Run(ctx context.Context) error
 }
 
+// NewService returns a Service running the app with the options of the given fns, see NewServiceWithOptions.
 func NewService(
 	sentryClient libsentry.Client,
 	app Application,
+	fns ...OptionsFn,
+) Service {
+	return NewServiceWithOptions(sentryClient, app, NewOptions(fns...))
+}
+
+// NewServiceWithOptions returns a Service running the app, which captures errors of the app to the sentryClient
+// unless they are excluded by the ExcludeErrors of the options. The options are built by NewOptions,
+// Main passes its own.
+func NewServiceWithOptions(
+	sentryClient libsentry.Client,
+	app Application,
+	options Options,
 ) Service {
 	return &service{
 		app:          app,
 		sentryClient: sentryClient,
+		options:      options,
 	}
 }
 
 type service struct {
 	sentryClient libsentry.Client
 	app          Application
+	options      Options
 }
 
 func (s *service) Run(ctx context.Context) error {
-	if err := s.app.Run(ctx, s.sentryClient); err != nil {
-		s.sentryClient.CaptureException(
-			err,
-			&sentry.EventHint{
-				Context:           ctx,
-				OriginalException: err,
-			},
-			sentry.NewScope(),
-		)
-		return errors.Wrapf(ctx, err, "application failed")
-	}
-	glog.V(4).Infof("run finished without error")
+	if err := s.runApp(ctx); err != nil {
+		if s.options.ErrorCallback != nil {
+			s.options.ErrorCallback(ctx, err)
+		}
+		if s.options.ExcludeErrors.IsExcluded(err) {
+			// e.g. context.Canceled of a clean shutdown, independent of the given client
+			s.options.Logger.Infof("error excluded from sentry: err=%v", err)
+			return errors.Wrapf(ctx, &applicationError{err: err}, "application failed")
+		}
+		eventID := s.captureException(ctx, err)
+		if eventID != nil {
+			s.options.Logger.Infof("captured error to sentry: event=%s err=%v", *eventID, err)
+		} else {
+			s.options.Logger.Infof("error not captured to sentry: err=%v", err)
+		}
+		return errors.Wrapf(ctx, &applicationError{err: err}, "application failed")
+	}
+	if s.options.Logger.V(4) {
+		s.options.Logger.Infof("run finished without error")
+	}
 	return nil
 }
+
+// runApp returns a panic of the application as PanicError.
+// With RePanic the panic is captured and continues. Without PanicRecoveryFromContext it is not recovered.
+func (s *service) runApp(ctx context.Context) (err error) {
+	if !PanicRecoveryFromContext(ctx) {
+		return s.app.Run(ctx, s.sentryClient)
+	}
+	defer func() {
+		if value := recover(); value != nil {
+			err = NewPanicError(value)
+			if s.options.RePanic {
+				if s.options.ErrorCallback != nil {
+					s.options.ErrorCallback(ctx, err)
+				}
+				s.captureException(ctx, err)
+				panic(value)
+			}
+		}
+	}()
+	return s.app.Run(ctx, s.sentryClient)
+}
+
+func (s *service) captureException(ctx context.Context, err error) *sentry.EventID {
+	return s.sentryClient.CaptureException(
+		err,
+		&sentry.EventHint{
+			Context:           ctx,
+			OriginalException: err,
+		},
+		s.newScope(ctx, err),
+	)
+}
+
+func (s *service) newScope(ctx context.Context, err error) *sentry.Scope {
+	scope := sentry.NewScope()
+	if s.options.ServiceName != "" {
+		scope.SetExtra("service", s.options.ServiceName)
+	}
+	for key, value := range errors.DataFromContext(ctx) {
+		scope.SetExtra(key, value)
+	}
+	if traceID := TraceIDFromContext(ctx); traceID != "" {
+		scope.SetTag(TraceIDKey, traceID)
+	}
+	if data := joinedErrorData(err); len(data) > 0 {
+		scope.SetTags(data)
+	}
+	var panicErr *PanicError
+	if stderrors.As(err, &panicErr) {
+		scope.SetExtra("stack", string(panicErr.Stack))
+	}
+	if s.options.BreadcrumbRecorder != nil {
+		breadcrumbs := s.options.BreadcrumbRecorder.Breadcrumbs()
+		for _, breadcrumb := range breadcrumbs {
+			scope.AddBreadcrumb(breadcrumb, len(breadcrumbs))
+		}
+	}
+	if len(s.options.SentryTags) > 0 {
+		scope.AddEventProcessor(mergeTags(s.options.SentryTags))
+	}
+	return scope
+}
+
+// mergeTags adds the given tags to the event, tags already set on the event win.
+func mergeTags(tags map[string]string) sentry.EventProcessor {
+	return func(event *sentry.Event, hint *sentry.EventHint) *sentry.Event {
+		if event.Tags == nil {
+			event.Tags = make(map[string]string, len(tags))
+		}
+		for key, value := range tags {
+			if _, ok := event.Tags[key]; !ok {
+				event.Tags[key] = value
+			}
+		}
+		return event
+	}
+}
+
+// joinedErrorData collects the data of err and all errors wrapped or joined by it,
+// like the func name of RunNamed. The data of an outer error wins.
+func joinedErrorData(err error) map[string]string {
+	data := make(map[string]string)
+	var walk func(err error)
+	walk = func(err error) {
+		if err == nil {
+			return
+		}
+		if hasData, ok := err.(errors.HasData); ok {
+			for key, value := range hasData.Data() {
+				if _, exists := data[key]; !exists {
+					data[key] = value
+				}
+			}
+		}
+		switch e := err.(type) {
+		case interface{ Unwrap() []error }:
+			for _, err := range e.Unwrap() {
+				walk(err)
+			}
+		case interface{ Unwrap() error }:
+			walk(e.Unwrap())
+		}
+	}
+	walk(err)
+	return data
+}
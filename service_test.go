@@ -0,0 +1,290 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package service_test
+
+import (
+	"context"
+	stderrors "errors"
+
+	"github.com/bborbe/errors"
+	libsentry "github.com/bborbe/sentry"
+	"github.com/getsentry/sentry-go"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/bborbe/service"
+	"github.com/bborbe/service/mocks"
+)
+
+var _ = Describe("Service", func() {
+	var ctx context.Context
+	var sentryClient *mocks.SentryClient
+	var app *mocks.ServiceApplication
+	var fns []service.OptionsFn
+	var err error
+	BeforeEach(func() {
+		ctx = context.Background()
+		sentryClient = &mocks.SentryClient{}
+		app = &mocks.ServiceApplication{}
+		fns = nil
+	})
+	JustBeforeEach(func() {
+		err = service.NewService(sentryClient, app, fns...).Run(ctx)
+	})
+	Context("application succeeds", func() {
+		It("returns no error", func() {
+			Expect(err).To(BeNil())
+		})
+		It("captures nothing", func() {
+			Expect(sentryClient.CaptureExceptionCallCount()).To(Equal(0))
+		})
+	})
+	Context("application returns context canceled", func() {
+		BeforeEach(func() {
+			app.RunReturns(errors.Wrapf(ctx, context.Canceled, "consume failed"))
+		})
+		It("returns the error", func() {
+			Expect(stderrors.Is(err, context.Canceled)).To(BeTrue())
+		})
+		It("captures nothing", func() {
+			Expect(sentryClient.CaptureExceptionCallCount()).To(Equal(0))
+		})
+	})
+	Context("application returns an error excluded by the options", func() {
+		var banana error
+		BeforeEach(func() {
+			banana = stderrors.New("banana")
+			app.RunReturns(banana)
+			fns = append(fns, func(options *service.Options) {
+				options.ExcludeErrors = append(options.ExcludeErrors, func(err error) bool {
+					return stderrors.Is(err, banana)
+				})
+			})
+		})
+		It("returns the error", func() {
+			Expect(stderrors.Is(err, banana)).To(BeTrue())
+		})
+		It("captures nothing", func() {
+			Expect(sentryClient.CaptureExceptionCallCount()).To(Equal(0))
+		})
+	})
+	Context("application fails", func() {
+		var event *sentry.Event
+		BeforeEach(func() {
+			app.RunReturns(stderrors.New("banana"))
+		})
+		JustBeforeEach(func() {
+			Expect(sentryClient.CaptureExceptionCallCount()).To(Equal(1))
+			_, _, scope := sentryClient.CaptureExceptionArgsForCall(0)
+			event = scope.ApplyToEvent(&sentry.Event{
+				Tags: map[string]string{
+					"region": "caller",
+				},
+			}, nil, nil)
+		})
+		It("returns the error", func() {
+			Expect(err).NotTo(BeNil())
+		})
+		It("keeps the tags of the event", func() {
+			Expect(event.Tags).To(Equal(map[string]string{"region": "caller"}))
+		})
+		Context("WithSentryTags", func() {
+			BeforeEach(func() {
+				fns = append(fns, service.WithSentryTags(map[string]string{
+					"cluster": "prod",
+					"region":  "eu",
+				}))
+			})
+			It("adds the tags", func() {
+				Expect(event.Tags).To(HaveKeyWithValue("cluster", "prod"))
+			})
+			It("does not overwrite tags of the event", func() {
+				Expect(event.Tags).To(HaveKeyWithValue("region", "caller"))
+			})
+		})
+		Context("WithServiceName", func() {
+			BeforeEach(func() {
+				fns = append(fns, service.WithServiceName("order-service"))
+				ctx = errors.AddToContext(ctx, "correlation_id", "1234")
+			})
+			It("adds the service name as extra", func() {
+				Expect(event.Extra).To(HaveKeyWithValue("service", "order-service"))
+			})
+			It("adds the data of the context as extra", func() {
+				Expect(event.Extra).To(HaveKeyWithValue("correlation_id", "1234"))
+			})
+		})
+		It("adds no service extra without name", func() {
+			Expect(event.Extra).NotTo(HaveKey("service"))
+		})
+		Context("failed named func", func() {
+			BeforeEach(func() {
+				app.RunStub = func(ctx context.Context, sentryClient libsentry.Client) error {
+					return service.RunNamed(ctx, service.NamedFunc{
+						Name: "consumer",
+						Func: func(ctx context.Context) error {
+							return stderrors.New("banana")
+						},
+					})
+				}
+			})
+			It("tags the event with the func name", func() {
+				Expect(event.Tags).To(HaveKeyWithValue(service.FuncNameKey, "consumer"))
+			})
+		})
+		Context("failed func with trace ID", func() {
+			BeforeEach(func() {
+				app.RunStub = func(ctx context.Context, sentryClient libsentry.Client) error {
+					return service.RunWithValues(service.WithTraceID(ctx, "deploy-42"), nil, func(ctx context.Context) error {
+						return stderrors.New("banana")
+					})
+				}
+			})
+			It("tags the event with the trace ID", func() {
+				Expect(event.Tags).To(HaveKeyWithValue(service.TraceIDKey, "deploy-42"))
+			})
+		})
+		Context("trace ID in the context", func() {
+			BeforeEach(func() {
+				ctx = service.WithTraceID(ctx, "deploy-42")
+			})
+			It("tags the event with the trace ID", func() {
+				Expect(event.Tags).To(HaveKeyWithValue(service.TraceIDKey, "deploy-42"))
+			})
+		})
+		Context("panic attributed to a func", func() {
+			BeforeEach(func() {
+				app.RunReturns(&service.PanicError{Value: "banana", FuncName: "cron"})
+			})
+			It("tags the event with the func name", func() {
+				Expect(event.Tags).To(HaveKeyWithValue(service.FuncNameKey, "cron"))
+			})
+		})
+		Context("application panics", func() {
+			BeforeEach(func() {
+				app.RunStub = func(ctx context.Context, sentryClient libsentry.Client) error {
+					panic("banana")
+				}
+			})
+			It("returns a PanicError", func() {
+				var panicErr *service.PanicError
+				Expect(stderrors.As(err, &panicErr)).To(BeTrue())
+				Expect(panicErr.Value).To(Equal("banana"))
+			})
+			It("adds the stack to the event", func() {
+				Expect(event.Extra).To(HaveKeyWithValue("stack", ContainSubstring("service_test.go")))
+			})
+		})
+	})
+})
+
+var _ = Describe("Service WithRePanic", func() {
+	It("captures and continues the panic", func() {
+		sentryClient := &mocks.SentryClient{}
+		app := &mocks.ServiceApplication{}
+		app.RunStub = func(ctx context.Context, sentryClient libsentry.Client) error {
+			panic("banana")
+		}
+		Expect(func() {
+			_ = service.NewService(sentryClient, app, service.WithRePanic(true)).Run(context.Background())
+		}).To(PanicWith("banana"))
+		Expect(sentryClient.CaptureExceptionCallCount()).To(Equal(1))
+	})
+})
+
+var _ = Describe("Service captured event", func() {
+	It("logs the event id with the error", func() {
+		sentryClient := &mocks.SentryClient{}
+		eventID := sentry.EventID("0123456789abcdef")
+		sentryClient.CaptureExceptionReturns(&eventID)
+		app := &mocks.ServiceApplication{}
+		app.RunReturns(stderrors.New("banana"))
+		output := captureLog(func() {
+			_ = service.NewService(sentryClient, app).Run(context.Background())
+		})
+		Expect(output).To(ContainSubstring("captured error to sentry: event=0123456789abcdef err=banana"))
+	})
+})
+
+var _ = Describe("NewServiceWithOptions", func() {
+	var sentryClient *mocks.SentryClient
+	var app *mocks.ServiceApplication
+	var banana error
+	var options service.Options
+	BeforeEach(func() {
+		sentryClient = &mocks.SentryClient{}
+		app = &mocks.ServiceApplication{}
+		banana = stderrors.New("banana")
+		options = service.NewOptions(service.WithExcludeErrors(func(err error) bool {
+			return stderrors.Is(err, banana)
+		}))
+	})
+	It("returns an excluded error without capturing it", func() {
+		app.RunReturns(banana)
+		err := service.NewServiceWithOptions(sentryClient, app, options).Run(context.Background())
+		Expect(stderrors.Is(err, banana)).To(BeTrue())
+		Expect(sentryClient.CaptureExceptionCallCount()).To(Equal(0))
+	})
+	It("captures an error that is not excluded", func() {
+		app.RunReturns(stderrors.New("apple"))
+		err := service.NewServiceWithOptions(sentryClient, app, options).Run(context.Background())
+		Expect(err).NotTo(BeNil())
+		Expect(sentryClient.CaptureExceptionCallCount()).To(Equal(1))
+	})
+	It("captures everything without ExcludeErrors", func() {
+		app.RunReturns(context.Canceled)
+		options.ExcludeErrors = nil
+		err := service.NewServiceWithOptions(sentryClient, app, options).Run(context.Background())
+		Expect(err).NotTo(BeNil())
+		Expect(sentryClient.CaptureExceptionCallCount()).To(Equal(1))
+	})
+})
+
+var _ = Describe("Service WithErrorCallback", func() {
+	var sentryClient *mocks.SentryClient
+	var app *mocks.ServiceApplication
+	var banana error
+	var errs []error
+	var fns []service.OptionsFn
+	BeforeEach(func() {
+		sentryClient = &mocks.SentryClient{}
+		app = &mocks.ServiceApplication{}
+		banana = stderrors.New("banana")
+		errs = nil
+		fns = []service.OptionsFn{
+			service.WithErrorCallback(func(ctx context.Context, err error) {
+				Expect(sentryClient.CaptureExceptionCallCount()).To(Equal(0))
+				errs = append(errs, err)
+			}),
+		}
+	})
+	It("calls the callback with the raw error before the capture", func() {
+		app.RunReturns(banana)
+		err := service.NewService(sentryClient, app, fns...).Run(context.Background())
+		Expect(err).NotTo(BeNil())
+		Expect(errs).To(Equal([]error{banana}))
+		Expect(sentryClient.CaptureExceptionCallCount()).To(Equal(1))
+	})
+	It("calls the callback for an excluded error", func() {
+		app.RunReturns(context.Canceled)
+		_ = service.NewService(sentryClient, app, fns...).Run(context.Background())
+		Expect(errs).To(Equal([]error{context.Canceled}))
+		Expect(sentryClient.CaptureExceptionCallCount()).To(Equal(0))
+	})
+	It("calls the callback with the panic error", func() {
+		app.RunStub = func(ctx context.Context, client libsentry.Client) error {
+			panic("banana")
+		}
+		_ = service.NewService(sentryClient, app, fns...).Run(context.Background())
+		Expect(errs).To(HaveLen(1))
+		var panicError *service.PanicError
+		Expect(stderrors.As(errs[0], &panicError)).To(BeTrue())
+		Expect(panicError.Value).To(Equal("banana"))
+	})
+	It("does not call the callback on success", func() {
+		Expect(service.NewService(sentryClient, app, fns...).Run(context.Background())).To(BeNil())
+		Expect(errs).To(BeEmpty())
+	})
+})
@@ -0,0 +1,74 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package servicetest_test
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/bborbe/errors"
+	libsentry "github.com/bborbe/sentry"
+
+	"github.com/bborbe/service"
+	"github.com/bborbe/service/servicetest"
+)
+
+type failingApp struct{}
+
+func (f *failingApp) Run(ctx context.Context, sentryClient libsentry.Client) error {
+	return errors.AddDataToError(errors.New(ctx, "banana"), map[string]string{"order": "42"})
+}
+
+func ExampleNewSentryTransport() {
+	ctx := context.Background()
+	transport := servicetest.NewSentryTransport()
+	fns := []service.OptionsFn{
+		service.WithSentryTransport(transport),
+		service.WithSentryTags(map[string]string{"region": "eu"}),
+	}
+
+	clientOptions, _ := service.NewSentryClientOptions(ctx, "", nil, service.NewOptions(fns...))
+	sentryClient, _ := libsentry.NewClient(ctx, *clientOptions)
+	_ = service.NewService(sentryClient, &failingApp{}, fns...).Run(ctx)
+
+	for _, event := range transport.Events() {
+		fmt.Println("order:", event.Tags["order"])
+		fmt.Println("region:", event.Tags["region"])
+	}
+	// Output:
+	// order: 42
+	// region: eu
+}
+
+func ExampleNewFakeClock() {
+	clock := servicetest.NewFakeClock(time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC))
+	ctx, cancel := context.WithCancel(service.ContextWithClock(context.Background(), clock))
+
+	ticks := make(chan time.Time)
+	done := make(chan error)
+	go func() {
+		done <- service.Periodic(time.Minute, func(ctx context.Context) error {
+			ticks <- service.ClockFromContext(ctx).Now()
+			return nil
+		})(ctx)
+	}()
+
+	fmt.Println((<-ticks).Format(time.Kitchen))
+	for i := 0; i < 2; i++ {
+		// wait for the ticker of Periodic before advancing the time
+		clock.BlockUntil(1)
+		clock.Add(time.Minute)
+		fmt.Println((<-ticks).Format(time.Kitchen))
+	}
+	cancel()
+	fmt.Println(<-done)
+
+	// Output:
+	// 12:00PM
+	// 12:01PM
+	// 12:02PM
+	// <nil>
+}
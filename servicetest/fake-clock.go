@@ -0,0 +1,146 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package servicetest
+
+import (
+	"sync"
+	"time"
+
+	"github.com/bborbe/service"
+)
+
+// FakeClock is a service.Clock that only moves when the test advances it.
+type FakeClock interface {
+	service.Clock
+	// Add advances the time by d and fires all timers and tickers that are due.
+	Add(d time.Duration)
+	// BlockUntil waits until n timers and tickers are active,
+	// so the test knows the code under test waits for the clock.
+	BlockUntil(n int)
+}
+
+// NewFakeClock returns a FakeClock starting at now.
+func NewFakeClock(now time.Time) FakeClock {
+	return &fakeClock{
+		now:     now,
+		changed: make(chan struct{}),
+	}
+}
+
+type fakeClock struct {
+	mux     sync.Mutex
+	now     time.Time
+	waiters []*fakeWaiter
+	changed chan struct{}
+}
+
+type fakeWaiter struct {
+	clock    *fakeClock
+	deadline time.Time
+	period   time.Duration
+	ch       chan time.Time
+}
+
+func (f *fakeClock) Now() time.Time {
+	f.mux.Lock()
+	defer f.mux.Unlock()
+	return f.now
+}
+
+func (f *fakeClock) NewTimer(d time.Duration) service.Timer {
+	return f.add(d, 0)
+}
+
+func (f *fakeClock) NewTicker(d time.Duration) service.Ticker {
+	return &fakeTicker{fakeWaiter: f.add(d, d)}
+}
+
+func (f *fakeClock) add(d time.Duration, period time.Duration) *fakeWaiter {
+	f.mux.Lock()
+	defer f.mux.Unlock()
+	waiter := &fakeWaiter{
+		clock:    f,
+		deadline: f.now.Add(d),
+		period:   period,
+		ch:       make(chan time.Time, 1),
+	}
+	f.waiters = append(f.waiters, waiter)
+	f.notify()
+	return waiter
+}
+
+func (f *fakeClock) Add(d time.Duration) {
+	f.mux.Lock()
+	defer f.mux.Unlock()
+	f.now = f.now.Add(d)
+	active := f.waiters[:0]
+	for _, waiter := range f.waiters {
+		if waiter.deadline.After(f.now) {
+			active = append(active, waiter)
+			continue
+		}
+		select {
+		case waiter.ch <- f.now:
+		default:
+			// like time.Ticker, ticks are dropped for slow receivers
+		}
+		if waiter.period > 0 {
+			for !waiter.deadline.After(f.now) {
+				waiter.deadline = waiter.deadline.Add(waiter.period)
+			}
+			active = append(active, waiter)
+		}
+	}
+	f.waiters = active
+	f.notify()
+}
+
+func (f *fakeClock) BlockUntil(n int) {
+	for {
+		f.mux.Lock()
+		count := len(f.waiters)
+		changed := f.changed
+		f.mux.Unlock()
+		if count >= n {
+			return
+		}
+		<-changed
+	}
+}
+
+// notify wakes up BlockUntil. The mutex must be held.
+func (f *fakeClock) notify() {
+	close(f.changed)
+	f.changed = make(chan struct{})
+}
+
+func (f *fakeClock) remove(waiter *fakeWaiter) bool {
+	f.mux.Lock()
+	defer f.mux.Unlock()
+	for i, w := range f.waiters {
+		if w == waiter {
+			f.waiters = append(f.waiters[:i], f.waiters[i+1:]...)
+			f.notify()
+			return true
+		}
+	}
+	return false
+}
+
+func (w *fakeWaiter) C() <-chan time.Time {
+	return w.ch
+}
+
+func (w *fakeWaiter) Stop() bool {
+	return w.clock.remove(w)
+}
+
+type fakeTicker struct {
+	*fakeWaiter
+}
+
+func (f *fakeTicker) Stop() {
+	f.fakeWaiter.Stop()
+}
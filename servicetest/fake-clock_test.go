@@ -0,0 +1,61 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package servicetest_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/bborbe/service/servicetest"
+)
+
+var _ = Describe("FakeClock", func() {
+	var now time.Time
+	var clock servicetest.FakeClock
+	BeforeEach(func() {
+		now = time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+		clock = servicetest.NewFakeClock(now)
+	})
+	It("moves only when advanced", func() {
+		Expect(clock.Now()).To(Equal(now))
+		clock.Add(time.Second)
+		Expect(clock.Now()).To(Equal(now.Add(time.Second)))
+	})
+	It("fires a timer once it is due", func() {
+		timer := clock.NewTimer(time.Minute)
+		clock.Add(59 * time.Second)
+		Consistently(timer.C()).ShouldNot(Receive())
+		clock.Add(time.Second)
+		Expect(timer.C()).To(Receive(Equal(now.Add(time.Minute))))
+		Expect(timer.Stop()).To(BeFalse())
+	})
+	It("does not fire a stopped timer", func() {
+		timer := clock.NewTimer(time.Minute)
+		Expect(timer.Stop()).To(BeTrue())
+		clock.Add(time.Hour)
+		Consistently(timer.C()).ShouldNot(Receive())
+	})
+	It("fires a ticker every period", func() {
+		ticker := clock.NewTicker(time.Minute)
+		defer ticker.Stop()
+		clock.Add(time.Minute)
+		Expect(ticker.C()).To(Receive(Equal(now.Add(time.Minute))))
+		clock.Add(time.Minute)
+		Expect(ticker.C()).To(Receive(Equal(now.Add(2 * time.Minute))))
+	})
+	It("blocks until the timers are active", func() {
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			clock.BlockUntil(2)
+		}()
+		clock.NewTimer(time.Minute)
+		Consistently(done).ShouldNot(BeClosed())
+		clock.NewTicker(time.Minute)
+		Eventually(done).Should(BeClosed())
+	})
+})
@@ -0,0 +1,56 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package servicetest
+
+import (
+	"sync"
+	"time"
+
+	"github.com/getsentry/sentry-go"
+)
+
+// SentryTransport records the events of a Sentry client instead of sending them,
+// so tests can assert the payload, like tags or fingerprint, without a network.
+type SentryTransport interface {
+	sentry.Transport
+	// Events returns a copy of all events sent so far.
+	Events() []*sentry.Event
+	// Reset removes all recorded events.
+	Reset()
+}
+
+// NewSentryTransport returns a SentryTransport to pass to service.WithSentryTransport.
+func NewSentryTransport() SentryTransport {
+	return &sentryTransport{}
+}
+
+type sentryTransport struct {
+	mux    sync.Mutex
+	events []*sentry.Event
+}
+
+func (s *sentryTransport) Flush(timeout time.Duration) bool {
+	return true
+}
+
+func (s *sentryTransport) Configure(options sentry.ClientOptions) {}
+
+func (s *sentryTransport) SendEvent(event *sentry.Event) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	s.events = append(s.events, event)
+}
+
+func (s *sentryTransport) Events() []*sentry.Event {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	return append([]*sentry.Event{}, s.events...)
+}
+
+func (s *sentryTransport) Reset() {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	s.events = nil
+}
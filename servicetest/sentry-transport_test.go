@@ -0,0 +1,59 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package servicetest_test
+
+import (
+	"context"
+	stderrors "errors"
+
+	libsentry "github.com/bborbe/sentry"
+	"github.com/getsentry/sentry-go"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/bborbe/service"
+	"github.com/bborbe/service/mocks"
+	"github.com/bborbe/service/servicetest"
+)
+
+var _ = Describe("SentryTransport", func() {
+	var ctx context.Context
+	var transport servicetest.SentryTransport
+	var sentryClient libsentry.Client
+	BeforeEach(func() {
+		ctx = context.Background()
+		transport = servicetest.NewSentryTransport()
+		options := service.NewOptions(
+			service.WithSentryTransport(transport),
+			service.WithBeforeSend(func(event *sentry.Event, hint *sentry.EventHint) *sentry.Event {
+				event.Fingerprint = []string{"banana"}
+				return event
+			}),
+		)
+		clientOptions, err := service.NewSentryClientOptions(ctx, "", nil, options)
+		Expect(err).To(BeNil())
+		sentryClient, err = libsentry.NewClient(ctx, *clientOptions)
+		Expect(err).To(BeNil())
+	})
+	It("records nothing without capture", func() {
+		Expect(transport.Events()).To(BeEmpty())
+	})
+	It("records the event captured by the service", func() {
+		app := &mocks.ServiceApplication{}
+		app.RunReturns(stderrors.New("app failed"))
+		err := service.NewService(sentryClient, app, service.WithSentryTags(map[string]string{"region": "eu"})).Run(ctx)
+		Expect(err).NotTo(BeNil())
+		events := transport.Events()
+		Expect(events).To(HaveLen(1))
+		Expect(events[0].Tags).To(HaveKeyWithValue("region", "eu"))
+		Expect(events[0].Fingerprint).To(Equal([]string{"banana"}))
+	})
+	It("removes recorded events on reset", func() {
+		sentryClient.CaptureMessage("banana", &sentry.EventHint{}, nil)
+		Expect(transport.Events()).To(HaveLen(1))
+		transport.Reset()
+		Expect(transport.Events()).To(BeEmpty())
+	})
+})
@@ -0,0 +1,47 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package service
+
+import (
+	"math/rand/v2"
+
+	libsentry "github.com/bborbe/sentry"
+	"github.com/getsentry/sentry-go"
+)
+
+// SeveritySampler returns the sample rate in range [0.0,1.0] for a captured error,
+// e.g. 1.0 for critical errors and 0.05 for noisy low-value ones.
+type SeveritySampler func(err error) float64
+
+// newSamplingSentryClient returns a client that captures each exception with the rate the sampler returns for it.
+// The random func returns values in [0.0,1.0).
+func newSamplingSentryClient(client libsentry.Client, sampler SeveritySampler, random func() float64, logger Logger) libsentry.Client {
+	return &samplingSentryClient{
+		Client:  client,
+		sampler: sampler,
+		random:  random,
+		logger:  logger,
+	}
+}
+
+type samplingSentryClient struct {
+	libsentry.Client
+	sampler SeveritySampler
+	random  func() float64
+	logger  Logger
+}
+
+func (s *samplingSentryClient) CaptureException(err error, hint *sentry.EventHint, scope sentry.EventModifier) *sentry.EventID {
+	if rate := s.sampler(err); rate < 1 && s.random() >= rate {
+		if s.logger.V(4) {
+			s.logger.Infof("error not sampled with rate %v => skip capture exception: %v", rate, err)
+		}
+		return nil
+	}
+	return s.Client.CaptureException(err, hint, scope)
+}
+
+// defaultRandom is the random source of the severity sampling.
+var defaultRandom = rand.Float64
@@ -0,0 +1,59 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package service_test
+
+import (
+	stderrors "errors"
+
+	libsentry "github.com/bborbe/sentry"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/bborbe/service"
+	"github.com/bborbe/service/mocks"
+)
+
+var _ = Describe("SamplingSentryClient", func() {
+	var sentryClient *mocks.SentryClient
+	var client libsentry.Client
+	var criticalErr error
+	var noisyErr error
+	var randomValue float64
+	BeforeEach(func() {
+		sentryClient = &mocks.SentryClient{}
+		criticalErr = stderrors.New("critical")
+		noisyErr = stderrors.New("noisy")
+		client = service.NewSamplingSentryClient(sentryClient, func(err error) float64 {
+			if stderrors.Is(err, criticalErr) {
+				return 1
+			}
+			return 0.05
+		}, func() float64 {
+			return randomValue
+		}, service.NewGlogLogger())
+	})
+	It("always captures a high severity error", func() {
+		for _, value := range []float64{0, 0.5, 0.999} {
+			randomValue = value
+			client.CaptureException(criticalErr, nil, nil)
+		}
+		Expect(sentryClient.CaptureExceptionCallCount()).To(Equal(3))
+	})
+	It("captures a low severity error within the sample rate", func() {
+		randomValue = 0.01
+		client.CaptureException(noisyErr, nil, nil)
+		Expect(sentryClient.CaptureExceptionCallCount()).To(Equal(1))
+	})
+	It("drops a low severity error outside the sample rate", func() {
+		randomValue = 0.5
+		Expect(client.CaptureException(noisyErr, nil, nil)).To(BeNil())
+		Expect(sentryClient.CaptureExceptionCallCount()).To(Equal(0))
+	})
+	It("passes messages through", func() {
+		randomValue = 0.5
+		client.CaptureMessage("banana", nil, nil)
+		Expect(sentryClient.CaptureMessageCallCount()).To(Equal(1))
+	})
+})
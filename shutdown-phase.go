@@ -0,0 +1,74 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package service
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/bborbe/errors"
+)
+
+// DefaultShutdownPhaseTimeout is the timeout of each shutdown phase if no ShutdownTimeout is configured.
+const DefaultShutdownPhaseTimeout = 10 * time.Second
+
+// ShutdownPhase is a named step of the shutdown, like draining in-flight work or writing a checkpoint.
+type ShutdownPhase struct {
+	Name  string
+	Order int
+	Fn    func(ctx context.Context) error
+}
+
+// runShutdownPhases runs the phases by ascending order after the application returned.
+// With a ShutdownTimeout each phase gets an equal share of the remaining budget,
+// so a phase finishing early leaves more time for the following ones.
+// A phase exceeding its timeout is left behind and the next phase starts.
+func runShutdownPhases(ctx context.Context, phases []ShutdownPhase, shutdown *shutdownDeadline, logger Logger) error {
+	if len(phases) == 0 {
+		return nil
+	}
+	clock := ClockFromContext(ctx)
+	shutdown.Start(clock.Now())
+	phases = append([]ShutdownPhase{}, phases...)
+	sort.SliceStable(phases, func(i, j int) bool {
+		return phases[i].Order < phases[j].Order
+	})
+
+	var errs []error
+	for i, phase := range phases {
+		timeout := DefaultShutdownPhaseTimeout
+		if shutdown.Enabled() {
+			timeout = shutdown.Remaining(clock.Now()) / time.Duration(len(phases)-i)
+		}
+		if logger.V(2) {
+			logger.Infof("run shutdown phase %s with timeout %v", phase.Name, timeout)
+		}
+		if err := runShutdownPhase(ctx, phase, timeout); err != nil {
+			logger.Warningf("shutdown phase %s failed: %v", phase.Name, err)
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func runShutdownPhase(ctx context.Context, phase ShutdownPhase, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- CatchPanic(phase.Fn)(ctx)
+	}()
+	select {
+	case err := <-errCh:
+		if err != nil {
+			return errors.Wrapf(ctx, err, "shutdown phase %s failed", phase.Name)
+		}
+		return nil
+	case <-ctx.Done():
+		return errors.Errorf(ctx, "shutdown phase %s exceeded timeout %v", phase.Name, timeout)
+	}
+}
@@ -0,0 +1,105 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package service_test
+
+import (
+	"context"
+	stderrors "errors"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/bborbe/service"
+)
+
+var _ = Describe("RunShutdownPhases", func() {
+	var ctx context.Context
+	var mux sync.Mutex
+	var calls []string
+	var release chan struct{}
+	BeforeEach(func() {
+		ctx = context.Background()
+		calls = nil
+		release = make(chan struct{})
+	})
+	AfterEach(func() {
+		close(release)
+	})
+	phase := func(name string, order int) service.ShutdownPhase {
+		return service.ShutdownPhase{
+			Name:  name,
+			Order: order,
+			Fn: func(ctx context.Context) error {
+				mux.Lock()
+				defer mux.Unlock()
+				calls = append(calls, name)
+				return nil
+			},
+		}
+	}
+	called := func() []string {
+		mux.Lock()
+		defer mux.Unlock()
+		return append([]string{}, calls...)
+	}
+	It("runs the phases by ascending order", func() {
+		err := service.RunShutdownPhases(ctx, []service.ShutdownPhase{
+			phase("checkpoint", 2),
+			phase("drain", 1),
+			phase("flush", 2),
+		}, service.NewShutdownDeadline(0), service.NewGlogLogger())
+		Expect(err).To(BeNil())
+		Expect(called()).To(Equal([]string{"drain", "checkpoint", "flush"}))
+	})
+	It("continues with the next phase after a phase exceeded its timeout", func() {
+		released := release
+		start := time.Now()
+		err := service.RunShutdownPhases(ctx, []service.ShutdownPhase{
+			{
+				Name:  "drain",
+				Order: 1,
+				Fn: func(ctx context.Context) error {
+					<-released
+					return nil
+				},
+			},
+			phase("checkpoint", 2),
+		}, service.NewShutdownDeadline(100*time.Millisecond), service.NewGlogLogger())
+		Expect(err).NotTo(BeNil())
+		Expect(err.Error()).To(ContainSubstring("shutdown phase drain exceeded timeout"))
+		Expect(called()).To(Equal([]string{"checkpoint"}))
+		Expect(time.Since(start)).To(BeNumerically("<", 100*time.Millisecond))
+	})
+	It("gives each phase its share of the remaining budget", func() {
+		var deadlines []time.Duration
+		record := func(ctx context.Context) error {
+			deadline, ok := ctx.Deadline()
+			Expect(ok).To(BeTrue())
+			deadlines = append(deadlines, time.Until(deadline))
+			return nil
+		}
+		err := service.RunShutdownPhases(ctx, []service.ShutdownPhase{
+			{Name: "drain", Order: 1, Fn: record},
+			{Name: "checkpoint", Order: 2, Fn: record},
+		}, service.NewShutdownDeadline(time.Second), service.NewGlogLogger())
+		Expect(err).To(BeNil())
+		Expect(deadlines).To(HaveLen(2))
+		Expect(deadlines[0]).To(BeNumerically("~", 500*time.Millisecond, 50*time.Millisecond))
+		Expect(deadlines[1]).To(BeNumerically("~", time.Second, 50*time.Millisecond))
+	})
+	It("returns the errors of failed phases and runs the others", func() {
+		err := service.RunShutdownPhases(ctx, []service.ShutdownPhase{
+			{Name: "drain", Order: 1, Fn: func(ctx context.Context) error {
+				return stderrors.New("banana")
+			}},
+			phase("checkpoint", 2),
+		}, service.NewShutdownDeadline(0), service.NewGlogLogger())
+		Expect(err).NotTo(BeNil())
+		Expect(err.Error()).To(ContainSubstring("shutdown phase drain failed"))
+		Expect(called()).To(Equal([]string{"checkpoint"}))
+	})
+})
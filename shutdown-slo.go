@@ -0,0 +1,47 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package service
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// ShutdownSLO compares the measured shutdown duration with the expected one.
+// Unlike the shutdown timeout it never cuts the shutdown short, it only reports violations.
+type ShutdownSLO interface {
+	// Observe the duration of a shutdown and report whether it exceeded the expected duration.
+	Observe(elapsed time.Duration) bool
+}
+
+// newShutdownSLO returns a ShutdownSLO counting violations in service_shutdown_slo_violations_total.
+// An expected duration of zero disables the check.
+func newShutdownSLO(registerer prometheus.Registerer, expected time.Duration, logger Logger) ShutdownSLO {
+	return &shutdownSLO{
+		expected: expected,
+		logger:   logger,
+		violations: register(registerer, prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "shutdown_slo_violations_total",
+			Help:      "Number of shutdowns that took longer than the expected shutdown duration.",
+		})),
+	}
+}
+
+type shutdownSLO struct {
+	expected   time.Duration
+	logger     Logger
+	violations prometheus.Counter
+}
+
+func (s *shutdownSLO) Observe(elapsed time.Duration) bool {
+	if s.expected <= 0 || elapsed <= s.expected {
+		return false
+	}
+	s.violations.Inc()
+	s.logger.Warningf("shutdown took %v, longer than the expected %v", elapsed, s.expected)
+	return true
+}
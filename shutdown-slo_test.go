@@ -0,0 +1,61 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package service_test
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/bborbe/service"
+	"github.com/bborbe/service/servicetest"
+)
+
+var _ = Describe("ShutdownSLO", func() {
+	var registry *prometheus.Registry
+	var logger *recordingLogger
+	BeforeEach(func() {
+		registry = prometheus.NewRegistry()
+		logger = &recordingLogger{}
+	})
+	It("counts a shutdown exceeding the expected duration", func() {
+		slo := service.NewShutdownSLO(registry, 10*time.Second, logger)
+		Expect(slo.Observe(11 * time.Second)).To(BeTrue())
+		Expect(metricValue(registry, "service_shutdown_slo_violations_total")).To(Equal(1.0))
+		Expect(logger.Lines()).To(ConsistOf("W shutdown took 11s, longer than the expected 10s"))
+	})
+	It("ignores a shutdown within the expected duration", func() {
+		slo := service.NewShutdownSLO(registry, 10*time.Second, logger)
+		Expect(slo.Observe(10 * time.Second)).To(BeFalse())
+		Expect(metricValue(registry, "service_shutdown_slo_violations_total")).To(Equal(0.0))
+		Expect(logger.Lines()).To(BeEmpty())
+	})
+	It("is disabled without expected duration", func() {
+		slo := service.NewShutdownSLO(registry, 0, logger)
+		Expect(slo.Observe(time.Hour)).To(BeFalse())
+	})
+	It("counts a slow shutdown of Main", func() {
+		app := &mainApplication{
+			RunFunc: func(ctx context.Context) error {
+				return nil
+			},
+		}
+		Expect(service.Main(context.Background(), app, &app.SentryDSN, nil,
+			service.WithoutTimezoneOverride(),
+			service.WithRegisterer(registry),
+			service.WithLogger(logger),
+			service.WithSentryTransport(servicetest.NewSentryTransport()),
+			service.WithExpectedShutdownDuration(10*time.Millisecond),
+			service.WithShutdownPhase("slow", func(ctx context.Context) error {
+				time.Sleep(50 * time.Millisecond)
+				return nil
+			}, 1),
+		)).To(Equal(service.ExitCodeSuccess))
+		Expect(metricValue(registry, "service_shutdown_slo_violations_total")).To(Equal(1.0))
+	})
+})
@@ -0,0 +1,113 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package service
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/bborbe/errors"
+)
+
+const sentryFlushTimeout = 2 * time.Second
+
+func newShutdownDeadline(timeout time.Duration) *shutdownDeadline {
+	return &shutdownDeadline{
+		timeout: timeout,
+	}
+}
+
+// shutdownDeadline tracks the hard shutdown budget.
+// The budget starts with the first call of Start.
+type shutdownDeadline struct {
+	timeout time.Duration
+
+	mux     sync.Mutex
+	started time.Time
+}
+
+// Start the shutdown budget. Only the first call has an effect.
+func (s *shutdownDeadline) Start(now time.Time) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	if s.started.IsZero() {
+		s.started = now
+	}
+}
+
+// Elapsed time since the budget started at the given time. It is zero before Start.
+func (s *shutdownDeadline) Elapsed(now time.Time) time.Duration {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	if s.started.IsZero() {
+		return 0
+	}
+	return now.Sub(s.started)
+}
+
+// Enabled reports whether a shutdown budget is configured.
+func (s *shutdownDeadline) Enabled() bool {
+	return s.timeout > 0
+}
+
+// Remaining budget at the given time. It is never negative.
+func (s *shutdownDeadline) Remaining(now time.Time) time.Duration {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	if s.started.IsZero() {
+		return s.timeout
+	}
+	remaining := s.started.Add(s.timeout).Sub(now)
+	if remaining < 0 {
+		return 0
+	}
+	return remaining
+}
+
+// FlushTimeout returns the given timeout clamped to the remaining budget.
+func (s *shutdownDeadline) FlushTimeout(now time.Time, timeout time.Duration) time.Duration {
+	if !s.Enabled() {
+		return timeout
+	}
+	if remaining := s.Remaining(now); remaining < timeout {
+		return remaining
+	}
+	return timeout
+}
+
+// Run the given func and start the budget as soon as ctx is canceled.
+// If the func does not return within the budget an error is returned without waiting any longer.
+func (s *shutdownDeadline) Run(ctx context.Context, fn func(ctx context.Context) error) error {
+	clock := ClockFromContext(ctx)
+	stop := context.AfterFunc(ctx, func() {
+		s.Start(clock.Now())
+	})
+	defer stop()
+
+	if !s.Enabled() {
+		return fn(ctx)
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- fn(ctx)
+	}()
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+		s.Start(clock.Now())
+	}
+
+	timer := clock.NewTimer(s.Remaining(clock.Now()))
+	defer timer.Stop()
+	select {
+	case err := <-errCh:
+		return err
+	case <-timer.C():
+		return errors.Errorf(ctx, "shutdown timeout %v exceeded", s.timeout)
+	}
+}
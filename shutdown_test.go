@@ -0,0 +1,82 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package service_test
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/bborbe/service"
+)
+
+var _ = Describe("ShutdownDeadline", func() {
+	var now time.Time
+	BeforeEach(func() {
+		now = time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	})
+	It("measures the elapsed time since the start", func() {
+		deadline := service.NewShutdownDeadline(0)
+		Expect(deadline.Elapsed(now)).To(Equal(time.Duration(0)))
+		deadline.Start(now)
+		Expect(deadline.Elapsed(now.Add(3 * time.Second))).To(Equal(3 * time.Second))
+	})
+	Context("without timeout", func() {
+		It("does not clamp the flush timeout", func() {
+			deadline := service.NewShutdownDeadline(0)
+			deadline.Start(now)
+			Expect(deadline.FlushTimeout(now.Add(time.Hour), 2*time.Second)).To(Equal(2 * time.Second))
+		})
+	})
+	Context("with plenty of budget", func() {
+		It("returns the flush timeout", func() {
+			deadline := service.NewShutdownDeadline(30 * time.Second)
+			deadline.Start(now)
+			Expect(deadline.FlushTimeout(now.Add(time.Second), 2*time.Second)).To(Equal(2 * time.Second))
+		})
+	})
+	Context("with tight deadline", func() {
+		It("clamps the flush timeout to the remaining budget", func() {
+			deadline := service.NewShutdownDeadline(5 * time.Second)
+			deadline.Start(now)
+			Expect(deadline.FlushTimeout(now.Add(4500*time.Millisecond), 2*time.Second)).To(Equal(500 * time.Millisecond))
+		})
+		It("returns zero if the budget is exhausted", func() {
+			deadline := service.NewShutdownDeadline(5 * time.Second)
+			deadline.Start(now)
+			Expect(deadline.FlushTimeout(now.Add(time.Minute), 2*time.Second)).To(Equal(time.Duration(0)))
+		})
+		It("ignores later starts", func() {
+			deadline := service.NewShutdownDeadline(5 * time.Second)
+			deadline.Start(now)
+			deadline.Start(now.Add(4 * time.Second))
+			Expect(deadline.Remaining(now.Add(4 * time.Second))).To(Equal(time.Second))
+		})
+	})
+	Context("Run", func() {
+		It("returns an error if the func does not stop within the budget", func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+			deadline := service.NewShutdownDeadline(50 * time.Millisecond)
+			done := make(chan struct{})
+			defer close(done)
+			err := deadline.Run(ctx, func(ctx context.Context) error {
+				<-done
+				return nil
+			})
+			Expect(err).NotTo(BeNil())
+			Expect(err.Error()).To(ContainSubstring("shutdown timeout"))
+		})
+		It("returns the result of the func", func() {
+			deadline := service.NewShutdownDeadline(50 * time.Millisecond)
+			err := deadline.Run(context.Background(), func(ctx context.Context) error {
+				return nil
+			})
+			Expect(err).To(BeNil())
+		})
+	})
+})
@@ -0,0 +1,138 @@
+// Copyright (c) 2023 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package service
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// DefaultForceExitCode is used if a shutdown signal is received a second time, like 128+SIGINT.
+const DefaultForceExitCode = 130
+
+// contextWithSig returns a context that is canceled on one of the signals of the options.
+// A second identical signal exits the process immediately with the ForceExitCode of the options.
+// The signal handler is registered before it returns and stays registered until ctx is done.
+func contextWithSig(ctx context.Context, options Options) context.Context {
+	signalCh := make(chan os.Signal, 1)
+	signal.Notify(signalCh, options.Signals...)
+	context.AfterFunc(ctx, func() {
+		signal.Stop(signalCh)
+	})
+	return contextWithSignalCh(ctx, signalCh, options, os.Exit)
+}
+
+// contextWithSignalCh returns a context that is canceled as soon as a signal is received on the given channel.
+// If the same signal is received again before ctx is done, exit is called with the ForceExitCode of the options.
+func contextWithSignalCh(
+	ctx context.Context,
+	signalCh <-chan os.Signal,
+	options Options,
+	exit func(code int),
+) context.Context {
+	received := &receivedSignal{}
+	ctxWithCancel, cancel := context.WithCancel(context.WithValue(ctx, signalContextKey{}, received))
+	signalToCancel := newSignalToCancelHistogram(options.Registerer)
+	context.AfterFunc(ctxWithCancel, func() {
+		if at := received.At(); !at.IsZero() {
+			signalToCancel.Observe(time.Since(at).Seconds())
+		}
+	})
+	go func() {
+		defer cancel()
+
+		var first os.Signal
+		select {
+		case first = <-signalCh:
+			received.Set(first, time.Now())
+			if options.Logger.V(2) {
+				options.Logger.Infof("got signal %s => cancel context ", first)
+			}
+			if options.CancelParentOnSignal != nil {
+				if options.Logger.V(2) {
+					options.Logger.Infof("cancel parent context")
+				}
+				options.CancelParentOnSignal()
+			}
+			cancel()
+		case <-ctx.Done():
+			return
+		}
+
+		for {
+			select {
+			case signal := <-signalCh:
+				if signal != first {
+					if options.Logger.V(2) {
+						options.Logger.Infof("got signal %s during shutdown => ignore", signal)
+					}
+					continue
+				}
+				options.Logger.Warningf("got signal %s again => force exit with code %d", signal, options.ForceExitCode)
+				glog.Flush()
+				exit(options.ForceExitCode)
+				return
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return ctxWithCancel
+}
+
+type signalContextKey struct{}
+
+type receivedSignal struct {
+	mux    sync.Mutex
+	signal os.Signal
+	at     time.Time
+}
+
+func (r *receivedSignal) Set(signal os.Signal, at time.Time) {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	r.signal = signal
+	r.at = at
+}
+
+func (r *receivedSignal) At() time.Time {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	return r.at
+}
+
+func (r *receivedSignal) Get() os.Signal {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	return r.signal
+}
+
+// SignalFromContext returns the signal that began the shutdown of Main,
+// e.g. SIGTERM for a drain by Kubernetes or SIGINT for a manual stop.
+// It returns nil if no signal was received or the shutdown had another cause.
+func SignalFromContext(ctx context.Context) os.Signal {
+	received, ok := ctx.Value(signalContextKey{}).(*receivedSignal)
+	if !ok {
+		return nil
+	}
+	return received.Get()
+}
+
+// newSignalToCancelHistogram measures the time from the receipt of the signal until the cancellation
+// of the context propagated, which grows with GC pauses or goroutine starvation.
+func newSignalToCancelHistogram(registerer prometheus.Registerer) prometheus.Histogram {
+	return register(registerer, prometheus.NewHistogram(prometheus.HistogramOpts{
+		Namespace: metricsNamespace,
+		Name:      "signal_to_cancel_seconds",
+		Help:      "Time from the receipt of the shutdown signal until the context cancellation propagated.",
+		Buckets:   []float64{.0001, .0005, .001, .005, .01, .05, .1, .5, 1},
+	}))
+}
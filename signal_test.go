@@ -0,0 +1,148 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package service_test
+
+import (
+	"context"
+	"os"
+	"syscall"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/bborbe/service"
+)
+
+var _ = Describe("ContextWithSignalCh", func() {
+	var parentCtx context.Context
+	var parentCancel context.CancelFunc
+	var ctx context.Context
+	var signalCh chan os.Signal
+	var exitCodes chan int
+	var exit func(code int)
+	BeforeEach(func() {
+		parentCtx, parentCancel = context.WithCancel(context.Background())
+		signalCh = make(chan os.Signal, 1)
+		exitCodes = make(chan int, 1)
+		exit = func(code int) {
+			exitCodes <- code
+		}
+	})
+	AfterEach(func() {
+		parentCancel()
+	})
+	Context("signal latency", func() {
+		var registry *prometheus.Registry
+		BeforeEach(func() {
+			registry = prometheus.NewRegistry()
+			ctx = service.ContextWithSignalCh(parentCtx, signalCh, service.NewOptions(service.WithRegisterer(registry)), exit)
+		})
+		It("records the time until the cancellation propagated", func() {
+			signalCh <- syscall.SIGTERM
+			Eventually(ctx.Done()).Should(BeClosed())
+			Eventually(func() float64 {
+				return metricValue(registry, "service_signal_to_cancel_seconds")
+			}).Should(Equal(1.0))
+		})
+		It("records nothing without signal", func() {
+			parentCancel()
+			Eventually(ctx.Done()).Should(BeClosed())
+			Consistently(func() float64 {
+				return metricValue(registry, "service_signal_to_cancel_seconds")
+			}, 20*time.Millisecond).Should(Equal(0.0))
+		})
+	})
+	Context("SignalFromContext", func() {
+		BeforeEach(func() {
+			ctx = service.ContextWithSignalCh(parentCtx, signalCh, service.NewOptions(), exit)
+		})
+		It("returns nil without signal", func() {
+			Expect(service.SignalFromContext(ctx)).To(BeNil())
+		})
+		It("returns nil if the parent context is canceled", func() {
+			parentCancel()
+			Eventually(ctx.Done()).Should(BeClosed())
+			Expect(service.SignalFromContext(ctx)).To(BeNil())
+		})
+		It("returns the received signal", func() {
+			signalCh <- syscall.SIGTERM
+			Eventually(ctx.Done()).Should(BeClosed())
+			Expect(service.SignalFromContext(ctx)).To(Equal(syscall.SIGTERM))
+		})
+		It("returns nil for a context without signal handler", func() {
+			Expect(service.SignalFromContext(context.Background())).To(BeNil())
+		})
+	})
+	Context("second signal", func() {
+		var fns []service.OptionsFn
+		BeforeEach(func() {
+			fns = nil
+		})
+		JustBeforeEach(func() {
+			ctx = service.ContextWithSignalCh(parentCtx, signalCh, service.NewOptions(fns...), exit)
+			signalCh <- syscall.SIGINT
+			Eventually(ctx.Done()).Should(BeClosed())
+		})
+		It("force exits with 130 on the same signal", func() {
+			signalCh <- syscall.SIGINT
+			Eventually(exitCodes).Should(Receive(Equal(130)))
+		})
+		It("ignores a different signal", func() {
+			signalCh <- syscall.SIGTERM
+			Consistently(exitCodes, 50*time.Millisecond).ShouldNot(Receive())
+		})
+		Context("WithForceExitCode", func() {
+			BeforeEach(func() {
+				fns = append(fns, service.WithForceExitCode(42))
+			})
+			It("force exits with the configured code", func() {
+				signalCh <- syscall.SIGINT
+				Eventually(exitCodes).Should(Receive(Equal(42)))
+			})
+		})
+	})
+	Context("WithCancelParentOnSignal", func() {
+		var parentCancelCalled chan struct{}
+		BeforeEach(func() {
+			parentCancelCalled = make(chan struct{})
+			ctx = service.ContextWithSignalCh(parentCtx, signalCh, service.NewOptions(
+				service.WithCancelParentOnSignal(func() {
+					close(parentCancelCalled)
+				}),
+			), exit)
+		})
+		It("cancels the parent on signal", func() {
+			signalCh <- syscall.SIGTERM
+			Eventually(ctx.Done()).Should(BeClosed())
+			Eventually(parentCancelCalled).Should(BeClosed())
+		})
+		It("does not cancel the parent if the parent context is canceled", func() {
+			parentCancel()
+			Eventually(ctx.Done()).Should(BeClosed())
+			Consistently(parentCancelCalled, 50*time.Millisecond).ShouldNot(BeClosed())
+		})
+	})
+})
+
+var _ = Describe("ContextWithSig", func() {
+	var ctx context.Context
+	var cancel context.CancelFunc
+	BeforeEach(func() {
+		ctx, cancel = context.WithCancel(context.Background())
+	})
+	AfterEach(func() {
+		cancel()
+	})
+	It("uses SIGINT and SIGTERM by default", func() {
+		Expect(service.NewOptions().Signals).To(ConsistOf(os.Interrupt, syscall.SIGINT, syscall.SIGTERM))
+	})
+	It("cancels the context on a custom signal", func() {
+		ctx = service.ContextWithSig(ctx, service.NewOptions(service.WithSignals(syscall.SIGUSR1)))
+		Expect(syscall.Kill(os.Getpid(), syscall.SIGUSR1)).To(Succeed())
+		Eventually(ctx.Done()).Should(BeClosed())
+	})
+})
@@ -0,0 +1,56 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package service
+
+import (
+	"context"
+
+	"github.com/bborbe/errors"
+	"github.com/bborbe/run"
+)
+
+// StartupSelfCheck verifies once after start that the service actually works,
+// e.g. its own HTTP port is reachable or configured directories are writable.
+type StartupSelfCheck func(ctx context.Context) error
+
+// runWithStartupSelfCheck runs fn and concurrently the self check.
+// onPassed is only called if the self check passes.
+// If it fails and shutdownOnFailure is set, fn gets canceled and the self check error is returned.
+func runWithStartupSelfCheck(
+	fn run.Func,
+	selfCheck StartupSelfCheck,
+	shutdownOnFailure bool,
+	onPassed func(),
+) run.Func {
+	return func(ctx context.Context) error {
+		ctx, cancel := context.WithCancelCause(ctx)
+		defer cancel(nil)
+
+		selfCheckErrCh := make(chan error, 1)
+		go func() {
+			defer close(selfCheckErrCh)
+			if err := selfCheck(ctx); err != nil {
+				LoggerFromContext(ctx).Warningf("startup self check failed: %v", err)
+				if shutdownOnFailure {
+					err = errors.Wrapf(ctx, err, "startup self check failed")
+					selfCheckErrCh <- err
+					cancel(err)
+				}
+				return
+			}
+			if LoggerFromContext(ctx).V(2) {
+				LoggerFromContext(ctx).Infof("startup self check passed")
+			}
+			onPassed()
+		}()
+
+		err := fn(ctx)
+		cancel(nil)
+		if selfCheckErr := <-selfCheckErrCh; selfCheckErr != nil {
+			return errors.Join(selfCheckErr, err)
+		}
+		return err
+	}
+}
@@ -0,0 +1,82 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package service_test
+
+import (
+	"context"
+	stderrors "errors"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/bborbe/service"
+)
+
+var _ = Describe("StartupSelfCheck", func() {
+	var ctx context.Context
+	var cancel context.CancelFunc
+	var criteria service.ReadinessCriteria
+	var selfCheckErr error
+	var shutdownOnFailure bool
+	var fn func(ctx context.Context) error
+	BeforeEach(func() {
+		ctx, cancel = context.WithTimeout(context.Background(), time.Second)
+		criteria = service.NewReadinessCriteria(service.ReadinessCriteriaOptions{})
+		selfCheckErr = nil
+		shutdownOnFailure = false
+		fn = func(ctx context.Context) error {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(100 * time.Millisecond):
+				return nil
+			}
+		}
+	})
+	AfterEach(func() {
+		cancel()
+	})
+	run := func() error {
+		return service.RunWithStartupSelfCheck(
+			fn,
+			func(ctx context.Context) error {
+				return selfCheckErr
+			},
+			shutdownOnFailure,
+			func() {
+				criteria.Started(time.Now())
+			},
+		)(ctx)
+	}
+	It("becomes ready if the self check passes", func() {
+		Expect(run()).To(BeNil())
+		Expect(criteria.Ready(ctx)).To(BeNil())
+	})
+	Context("failing self check", func() {
+		BeforeEach(func() {
+			selfCheckErr = stderrors.New("port not reachable")
+		})
+		It("never becomes ready", func() {
+			Expect(run()).To(BeNil())
+			Expect(criteria.Ready(ctx)).NotTo(BeNil())
+		})
+		Context("with shutdown on failure", func() {
+			BeforeEach(func() {
+				shutdownOnFailure = true
+				fn = func(ctx context.Context) error {
+					<-ctx.Done()
+					return nil
+				}
+			})
+			It("stops with the self check error", func() {
+				err := run()
+				Expect(err).NotTo(BeNil())
+				Expect(stderrors.Is(err, selfCheckErr)).To(BeTrue())
+				Expect(criteria.Ready(ctx)).NotTo(BeNil())
+			})
+		})
+	})
+})
@@ -0,0 +1,79 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package service
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/bborbe/errors"
+	"github.com/bborbe/run"
+)
+
+// StartupTimeoutError is returned by Main if the application did not become ready within the startup timeout.
+type StartupTimeoutError struct {
+	Timeout time.Duration
+}
+
+func (s *StartupTimeoutError) Error() string {
+	return fmt.Sprintf("application not ready within startup timeout %v", s.Timeout)
+}
+
+// newReadinessSignal returns a HealthState that closes Ready the first time it is set ready.
+func newReadinessSignal(healthState HealthState) *readinessSignal {
+	return &readinessSignal{
+		HealthState: healthState,
+		ready:       make(chan struct{}),
+	}
+}
+
+type readinessSignal struct {
+	HealthState
+	once  sync.Once
+	ready chan struct{}
+}
+
+func (r *readinessSignal) SetReady(ready bool) {
+	r.HealthState.SetReady(ready)
+	if ready {
+		r.once.Do(func() {
+			close(r.ready)
+		})
+	}
+}
+
+// Signaled is closed once the state was set ready.
+func (r *readinessSignal) Signaled() <-chan struct{} {
+	return r.ready
+}
+
+// runWithStartupTimeout cancels fn and returns a StartupTimeoutError if ready is not closed within the timeout.
+func runWithStartupTimeout(fn run.Func, ready <-chan struct{}, timeout time.Duration) run.Func {
+	return func(ctx context.Context) error {
+		ctx, cancel := context.WithCancelCause(ctx)
+		defer cancel(nil)
+
+		timer := ClockFromContext(ctx).NewTimer(timeout)
+		defer timer.Stop()
+		go func() {
+			select {
+			case <-ready:
+			case <-ctx.Done():
+			case <-timer.C():
+				cancel(&StartupTimeoutError{Timeout: timeout})
+			}
+		}()
+
+		err := fn(ctx)
+		var startupTimeoutErr *StartupTimeoutError
+		if stderrors.As(context.Cause(ctx), &startupTimeoutErr) {
+			return errors.Join(startupTimeoutErr, err)
+		}
+		return err
+	}
+}
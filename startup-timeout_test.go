@@ -0,0 +1,91 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package service_test
+
+import (
+	"context"
+	stderrors "errors"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/bborbe/service"
+	"github.com/bborbe/service/servicetest"
+)
+
+var _ = Describe("RunWithStartupTimeout", func() {
+	var ctx context.Context
+	var ready chan struct{}
+	BeforeEach(func() {
+		ctx = context.Background()
+		ready = make(chan struct{})
+	})
+	It("cancels the func if it is not ready in time", func() {
+		err := service.RunWithStartupTimeout(func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		}, ready, 10*time.Millisecond)(ctx)
+		var startupTimeoutErr *service.StartupTimeoutError
+		Expect(stderrors.As(err, &startupTimeoutErr)).To(BeTrue())
+		Expect(startupTimeoutErr.Timeout).To(Equal(10 * time.Millisecond))
+	})
+	It("keeps the func running once it is ready", func() {
+		err := service.RunWithStartupTimeout(func(ctx context.Context) error {
+			close(ready)
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(50 * time.Millisecond):
+				return nil
+			}
+		}, ready, 10*time.Millisecond)(ctx)
+		Expect(err).To(BeNil())
+	})
+})
+
+var _ = Describe("Main WithStartupTimeout", func() {
+	var transport servicetest.SentryTransport
+	var fns []service.OptionsFn
+	BeforeEach(func() {
+		transport = servicetest.NewSentryTransport()
+		fns = []service.OptionsFn{
+			service.WithoutTimezoneOverride(),
+			service.WithRegisterer(nil),
+			service.WithSentryTransport(transport),
+			service.WithStartupTimeout(20 * time.Millisecond),
+		}
+	})
+	It("reports an application that never becomes ready", func() {
+		app := &mainApplication{
+			RunFunc: func(ctx context.Context) error {
+				<-ctx.Done()
+				return nil
+			},
+		}
+		Expect(service.Main(context.Background(), app, &app.SentryDSN, nil, fns...)).To(Equal(service.ExitCodeFailure))
+		var messages []string
+		for _, event := range transport.Events() {
+			for _, exception := range event.Exception {
+				messages = append(messages, exception.Value)
+			}
+		}
+		Expect(messages).To(ContainElement("application not ready within startup timeout 20ms"))
+	})
+	It("runs an application that becomes ready", func() {
+		app := &mainApplication{
+			RunFunc: func(ctx context.Context) error {
+				service.HealthStateFromContext(ctx).SetReady(true)
+				select {
+				case <-ctx.Done():
+					return ctx.Err()
+				case <-time.After(50 * time.Millisecond):
+					return nil
+				}
+			},
+		}
+		Expect(service.Main(context.Background(), app, &app.SentryDSN, nil, fns...)).To(Equal(service.ExitCodeSuccess))
+	})
+})
@@ -0,0 +1,77 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package service
+
+import (
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Startup phases timed by Main and exported as label of gauge service_startup_phase_seconds.
+const (
+	StartupPhaseSetup     = "setup"
+	StartupPhaseArguments = "arguments"
+	StartupPhaseSentry    = "sentry"
+	StartupPhaseOnStart   = "on_start"
+	StartupPhaseRunning   = "running"
+)
+
+// StartupTiming is the duration of a single startup phase.
+type StartupTiming struct {
+	Phase    string
+	Duration time.Duration
+}
+
+func newStartupTimer(registerer prometheus.Registerer, now func() time.Time, logger Logger) *startupTimer {
+	return &startupTimer{
+		now:    now,
+		last:   now(),
+		logger: logger,
+		gauge: register(registerer, prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: metricsNamespace,
+			Name:      "startup_phase_seconds",
+			Help:      "Duration of each phase of the startup until the application runs.",
+		}, []string{"phase"})),
+	}
+}
+
+// startupTimer measures consecutive startup phases, each one ends with the call of Done.
+type startupTimer struct {
+	now     func() time.Time
+	logger  Logger
+	last    time.Time
+	gauge   *prometheus.GaugeVec
+	timings []StartupTiming
+}
+
+// Done ends the given phase, it began with the end of the previous one.
+func (s *startupTimer) Done(phase string) {
+	now := s.now()
+	duration := now.Sub(s.last)
+	s.last = now
+	s.timings = append(s.timings, StartupTiming{Phase: phase, Duration: duration})
+	s.gauge.WithLabelValues(phase).Set(duration.Seconds())
+}
+
+// Timings of all phases done so far.
+func (s *startupTimer) Timings() []StartupTiming {
+	return s.timings
+}
+
+// Log the summary of all phases done so far.
+func (s *startupTimer) Log() {
+	if !s.logger.V(1) {
+		return
+	}
+	var total time.Duration
+	parts := make([]string, len(s.timings))
+	for i, timing := range s.timings {
+		total += timing.Duration
+		parts[i] = timing.Phase + "=" + timing.Duration.String()
+	}
+	s.logger.Infof("startup took %v: %s", total, strings.Join(parts, " "))
+}
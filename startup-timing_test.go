@@ -0,0 +1,60 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package service_test
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/bborbe/service"
+	"github.com/bborbe/service/servicetest"
+)
+
+var _ = Describe("StartupTimer", func() {
+	var registry *prometheus.Registry
+	var now time.Time
+	BeforeEach(func() {
+		registry = prometheus.NewRegistry()
+		now = time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	})
+	It("times consecutive phases", func() {
+		timer := service.NewStartupTimer(registry, func() time.Time { return now }, service.NewGlogLogger())
+		now = now.Add(2 * time.Second)
+		timer.Done(service.StartupPhaseArguments)
+		now = now.Add(500 * time.Millisecond)
+		timer.Done(service.StartupPhaseSentry)
+		Expect(timer.Timings()).To(Equal([]service.StartupTiming{
+			{Phase: service.StartupPhaseArguments, Duration: 2 * time.Second},
+			{Phase: service.StartupPhaseSentry, Duration: 500 * time.Millisecond},
+		}))
+		Expect(metricValue(registry, "service_startup_phase_seconds")).To(Equal(2.5))
+	})
+	It("exports a gauge for each phase of Main", func() {
+		app := &mainApplication{
+			RunFunc: func(ctx context.Context) error {
+				return nil
+			},
+		}
+		Expect(service.Main(context.Background(), app, &app.SentryDSN, nil,
+			service.WithoutTimezoneOverride(),
+			service.WithRegisterer(registry),
+			service.WithSentryTransport(servicetest.NewSentryTransport()),
+			service.WithOnStart(func(ctx context.Context) error {
+				return nil
+			}),
+		)).To(Equal(service.ExitCodeSuccess))
+		Expect(metricLabels(registry, "service_startup_phase_seconds")).To(ConsistOf(
+			map[string]string{"phase": service.StartupPhaseSetup},
+			map[string]string{"phase": service.StartupPhaseArguments},
+			map[string]string{"phase": service.StartupPhaseSentry},
+			map[string]string{"phase": service.StartupPhaseOnStart},
+			map[string]string{"phase": service.StartupPhaseRunning},
+		))
+	})
+})
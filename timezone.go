@@ -0,0 +1,28 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package service
+
+import (
+	"time"
+)
+
+// setTimezone sets time.Local to loc, nil leaves it untouched.
+func setTimezone(loc *time.Location, logger Logger) {
+	setLocation(&time.Local, loc, logger)
+}
+
+// setLocation sets *local to loc, nil leaves it untouched.
+func setLocation(local **time.Location, loc *time.Location, logger Logger) {
+	if loc == nil {
+		if logger.V(2) {
+			logger.Infof("keep global timezone %s", *local)
+		}
+		return
+	}
+	*local = loc
+	if logger.V(2) {
+		logger.Infof("set global timezone to %s", loc)
+	}
+}
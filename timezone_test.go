@@ -0,0 +1,37 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package service_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/bborbe/service"
+)
+
+var _ = Describe("Timezone", func() {
+	// a local location instead of time.Local, which goroutines of other tests may read
+	var local *time.Location
+	var berlin *time.Location
+	BeforeEach(func() {
+		berlin = time.FixedZone("Berlin", 3600)
+		local = berlin
+	})
+	It("sets UTC by default", func() {
+		service.SetLocation(&local, service.NewOptions().Timezone, service.NewGlogLogger())
+		Expect(local).To(Equal(time.UTC))
+	})
+	It("sets the given timezone", func() {
+		tokyo := time.FixedZone("Tokyo", 9*3600)
+		service.SetLocation(&local, service.NewOptions(service.WithTimezone(tokyo)).Timezone, service.NewGlogLogger())
+		Expect(local).To(Equal(tokyo))
+	})
+	It("leaves the location untouched without override", func() {
+		service.SetLocation(&local, service.NewOptions(service.WithoutTimezoneOverride()).Timezone, service.NewGlogLogger())
+		Expect(local).To(Equal(berlin))
+	})
+})
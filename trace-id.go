@@ -0,0 +1,51 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package service
+
+import (
+	"context"
+
+	"github.com/bborbe/errors"
+	"github.com/bborbe/run"
+)
+
+// TraceIDKey is the key of the trace ID in the data of errors and the tags of captured exceptions.
+const TraceIDKey = "trace_id"
+
+type traceIDContextKey struct{}
+
+// WithTraceID returns a context carrying the given trace ID to correlate the logs and errors of funcs.
+// The service tags captured exceptions with the trace ID of its context.
+func WithTraceID(ctx context.Context, traceID string) context.Context {
+	return context.WithValue(ctx, traceIDContextKey{}, traceID)
+}
+
+// TraceIDFromContext returns the trace ID of the context or an empty string.
+func TraceIDFromContext(ctx context.Context) string {
+	traceID, _ := ctx.Value(traceIDContextKey{}).(string)
+	return traceID
+}
+
+// RunWithValues works like Run, but each func receives a context with the given values.
+// If the values contain a trace ID, see WithTraceID, errors of the funcs carry it as data.
+func RunWithValues(ctx context.Context, values map[any]any, funcs ...run.Func) error {
+	for key, value := range values {
+		ctx = context.WithValue(ctx, key, value)
+	}
+	traceID := TraceIDFromContext(ctx)
+	if traceID == "" {
+		return Run(ctx, funcs...)
+	}
+	traced := make([]run.Func, len(funcs))
+	for i, fn := range funcs {
+		traced[i] = func(ctx context.Context) error {
+			if err := fn(ctx); err != nil {
+				return errors.AddDataToError(err, map[string]string{TraceIDKey: traceID})
+			}
+			return nil
+		}
+	}
+	return Run(ctx, traced...)
+}
@@ -0,0 +1,74 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package service_test
+
+import (
+	"context"
+	stderrors "errors"
+	"sync"
+
+	"github.com/bborbe/errors"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/bborbe/service"
+)
+
+type valueKey struct{}
+
+var _ = Describe("RunWithValues", func() {
+	var ctx context.Context
+	BeforeEach(func() {
+		ctx = context.Background()
+	})
+	It("passes the values to every func", func() {
+		var mux sync.Mutex
+		var received []any
+		record := func(ctx context.Context) error {
+			mux.Lock()
+			defer mux.Unlock()
+			received = append(received, ctx.Value(valueKey{}))
+			return nil
+		}
+		Expect(service.RunWithValues(ctx, map[any]any{valueKey{}: "banana"}, record, record)).To(Succeed())
+		Expect(received).To(Equal([]any{"banana", "banana"}))
+	})
+	It("passes the trace ID to every func", func() {
+		var mux sync.Mutex
+		var received []string
+		record := func(ctx context.Context) error {
+			mux.Lock()
+			defer mux.Unlock()
+			received = append(received, service.TraceIDFromContext(ctx))
+			return nil
+		}
+		Expect(service.RunWithValues(service.WithTraceID(ctx, "deploy-42"), nil, record, record)).To(Succeed())
+		Expect(received).To(Equal([]string{"deploy-42", "deploy-42"}))
+	})
+	It("adds the trace ID to the data of errors", func() {
+		err := service.RunWithValues(service.WithTraceID(ctx, "deploy-42"), nil, func(ctx context.Context) error {
+			return stderrors.New("banana")
+		})
+		var hasData errors.HasData
+		Expect(stderrors.As(err, &hasData)).To(BeTrue())
+		Expect(hasData.Data()).To(HaveKeyWithValue(service.TraceIDKey, "deploy-42"))
+	})
+	It("returns errors unchanged without trace ID", func() {
+		err := service.RunWithValues(ctx, nil, func(ctx context.Context) error {
+			return stderrors.New("banana")
+		})
+		var hasData errors.HasData
+		Expect(stderrors.As(err, &hasData)).To(BeFalse())
+	})
+})
+
+var _ = Describe("TraceIDFromContext", func() {
+	It("returns an empty string without trace ID", func() {
+		Expect(service.TraceIDFromContext(context.Background())).To(BeEmpty())
+	})
+	It("returns the trace ID", func() {
+		Expect(service.TraceIDFromContext(service.WithTraceID(context.Background(), "deploy-42"))).To(Equal("deploy-42"))
+	})
+})
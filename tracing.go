@@ -0,0 +1,48 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package service
+
+import (
+	"context"
+	"time"
+)
+
+// DefaultTracingShutdownTimeout bounds the shutdown of the TracerProvider if no shutdown budget is configured.
+const DefaultTracingShutdownTimeout = 5 * time.Second
+
+// TracerProvider is the part of a tracer provider Main manages, e.g. *trace.TracerProvider of the OpenTelemetry SDK.
+type TracerProvider interface {
+	// Shutdown flushes pending spans and stops the exporter.
+	Shutdown(ctx context.Context) error
+}
+
+// TracingSetup creates a TracerProvider exporting to the given endpoint and installs it globally,
+// e.g. with an OTLP exporter and otel.SetTracerProvider. The framework does not depend on an SDK.
+type TracingSetup func(ctx context.Context, endpoint string) (TracerProvider, error)
+
+type tracerProviderContextKey struct{}
+
+// ContextWithTracerProvider returns a context carrying the given TracerProvider.
+func ContextWithTracerProvider(ctx context.Context, tracerProvider TracerProvider) context.Context {
+	return context.WithValue(ctx, tracerProviderContextKey{}, tracerProvider)
+}
+
+// TracerProviderFromContext returns the TracerProvider Main set up or nil if tracing is disabled.
+func TracerProviderFromContext(ctx context.Context) TracerProvider {
+	tracerProvider, _ := ctx.Value(tracerProviderContextKey{}).(TracerProvider)
+	return tracerProvider
+}
+
+// setupTracing calls the setup if an endpoint is configured. The returned shutdown func is never nil.
+func setupTracing(ctx context.Context, endpoint string, setup TracingSetup) (TracerProvider, func(ctx context.Context) error, error) {
+	if endpoint == "" || setup == nil {
+		return nil, func(ctx context.Context) error { return nil }, nil
+	}
+	tracerProvider, err := setup(ctx, endpoint)
+	if err != nil {
+		return nil, nil, err
+	}
+	return tracerProvider, tracerProvider.Shutdown, nil
+}
@@ -0,0 +1,80 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package service_test
+
+import (
+	"context"
+	stderrors "errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/bborbe/service"
+	"github.com/bborbe/service/servicetest"
+)
+
+type recordingTracerProvider struct {
+	calls *[]string
+}
+
+func (r *recordingTracerProvider) Shutdown(ctx context.Context) error {
+	*r.calls = append(*r.calls, "shutdown")
+	return nil
+}
+
+var _ = Describe("Main WithTracing", func() {
+	var ctx context.Context
+	var app *mainApplication
+	var calls []string
+	var endpoints []string
+	var tracerProvider *recordingTracerProvider
+	var setupErr error
+	var fns []service.OptionsFn
+	BeforeEach(func() {
+		ctx = context.Background()
+		calls = nil
+		endpoints = nil
+		setupErr = nil
+		tracerProvider = &recordingTracerProvider{calls: &calls}
+		app = &mainApplication{
+			RunFunc: func(ctx context.Context) error {
+				if service.TracerProviderFromContext(ctx) == tracerProvider {
+					calls = append(calls, "run with provider")
+				}
+				return nil
+			},
+		}
+		fns = []service.OptionsFn{
+			service.WithoutTimezoneOverride(),
+			service.WithRegisterer(nil),
+			service.WithSentryTransport(servicetest.NewSentryTransport()),
+		}
+	})
+	setup := func(ctx context.Context, endpoint string) (service.TracerProvider, error) {
+		endpoints = append(endpoints, endpoint)
+		if setupErr != nil {
+			return nil, setupErr
+		}
+		return tracerProvider, nil
+	}
+	It("passes the provider to the application and shuts it down on exit", func() {
+		fns = append(fns, service.WithTracing("otel-collector:4317", setup))
+		Expect(service.Main(ctx, app, &app.SentryDSN, nil, fns...)).To(Equal(service.ExitCodeSuccess))
+		Expect(endpoints).To(Equal([]string{"otel-collector:4317"}))
+		Expect(calls).To(Equal([]string{"run with provider", "shutdown"}))
+	})
+	It("keeps tracing disabled without endpoint", func() {
+		fns = append(fns, service.WithTracing("", setup))
+		Expect(service.Main(ctx, app, &app.SentryDSN, nil, fns...)).To(Equal(service.ExitCodeSuccess))
+		Expect(endpoints).To(BeEmpty())
+		Expect(calls).To(BeEmpty())
+	})
+	It("does not start the application if the setup fails", func() {
+		setupErr = stderrors.New("banana")
+		fns = append(fns, service.WithTracing("otel-collector:4317", setup))
+		Expect(service.Main(ctx, app, &app.SentryDSN, nil, fns...)).To(Equal(service.ExitCodeTracingSetup))
+		Expect(calls).To(BeEmpty())
+	})
+})
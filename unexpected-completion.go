@@ -0,0 +1,45 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package service
+
+import (
+	"context"
+
+	"github.com/bborbe/run"
+)
+
+// UnexpectedCompletionHandler is called with the name of a func of Run that returned nil
+// while its context was still alive, like a server that quietly stopped serving.
+// Funcs of Run are named by their index, like "func 0", funcs of RunNamed by their Name.
+type UnexpectedCompletionHandler func(name string)
+
+type unexpectedCompletionHandlerContextKey struct{}
+
+// ContextWithUnexpectedCompletionHandler returns a context that lets Run report unexpected completions to the handler.
+func ContextWithUnexpectedCompletionHandler(ctx context.Context, handler UnexpectedCompletionHandler) context.Context {
+	return context.WithValue(ctx, unexpectedCompletionHandlerContextKey{}, handler)
+}
+
+// UnexpectedCompletionHandlerFromContext returns the UnexpectedCompletionHandler of the context or nil.
+func UnexpectedCompletionHandlerFromContext(ctx context.Context) UnexpectedCompletionHandler {
+	handler, _ := ctx.Value(unexpectedCompletionHandlerContextKey{}).(UnexpectedCompletionHandler)
+	return handler
+}
+
+func reportUnexpectedCompletions(handler UnexpectedCompletionHandler, names []string, funcs []run.Func) []run.Func {
+	result := make([]run.Func, len(funcs))
+	for i, fn := range funcs {
+		name := names[i]
+		result[i] = func(ctx context.Context) error {
+			err := fn(ctx)
+			if err == nil && ctx.Err() == nil {
+				LoggerFromContext(ctx).Warningf("%s completed while the context is alive", name)
+				handler(name)
+			}
+			return err
+		}
+	}
+	return result
+}
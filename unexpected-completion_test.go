@@ -0,0 +1,73 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package service_test
+
+import (
+	"context"
+	stderrors "errors"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/bborbe/service"
+)
+
+var _ = Describe("UnexpectedCompletionHandler", func() {
+	var ctx context.Context
+	var cancel context.CancelFunc
+	var names chan string
+	BeforeEach(func() {
+		ctx, cancel = context.WithTimeout(context.Background(), time.Second)
+		names = make(chan string, 10)
+		ctx = service.ContextWithUnexpectedCompletionHandler(ctx, func(name string) {
+			names <- name
+		})
+	})
+	AfterEach(func() {
+		cancel()
+	})
+	waitForCancel := func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+	It("reports a func returning nil while the context is alive", func() {
+		Expect(service.Run(ctx, waitForCancel, func(ctx context.Context) error {
+			return nil
+		})).To(BeNil())
+		Expect(names).To(Receive(Equal("func 1")))
+		Expect(names).NotTo(Receive())
+	})
+	It("reports the name of a NamedFunc", func() {
+		Expect(service.RunNamed(ctx,
+			service.NamedFunc{Name: "http", Func: func(ctx context.Context) error {
+				return nil
+			}},
+			service.NamedFunc{Name: "consumer", Func: waitForCancel},
+		)).To(BeNil())
+		Expect(names).To(Receive(Equal("http")))
+		Expect(names).NotTo(Receive())
+	})
+	It("does not report a failing func", func() {
+		Expect(service.Run(ctx, func(ctx context.Context) error {
+			return stderrors.New("banana")
+		})).NotTo(BeNil())
+		Expect(names).NotTo(Receive())
+	})
+	It("does not report funcs returning after the cancel", func() {
+		go func() {
+			time.Sleep(10 * time.Millisecond)
+			cancel()
+		}()
+		Expect(service.Run(ctx, func(ctx context.Context) error {
+			<-ctx.Done()
+			return nil
+		})).To(BeNil())
+		Expect(names).NotTo(Receive())
+	})
+	It("has no handler in an empty context", func() {
+		Expect(service.UnexpectedCompletionHandlerFromContext(context.Background())).To(BeNil())
+	})
+})
@@ -0,0 +1,102 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package service
+
+import (
+	"context"
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+
+	"github.com/bborbe/errors"
+)
+
+// FieldErrorReasonRequired is the reason of a FieldError for a required field without value.
+const FieldErrorReasonRequired = "required"
+
+// FieldError describes why a field of the application failed the validation.
+// It never contains the provided value, which might be a secret.
+type FieldError struct {
+	Field  string `json:"field"`
+	Arg    string `json:"arg,omitempty"`
+	Env    string `json:"env,omitempty"`
+	Reason string `json:"reason"`
+}
+
+// ValidationErrors is returned by the parsing of Main if fields of the application are invalid.
+type ValidationErrors []FieldError
+
+// Error names the arg and env to define, like the error of argument.ValidateRequired.
+func (f FieldError) Error() string {
+	var defines []string
+	if f.Arg != "" {
+		defines = append(defines, fmt.Sprintf("define parameter %s", f.Arg))
+	}
+	if f.Env != "" {
+		defines = append(defines, fmt.Sprintf("define env %s", f.Env))
+	}
+	message := fmt.Sprintf("field %s is %s", f.Field, f.Reason)
+	if f.Reason == FieldErrorReasonRequired {
+		message = "Required field empty"
+	}
+	if len(defines) == 0 {
+		return message
+	}
+	return fmt.Sprintf("%s, %s", message, strings.Join(defines, " or "))
+}
+
+func (v ValidationErrors) Error() string {
+	messages := make([]string, len(v))
+	for i, fieldError := range v {
+		messages[i] = fieldError.Error()
+	}
+	return strings.Join(messages, "; ")
+}
+
+// validateRequired works like argument.ValidateRequired, but returns ValidationErrors
+// with all required fields that are empty instead of only the first one.
+func validateRequired(ctx context.Context, data interface{}) error {
+	value := reflect.ValueOf(data).Elem()
+	var validationErrors ValidationErrors
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Type().Field(i)
+		if field.Tag.Get("required") != "true" {
+			continue
+		}
+		switch field.Type.Kind() {
+		case reflect.Bool:
+			continue
+		case reflect.String, reflect.Int, reflect.Int32, reflect.Int64, reflect.Uint, reflect.Uint64, reflect.Float64, reflect.Ptr:
+		default:
+			return errors.Errorf(ctx, "field %s with type %s is unsupported", field.Name, field.Type)
+		}
+		if !value.Field(i).IsZero() {
+			continue
+		}
+		validationErrors = append(validationErrors, FieldError{
+			Field:  field.Name,
+			Arg:    field.Tag.Get("arg"),
+			Env:    field.Tag.Get("env"),
+			Reason: FieldErrorReasonRequired,
+		})
+	}
+	if len(validationErrors) > 0 {
+		return validationErrors
+	}
+	return nil
+}
+
+// writeValidationErrors writes the field errors of err as JSON array.
+// Other errors are written as a single entry without field, their message might contain a secret.
+func writeValidationErrors(writer io.Writer, err error) error {
+	var validationErrors ValidationErrors
+	if !stderrors.As(err, &validationErrors) {
+		validationErrors = ValidationErrors{{Reason: "invalid arguments"}}
+	}
+	return json.NewEncoder(writer).Encode(validationErrors)
+}
@@ -0,0 +1,114 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package service_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	stderrors "errors"
+	"time"
+
+	libsentry "github.com/bborbe/sentry"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/bborbe/service"
+)
+
+type validationApplication struct {
+	SentryDSN string        `required:"false" arg:"sentry-dsn" usage:"SentryDSN"`
+	Name      string        `required:"true" arg:"name" env:"NAME" usage:"name of the banana"`
+	Password  string        `required:"true" arg:"password" display:"hidden" usage:"password"`
+	Port      int           `required:"true" arg:"port" usage:"port"`
+	Timeout   time.Duration `required:"true" arg:"timeout" default:"1s" usage:"timeout"`
+	Debug     bool          `required:"true" arg:"debug" usage:"debug"`
+}
+
+func (v *validationApplication) Run(ctx context.Context, sentryClient libsentry.Client) error {
+	return nil
+}
+
+var _ = Describe("WithValidationErrorJSON", func() {
+	var buf *bytes.Buffer
+	BeforeEach(func() {
+		buf = &bytes.Buffer{}
+	})
+	It("writes the field errors of all missing fields", func() {
+		app := &validationApplication{}
+		Expect(service.MainWithArgs(
+			context.Background(),
+			app,
+			&app.SentryDSN,
+			nil,
+			[]string{"-password=secret"},
+			service.WithoutTimezoneOverride(),
+			service.WithRegisterer(nil),
+			service.WithValidationErrorJSON(buf),
+		)).To(Equal(service.ExitCodeParseArguments))
+
+		var fieldErrors []map[string]string
+		Expect(json.Unmarshal(buf.Bytes(), &fieldErrors)).To(Succeed())
+		Expect(fieldErrors).To(Equal([]map[string]string{
+			{"field": "Name", "arg": "name", "env": "NAME", "reason": "required"},
+			{"field": "Port", "arg": "port", "reason": "required"},
+		}))
+	})
+	It("writes nothing if the arguments are valid", func() {
+		app := &validationApplication{}
+		Expect(service.MainWithArgs(
+			context.Background(),
+			app,
+			nil,
+			nil,
+			[]string{"-name=banana", "-password=secret", "-port=8080"},
+			service.WithoutTimezoneOverride(),
+			service.WithRegisterer(nil),
+			service.WithValidateOnly(),
+			service.WithValidationErrorJSON(buf),
+		)).To(Equal(service.ExitCodeSuccess))
+		Expect(buf.Len()).To(Equal(0))
+	})
+	It("does not include the value of an unparsable argument", func() {
+		app := &validationApplication{}
+		Expect(service.MainWithArgs(
+			context.Background(),
+			app,
+			&app.SentryDSN,
+			nil,
+			[]string{"-port=secret"},
+			service.WithoutTimezoneOverride(),
+			service.WithRegisterer(nil),
+			service.WithValidationErrorJSON(buf),
+		)).To(Equal(service.ExitCodeParseArguments))
+		Expect(buf.String()).To(Equal(`[{"field":"","reason":"invalid arguments"}]` + "\n"))
+	})
+})
+
+var _ = Describe("ValidateRequired", func() {
+	It("returns ValidationErrors", func() {
+		err := service.ValidateRequired(context.Background(), &validationApplication{})
+		var validationErrors service.ValidationErrors
+		Expect(stderrors.As(err, &validationErrors)).To(BeTrue())
+		Expect(validationErrors).To(HaveLen(4))
+		Expect(err.Error()).To(Equal("Required field empty, define parameter name or define env NAME; " +
+			"Required field empty, define parameter password; " +
+			"Required field empty, define parameter port; " +
+			"Required field empty, define parameter timeout"))
+	})
+	It("returns nil if all required fields are set", func() {
+		Expect(service.ValidateRequired(context.Background(), &validationApplication{
+			Name:     "banana",
+			Password: "secret",
+			Port:     8080,
+			Timeout:  time.Second,
+		})).To(Succeed())
+	})
+	It("returns an error for unsupported types", func() {
+		Expect(service.ValidateRequired(context.Background(), &struct {
+			Names []string `required:"true"`
+		}{})).NotTo(Succeed())
+	})
+})